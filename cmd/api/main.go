@@ -2,22 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 
 	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/db"
 	"github.com/howallet/howallet/internal/handler"
+	"github.com/howallet/howallet/internal/metrics"
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
 	"github.com/howallet/howallet/internal/repository/postgres"
 	"github.com/howallet/howallet/internal/router"
 	"github.com/howallet/howallet/internal/service"
+	"github.com/howallet/howallet/internal/tracing"
+	"github.com/howallet/howallet/migrations"
 )
 
 func main() {
@@ -32,10 +41,29 @@ func main() {
 		logger.Error("failed to load config", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	logger.Info("effective configuration", slog.Any("config", cfg.Redacted()))
 
-	// Database connection pool
 	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, cfg.DB.DSN())
+	sigCtx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// Tracing (optional; no-op unless an OTLP endpoint is configured)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Database connection pool
+	poolCfg, err := pgxpool.ParseConfig(cfg.DB.DSN())
+	if err != nil {
+		logger.Error("invalid database config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled() {
+		poolCfg.ConnConfig.Tracer = db.QueryTracer{}
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		logger.Error("failed to connect to database", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -48,26 +76,124 @@ func main() {
 	}
 	logger.Info("connected to database")
 
+	// Schema: apply or wait for migrations rather than failing every query
+	schemaVersion, err := db.LatestMigration(migrations.FS)
+	if err != nil {
+		logger.Error("failed to read migrations", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if cfg.DB.MigrateOnStart {
+		n, err := db.Migrate(ctx, pool, migrations.FS)
+		if err != nil {
+			logger.Error("failed to migrate database", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		logger.Info("database migrated", slog.Int("applied", n), slog.Uint64("version", schemaVersion))
+	}
+	if !waitForSchema(sigCtx, logger, pool, schemaVersion) {
+		os.Exit(1)
+	}
+
 	// Repository layer
 	repos := postgres.New(pool)
 
 	// Services (repository-based)
-	emailSvc := service.NewEmailService(&cfg.SMTP)
-	authSvc := service.NewAuthService(repos, &cfg.JWT)
-	hhSvc := service.NewHouseholdService(repos, emailSvc, cfg.Frontend.URL)
-	accSvc := service.NewAccountService(repos.Accounts)
-	txnSvc := service.NewTransactionService(repos)
-	exportSvc := service.NewExportService(repos.Transactions)
+	mailer := service.NewMailer(&cfg.SMTP, logger)
+	webhooks := service.NewWebhookDispatcher(repos, logger)
+	notificationSvc := service.NewNotificationService(repos, cfg.Pagination, logger, cfg.Transaction.MaxAmount)
+	alertSvc := service.NewAlertService(repos, webhooks, logger)
+	authSvc := service.NewAuthService(repos, &cfg.JWT, cfg.Password, cfg.Household)
+	hhSvc := service.NewHouseholdService(repos, mailer, cfg.Frontend.URL, cfg.Invitation.TTL, webhooks, notificationSvc, cfg.Pagination, cfg.Household.DefaultCurrency, cfg.Transaction.MaxAmount, logger)
+	accSvc := service.NewAccountService(repos, cfg.Transaction.MaxAmount)
+	txnSvc := service.NewTransactionService(repos, webhooks, notificationSvc, alertSvc, cfg.Transaction, cfg.Pagination)
+	catSvc := service.NewCategoryService(repos.Categories)
+	budgetSvc := service.NewBudgetService(repos, cfg.Transaction.MaxAmount)
+	exportSvc := service.NewExportService(repos.Transactions, repos.Households, repos.Accounts)
+	reportSvc := service.NewReportService(repos)
+	searchSvc := service.NewSearchService(repos)
+	auditSvc := service.NewAuditService(repos, cfg.Audit.UndoWindow)
+	onboardingSvc := service.NewOnboardingService(repos, webhooks, cfg.Transaction)
+	webhookSvc := service.NewWebhookService(repos.Webhooks, cfg.Pagination)
+	templateSvc := service.NewTemplateService(repos, txnSvc)
+
+	// Background jobs (stopped on shutdown, after the server has drained)
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	janitor := service.NewJanitor(repos, cfg.Janitor.Interval, cfg.JWT.RefreshIdleTTL, logger)
+	jobs.Go(func() { janitor.Run(jobsCtx) })
+	poster := service.NewPoster(repos, webhooks, alertSvc, cfg.Transaction.PostInterval, logger)
+	jobs.Go(func() { poster.Run(jobsCtx) })
+	jobs.Go(func() { webhooks.Run(jobsCtx) })
 
 	// Handlers
 	authH := handler.NewAuthHandler(authSvc)
 	hhH := handler.NewHouseholdHandler(hhSvc)
 	accH := handler.NewAccountHandler(accSvc)
 	txnH := handler.NewTransactionHandler(txnSvc)
-	expH := handler.NewExportHandler(exportSvc)
+	catH := handler.NewCategoryHandler(catSvc)
+	expH := handler.NewExportHandler(exportSvc, logger)
+	reportH := handler.NewReportHandler(reportSvc)
+	searchH := handler.NewSearchHandler(searchSvc)
+	notificationH := handler.NewNotificationHandler(notificationSvc)
+	budgetH := handler.NewBudgetHandler(budgetSvc)
+	auditH := handler.NewAuditHandler(auditSvc)
+	onboardingH := handler.NewOnboardingHandler(onboardingSvc)
+	metaH := handler.NewMetaHandler(model.Meta{EmailEnabled: cfg.SMTP.Enabled()})
+	webhookH := handler.NewWebhookHandler(webhookSvc)
+	templateH := handler.NewTemplateHandler(templateSvc)
+
+	// Maintenance mode (reloaded from .env / environment on SIGHUP)
+	maintenance := middleware.NewMaintenance(cfg.Maintenance.Mode, cfg.Maintenance.RetryAfter)
+	go func() {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		for range hupCh {
+			_ = godotenv.Overload()
+			mode, err := config.ParseMaintenanceMode(os.Getenv("MAINTENANCE_MODE"))
+			if err != nil {
+				logger.Error("maintenance mode not reloaded", slog.String("error", err.Error()))
+				continue
+			}
+			maintenance.SetMode(mode)
+			logger.Info("maintenance mode reloaded", slog.String("mode", string(mode)))
+		}
+	}()
+
+	// Metrics (optional, served on their own port)
+	var httpMetrics *middleware.Metrics
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled {
+		reg := metrics.NewRegistry(pool)
+		httpMetrics = middleware.NewMetrics(reg)
+		metricsSrv = &http.Server{
+			Addr:         cfg.Metrics.Addr,
+			Handler:      metrics.Handler(reg),
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info(fmt.Sprintf("metrics listening on %s", cfg.Metrics.Addr))
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("metrics server error", slog.String("error", err.Error()))
+			}
+		}()
+	}
 
 	// Router (membership check enforced in HouseholdCtx middleware)
-	mux := router.New(cfg, logger, authH, hhH, accH, txnH, expH, hhSvc.CheckMembership)
+	checkMembership := func(ctx context.Context, householdID, userID uuid.UUID) (model.HouseholdRole, error) {
+		role, err := hhSvc.CheckMembership(ctx, householdID, userID)
+		switch {
+		case errors.Is(err, service.ErrNotMember):
+			return "", middleware.ErrNotMember
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			return "", middleware.ErrHouseholdNotFound
+		}
+		return role, err
+	}
+	mux := router.New(cfg, logger, authH, hhH, accH, txnH, catH, expH, auditH, onboardingH, metaH, webhookH, templateH, reportH, notificationH, budgetH, searchH, checkMembership, func(ctx context.Context) error {
+		return db.CheckSchema(ctx, pool, schemaVersion)
+	}, maintenance, httpMetrics)
 
 	// HTTP Server
 	srv := &http.Server{
@@ -78,26 +204,69 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Graceful shutdown
+	serveErr := make(chan error, 1)
 	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-
-		logger.Info("shutting down server...")
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			logger.Error("server shutdown error", slog.String("error", err.Error()))
-		}
+		logger.Info(fmt.Sprintf("hoWallet API listening on %s", cfg.API.Addr()))
+		serveErr <- srv.ListenAndServe()
 	}()
 
-	logger.Info(fmt.Sprintf("hoWallet API listening on %s", cfg.API.Addr()))
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	exitCode := 0
+	select {
+	case err := <-serveErr:
 		logger.Error("server error", slog.String("error", err.Error()))
-		os.Exit(1)
+		exitCode = 1
+	case <-sigCtx.Done():
+	}
+
+	// Graceful shutdown: stop accepting and drain in-flight requests while the
+	// pool is still open, then stop background jobs, and only then let the
+	// deferred pool.Close run.
+	logger.Info("shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.API.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("server shutdown error", slog.String("error", err.Error()))
+	}
+	if metricsSrv != nil {
+		_ = metricsSrv.Shutdown(shutdownCtx)
+	}
+	stopJobs()
+	jobs.Wait()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("tracing shutdown error", slog.String("error", err.Error()))
 	}
 
 	logger.Info("server stopped")
+	if exitCode != 0 {
+		pool.Close()
+		os.Exit(exitCode)
+	}
+}
+
+// schemaPollInterval is how often startup re-checks a database that isn't
+// migrated yet.
+const schemaPollInterval = 5 * time.Second
+
+// waitForSchema blocks until the database is migrated to version. It gives up,
+// returning false, on a dirty schema, which needs an operator, or when ctx ends.
+func waitForSchema(ctx context.Context, logger *slog.Logger, pool *pgxpool.Pool, version uint64) bool {
+	for {
+		err := db.CheckSchema(ctx, pool, version)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, db.ErrSchemaOutdated) {
+			logger.Error("database schema check failed", slog.String("error", err.Error()))
+			return false
+		}
+		logger.Warn("waiting for database migrations (run make migrate-up or set DB_MIGRATE_ON_START)",
+			slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(schemaPollInterval):
+		}
+	}
 }
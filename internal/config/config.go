@@ -1,19 +1,66 @@
 package config
 
 import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Config holds all application configuration loaded from environment variables.
 type Config struct {
-	DB       DBConfig
-	API      APIConfig
-	JWT      JWTConfig
-	SMTP     SMTPConfig
-	Frontend FrontendConfig
-	Env      string
+	DB          DBConfig
+	API         APIConfig
+	JWT         JWTConfig
+	SMTP        SMTPConfig
+	Frontend    FrontendConfig
+	Invitation  InvitationConfig
+	Password    PasswordConfig
+	Audit       AuditConfig
+	Transaction TransactionConfig
+	Pagination  PaginationConfig
+	Household   HouseholdConfig
+	Janitor     JanitorConfig
+	Maintenance MaintenanceConfig
+	Metrics     MetricsConfig
+	Tracing     TracingConfig
+	Env         string
+}
+
+// MaintenanceMode controls which requests are served during deploys and migrations.
+type MaintenanceMode string
+
+const (
+	MaintenanceOff      MaintenanceMode = "off"
+	MaintenanceReadOnly MaintenanceMode = "read-only"
+	MaintenanceFull     MaintenanceMode = "full"
+)
+
+type MaintenanceConfig struct {
+	Mode MaintenanceMode
+	// RetryAfter is advertised to clients rejected during maintenance.
+	RetryAfter time.Duration
+}
+
+// ParseMaintenanceMode parses a MAINTENANCE_MODE value; empty means off.
+func ParseMaintenanceMode(v string) (MaintenanceMode, error) {
+	switch MaintenanceMode(v) {
+	case "", MaintenanceOff:
+		return MaintenanceOff, nil
+	case MaintenanceReadOnly, MaintenanceFull:
+		return MaintenanceMode(v), nil
+	}
+	return "", fmt.Errorf("invalid MAINTENANCE_MODE %q (want off, read-only or full)", v)
 }
 
 type DBConfig struct {
@@ -23,6 +70,8 @@ type DBConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// MigrateOnStart applies pending migrations before the API starts serving.
+	MigrateOnStart bool
 }
 
 func (d DBConfig) DSN() string {
@@ -35,20 +84,197 @@ func (d DBConfig) DSN() string {
 type APIConfig struct {
 	Port string
 	Host string
+	// ShutdownTimeout bounds how long in-flight requests may drain on shutdown.
+	ShutdownTimeout time.Duration
 }
 
 func (a APIConfig) Addr() string {
 	return fmt.Sprintf("%s:%s", a.Host, a.Port)
 }
 
+// JWTAlgorithm names how access tokens are signed.
+type JWTAlgorithm string
+
+const (
+	// JWTAlgorithmHS256 signs with a shared secret.
+	JWTAlgorithmHS256 JWTAlgorithm = "HS256"
+	// JWTAlgorithmRS256 signs with a private RSA key, so other services can
+	// verify tokens holding only the public key.
+	JWTAlgorithmRS256 JWTAlgorithm = "RS256"
+)
+
 type JWTConfig struct {
-	Secret     string
+	// Algorithm is the only alg accepted on tokens; the others are rejected.
+	Algorithm JWTAlgorithm
+	// Secret signs new access tokens with HS256.
+	Secret string
+	// PreviousSecret is still accepted for verification while tokens signed
+	// before a rotation expire; empty outside a rotation.
+	PreviousSecret string
+	// PrivateKey signs new access tokens with RS256, and PublicKey (by default
+	// PrivateKey's) verifies them. PreviousPublicKey plays PreviousSecret's role.
+	PrivateKey        *rsa.PrivateKey
+	PublicKey         *rsa.PublicKey
+	PreviousPublicKey *rsa.PublicKey
+	// Issuer and Audience are put in the iss and aud claims and required on
+	// every token, so tokens minted by another service sharing the secret fail.
+	Issuer     string
+	Audience   string
 	AccessTTL  time.Duration
 	RefreshTTL time.Duration
+	// RefreshIdleTTL expires refresh tokens unused for this long; 0 disables it.
+	RefreshIdleTTL time.Duration
+}
+
+// KeyID identifies a signing secret in a token's "kid" header without revealing it.
+func KeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+// publicKeyID is KeyID for an RSA public key.
+func publicKeyID(pub *rsa.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:4])
+}
+
+// SigningKey returns the key new access tokens are signed with and its kid.
+func (j JWTConfig) SigningKey() (key any, kid string) {
+	if j.Algorithm == JWTAlgorithmRS256 {
+		return j.PrivateKey, publicKeyID(&j.PrivateKey.PublicKey)
+	}
+	return []byte(j.Secret), KeyID(j.Secret)
+}
+
+// VerificationKey returns the key matching a token's kid. Tokens without a
+// kid predate key IDs and were signed with the current secret or key.
+func (j JWTConfig) VerificationKey(kid string) (any, bool) {
+	if j.Algorithm == JWTAlgorithmRS256 {
+		switch {
+		case kid == "" || kid == publicKeyID(j.PublicKey):
+			return j.PublicKey, true
+		case j.PreviousPublicKey != nil && kid == publicKeyID(j.PreviousPublicKey):
+			return j.PreviousPublicKey, true
+		}
+		return nil, false
+	}
+	switch {
+	case kid == "" || kid == KeyID(j.Secret):
+		return []byte(j.Secret), true
+	case j.PreviousSecret != "" && kid == KeyID(j.PreviousSecret):
+		return []byte(j.PreviousSecret), true
+	}
+	return nil, false
 }
 
 type FrontendConfig struct {
+	// URL is the primary frontend address, used to build links in emails.
 	URL string
+	// URLs are the origins allowed by CORS (staging, previews, ...).
+	URLs []string
+}
+
+type InvitationConfig struct {
+	// TTL is how long an invitation link stays valid.
+	TTL time.Duration
+}
+
+// MaxPasswordBytes is the longest password bcrypt accepts.
+const MaxPasswordBytes = 72
+
+// PasswordHash names the algorithm new password hashes are made with.
+type PasswordHash string
+
+const (
+	PasswordHashBcrypt   PasswordHash = "bcrypt"
+	PasswordHashArgon2id PasswordHash = "argon2id"
+)
+
+type PasswordConfig struct {
+	// Hash is the algorithm for new hashes. Hashes made with the other one keep
+	// verifying and are replaced at the user's next login.
+	Hash PasswordHash
+	// BcryptCost is the work factor for password hashes; lower it on slow hardware.
+	BcryptCost int
+	// Argon2id parameters: passes over memory, memory in KiB and lanes.
+	Argon2Time      uint32
+	Argon2MemoryKiB uint32
+	Argon2Threads   uint8
+	// MinLength is the shortest accepted password, in characters.
+	MinLength int
+	// Optional character classes every new password must contain.
+	RequireMixedCase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+type AuditConfig struct {
+	// UndoWindow is how far back a user's own actions can be undone.
+	UndoWindow time.Duration
+}
+
+type TransactionConfig struct {
+	// MaxFuture is how far ahead of now transacted_at may be; 0 disables the check.
+	MaxFuture time.Duration
+	// PostInterval is how often scheduled transactions that have come due are posted.
+	PostInterval time.Duration
+	// MaxTags caps the distinct tags on one transaction.
+	MaxTags int
+	// MaxTagLength caps the length of a tag, in characters.
+	MaxTagLength int
+	// MaxAmount bounds the magnitude of amounts, and of account balances,
+	// daily limits, budgets and notification thresholds.
+	MaxAmount decimal.Decimal
+}
+
+type PaginationConfig struct {
+	// DefaultLimit is used when a list request gives no limit.
+	DefaultLimit int32
+	// MaxLimit caps any requested limit.
+	MaxLimit int32
+}
+
+// Limit returns the effective page size for a requested limit: the default when
+// none (or a non-positive one) was given, clamped to MaxLimit.
+func (p PaginationConfig) Limit(requested int32) int32 {
+	if requested <= 0 {
+		requested = p.DefaultLimit
+	}
+	return min(requested, p.MaxLimit)
+}
+
+type HouseholdConfig struct {
+	// DefaultCurrency is the default_currency of households created without one.
+	DefaultCurrency string
+	// SeedDefaultAccounts gives the household created at registration an empty
+	// "Cash" and "Card" account.
+	SeedDefaultAccounts bool
+}
+
+type JanitorConfig struct {
+	// Interval between cleanup runs of expired refresh tokens and invitations.
+	Interval time.Duration
+}
+
+type MetricsConfig struct {
+	// Enabled exposes Prometheus metrics on Addr, a separate port kept off the public API.
+	Enabled bool
+	Addr    string
+}
+
+// TracingConfig configures OpenTelemetry export. Tracing is off unless an OTLP
+// endpoint is set.
+type TracingConfig struct {
+	OTLPEndpoint string
+	ServiceName  string
+}
+
+func (t TracingConfig) Enabled() bool {
+	return t.OTLPEndpoint != ""
 }
 
 type SMTPConfig struct {
@@ -59,6 +285,29 @@ type SMTPConfig struct {
 	From     string
 }
 
+// readPEM parses the PEM file named by the env var key; nil when it is unset.
+func readPEM[K any](key string, parse func([]byte) (K, error)) (K, error) {
+	var zero K
+	path := getEnv(key, "")
+	if path == "" {
+		return zero, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return zero, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	k, err := parse(data)
+	if err != nil {
+		return zero, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return k, nil
+}
+
+// Enabled reports whether outgoing email is configured (SMTP_HOST is set).
+func (s SMTPConfig) Enabled() bool {
+	return s.Host != ""
+}
+
 // Load reads configuration from environment variables with sensible defaults.
 func Load() (*Config, error) {
 	accessTTL, err := time.ParseDuration(getEnv("JWT_ACCESS_TTL", "15m"))
@@ -71,26 +320,184 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_TTL: %w", err)
 	}
 
+	refreshIdleTTL, err := time.ParseDuration(getEnv("JWT_REFRESH_IDLE_TTL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REFRESH_IDLE_TTL: %w", err)
+	}
+
+	jwtPrivateKey, err := readPEM("JWT_PRIVATE_KEY_FILE", jwt.ParseRSAPrivateKeyFromPEM)
+	if err != nil {
+		return nil, err
+	}
+	jwtPublicKey, err := readPEM("JWT_PUBLIC_KEY_FILE", jwt.ParseRSAPublicKeyFromPEM)
+	if err != nil {
+		return nil, err
+	}
+	if jwtPublicKey == nil && jwtPrivateKey != nil {
+		jwtPublicKey = &jwtPrivateKey.PublicKey
+	}
+	jwtPreviousPublicKey, err := readPEM("JWT_PREVIOUS_PUBLIC_KEY_FILE", jwt.ParseRSAPublicKeyFromPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	invitationTTL, err := time.ParseDuration(getEnv("INVITATION_TTL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVITATION_TTL: %w", err)
+	}
+
+	bcryptCost, err := strconv.Atoi(getEnv("BCRYPT_COST", "12"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BCRYPT_COST: %w", err)
+	}
+
+	argon2Time, err := strconv.ParseUint(getEnv("ARGON2_TIME", "3"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_TIME: %w", err)
+	}
+	argon2Memory, err := strconv.ParseUint(getEnv("ARGON2_MEMORY_KIB", "65536"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_MEMORY_KIB: %w", err)
+	}
+	argon2Threads, err := strconv.ParseUint(getEnv("ARGON2_THREADS", "2"), 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_THREADS: %w", err)
+	}
+
+	passwordMinLength, err := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_MIN_LENGTH: %w", err)
+	}
+
+	passwordMixedCase, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_MIXED_CASE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_MIXED_CASE: %w", err)
+	}
+
+	passwordDigit, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_DIGIT", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_DIGIT: %w", err)
+	}
+
+	passwordSymbol, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_SYMBOL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_SYMBOL: %w", err)
+	}
+
+	undoWindow, err := time.ParseDuration(getEnv("UNDO_WINDOW", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid UNDO_WINDOW: %w", err)
+	}
+
+	maxFuture, err := time.ParseDuration(getEnv("TRANSACTION_MAX_FUTURE", "8760h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_MAX_FUTURE: %w", err)
+	}
+	migrateOnStart, err := strconv.ParseBool(getEnv("DB_MIGRATE_ON_START", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MIGRATE_ON_START: %w", err)
+	}
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+	}
+	postInterval, err := time.ParseDuration(getEnv("TRANSACTION_POST_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_POST_INTERVAL: %w", err)
+	}
+	maxTags, err := strconv.Atoi(getEnv("TRANSACTION_MAX_TAGS", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_MAX_TAGS: %w", err)
+	}
+	maxTagLength, err := strconv.Atoi(getEnv("TRANSACTION_MAX_TAG_LENGTH", "50"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_MAX_TAG_LENGTH: %w", err)
+	}
+	maxAmount, err := decimal.NewFromString(getEnv("TRANSACTION_MAX_AMOUNT", "1000000000000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_MAX_AMOUNT: %w", err)
+	}
+
+	defaultPageSize, err := strconv.ParseInt(getEnv("DEFAULT_PAGE_SIZE", "50"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEFAULT_PAGE_SIZE: %w", err)
+	}
+
+	maxPageSize, err := strconv.ParseInt(getEnv("MAX_PAGE_SIZE", "500"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAX_PAGE_SIZE: %w", err)
+	}
+
+	janitorInterval, err := time.ParseDuration(getEnv("JANITOR_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JANITOR_INTERVAL: %w", err)
+	}
+	if janitorInterval <= 0 {
+		return nil, fmt.Errorf("JANITOR_INTERVAL must be positive")
+	}
+
+	maintenanceMode, err := ParseMaintenanceMode(getEnv("MAINTENANCE_MODE", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	maintenanceRetryAfter, err := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_RETRY_AFTER: %w", err)
+	}
+
+	seedDefaultAccounts, err := strconv.ParseBool(getEnv("SEED_DEFAULT_ACCOUNTS", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEED_DEFAULT_ACCOUNTS: %w", err)
+	}
+
+	metricsEnabled, err := strconv.ParseBool(getEnv("ENABLE_METRICS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ENABLE_METRICS: %w", err)
+	}
+
 	cfg := &Config{
 		DB: DBConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "howallet"),
-			Password: getEnv("DB_PASSWORD", "howallet_secret"),
-			Name:     getEnv("DB_NAME", "howallet"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:           getEnv("DB_HOST", "localhost"),
+			Port:           getEnv("DB_PORT", "5432"),
+			User:           getEnv("DB_USER", "howallet"),
+			Password:       getEnv("DB_PASSWORD", "howallet_secret"),
+			Name:           getEnv("DB_NAME", "howallet"),
+			SSLMode:        getEnv("DB_SSLMODE", "disable"),
+			MigrateOnStart: migrateOnStart,
 		},
 		API: APIConfig{
-			Port: getEnv("API_PORT", "8080"),
-			Host: getEnv("API_HOST", "0.0.0.0"),
+			Port:            getEnv("API_PORT", "8080"),
+			Host:            getEnv("API_HOST", "0.0.0.0"),
+			ShutdownTimeout: shutdownTimeout,
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", ""),
-			AccessTTL:  accessTTL,
-			RefreshTTL: refreshTTL,
+			Algorithm:         JWTAlgorithm(strings.ToUpper(getEnv("JWT_ALGORITHM", string(JWTAlgorithmHS256)))),
+			Secret:            getEnv("JWT_SECRET", ""),
+			PreviousSecret:    getEnv("JWT_PREVIOUS_SECRET", ""),
+			PrivateKey:        jwtPrivateKey,
+			PublicKey:         jwtPublicKey,
+			PreviousPublicKey: jwtPreviousPublicKey,
+			Issuer:            getEnv("JWT_ISSUER", "howallet"),
+			Audience:          getEnv("JWT_AUDIENCE", "howallet-api"),
+			AccessTTL:         accessTTL,
+			RefreshTTL:        refreshTTL,
+			RefreshIdleTTL:    refreshIdleTTL,
+		},
+		Frontend: loadFrontend(),
+		Invitation: InvitationConfig{
+			TTL: invitationTTL,
 		},
-		Frontend: FrontendConfig{
-			URL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+		Password: PasswordConfig{
+			Hash:             PasswordHash(getEnv("PASSWORD_HASH", string(PasswordHashBcrypt))),
+			BcryptCost:       bcryptCost,
+			Argon2Time:       uint32(argon2Time),
+			Argon2MemoryKiB:  uint32(argon2Memory),
+			Argon2Threads:    uint8(argon2Threads),
+			MinLength:        passwordMinLength,
+			RequireMixedCase: passwordMixedCase,
+			RequireDigit:     passwordDigit,
+			RequireSymbol:    passwordSymbol,
 		},
 		SMTP: SMTPConfig{
 			Host:     getEnv("SMTP_HOST", ""),
@@ -99,16 +506,263 @@ func Load() (*Config, error) {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", ""),
 		},
+		Audit: AuditConfig{
+			UndoWindow: undoWindow,
+		},
+		Transaction: TransactionConfig{
+			MaxFuture:    maxFuture,
+			PostInterval: postInterval,
+			MaxTags:      maxTags,
+			MaxTagLength: maxTagLength,
+			MaxAmount:    maxAmount,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: int32(defaultPageSize),
+			MaxLimit:     int32(maxPageSize),
+		},
+		Household: HouseholdConfig{
+			DefaultCurrency:     strings.ToUpper(getEnv("DEFAULT_CURRENCY", "USD")),
+			SeedDefaultAccounts: seedDefaultAccounts,
+		},
+		Janitor: JanitorConfig{
+			Interval: janitorInterval,
+		},
+		Maintenance: MaintenanceConfig{
+			Mode:       maintenanceMode,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		Metrics: MetricsConfig{
+			Enabled: metricsEnabled,
+			Addr:    getEnv("METRICS_ADDR", ":9090"),
+		},
+		Tracing: TracingConfig{
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			ServiceName:  getEnv("OTEL_SERVICE_NAME", "howallet"),
+		},
 		Env: getEnv("ENV", "development"),
 	}
 
-	if cfg.JWT.Secret == "" {
-		return nil, fmt.Errorf("JWT_SECRET environment variable is required")
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// Validate checks settings that depend on each other and reports every problem at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.JWT.Algorithm {
+	case JWTAlgorithmHS256:
+		if c.JWT.Secret == "" {
+			errs = append(errs, errors.New("JWT_SECRET environment variable is required"))
+		}
+		if c.JWT.PreviousSecret != "" && c.JWT.PreviousSecret == c.JWT.Secret {
+			errs = append(errs, errors.New("JWT_PREVIOUS_SECRET must differ from JWT_SECRET"))
+		}
+	case JWTAlgorithmRS256:
+		if c.JWT.PrivateKey == nil {
+			errs = append(errs, errors.New("JWT_PRIVATE_KEY_FILE is required with JWT_ALGORITHM=RS256"))
+		} else if !c.JWT.PrivateKey.PublicKey.Equal(c.JWT.PublicKey) {
+			errs = append(errs, errors.New("JWT_PUBLIC_KEY_FILE does not match JWT_PRIVATE_KEY_FILE"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("JWT_ALGORITHM must be HS256 or RS256, got %q", c.JWT.Algorithm))
+	}
+	if c.JWT.Issuer == "" || c.JWT.Audience == "" {
+		errs = append(errs, errors.New("JWT_ISSUER and JWT_AUDIENCE must not be empty"))
+	}
+	if c.JWT.AccessTTL <= 0 {
+		errs = append(errs, errors.New("JWT_ACCESS_TTL must be positive"))
+	}
+	if c.JWT.RefreshTTL <= c.JWT.AccessTTL {
+		errs = append(errs, errors.New("JWT_REFRESH_TTL must be longer than JWT_ACCESS_TTL"))
+	}
+	if c.JWT.RefreshIdleTTL < 0 {
+		errs = append(errs, errors.New("JWT_REFRESH_IDLE_TTL must not be negative"))
+	}
+	if c.API.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.Transaction.MaxFuture < 0 {
+		errs = append(errs, errors.New("TRANSACTION_MAX_FUTURE must not be negative"))
+	}
+	if c.Transaction.PostInterval <= 0 {
+		errs = append(errs, errors.New("TRANSACTION_POST_INTERVAL must be positive"))
+	}
+	if c.Transaction.MaxTags <= 0 || c.Transaction.MaxTagLength <= 0 {
+		errs = append(errs, errors.New("TRANSACTION_MAX_TAGS and TRANSACTION_MAX_TAG_LENGTH must be positive"))
+	}
+	// Amounts are stored as DECIMAL(19, 4), which holds less than 10^15.
+	if !c.Transaction.MaxAmount.IsPositive() || c.Transaction.MaxAmount.GreaterThanOrEqual(decimal.New(1, 15)) {
+		errs = append(errs, errors.New("TRANSACTION_MAX_AMOUNT must be positive and below 10^15"))
+	}
+	if c.Pagination.DefaultLimit <= 0 || c.Pagination.MaxLimit <= 0 {
+		errs = append(errs, errors.New("DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE must be positive"))
+	} else if c.Pagination.DefaultLimit > c.Pagination.MaxLimit {
+		errs = append(errs, errors.New("DEFAULT_PAGE_SIZE must not exceed MAX_PAGE_SIZE"))
+	}
+	if !isCurrencyCode(c.Household.DefaultCurrency) {
+		errs = append(errs, fmt.Errorf("DEFAULT_CURRENCY must be a three-letter currency code, got %q", c.Household.DefaultCurrency))
+	}
+	if len(c.Frontend.URLs) == 0 {
+		errs = append(errs, errors.New("FRONTEND_URLS must list at least one origin"))
+	}
+	for _, origin := range c.Frontend.URLs {
+		if origin == "*" {
+			// Browsers reject a wildcard origin on credentialed requests.
+			errs = append(errs, errors.New("FRONTEND_URLS must not contain \"*\" because credentials are allowed"))
+		}
+	}
+	if c.Invitation.TTL <= 0 {
+		errs = append(errs, errors.New("INVITATION_TTL must be positive"))
+	}
+	if c.Password.BcryptCost < bcrypt.MinCost || c.Password.BcryptCost > bcrypt.MaxCost {
+		errs = append(errs, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+	switch c.Password.Hash {
+	case PasswordHashBcrypt:
+	case PasswordHashArgon2id:
+		if c.Password.Argon2Time < 1 || c.Password.Argon2Threads < 1 {
+			errs = append(errs, errors.New("ARGON2_TIME and ARGON2_THREADS must be positive"))
+		}
+		// argon2 needs at least 8 KiB per lane.
+		if c.Password.Argon2MemoryKiB < 8*uint32(c.Password.Argon2Threads) {
+			errs = append(errs, errors.New("ARGON2_MEMORY_KIB must be at least 8 per thread"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid PASSWORD_HASH %q (want bcrypt or argon2id)", c.Password.Hash))
+	}
+	// bcrypt only hashes the first 72 bytes, so a longer minimum can't be honoured.
+	if c.Password.MinLength < 1 || c.Password.MinLength > MaxPasswordBytes {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH must be between 1 and %d", MaxPasswordBytes))
+	}
+
+	if c.Metrics.Enabled && c.Metrics.Addr == "" {
+		errs = append(errs, errors.New("METRICS_ADDR is required when ENABLE_METRICS is true"))
+	}
+
+	// Email is enabled by SMTP_HOST; the rest must then be usable.
+	if c.SMTP.Enabled() {
+		if c.SMTP.Port == "" {
+			errs = append(errs, errors.New("SMTP_PORT is required when SMTP_HOST is set"))
+		}
+		if c.SMTP.From == "" {
+			errs = append(errs, errors.New("SMTP_FROM is required when SMTP_HOST is set"))
+		}
+		if c.SMTP.User != "" && c.SMTP.Password == "" {
+			errs = append(errs, errors.New("SMTP_PASSWORD is required when SMTP_USER is set"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns the effective configuration with secrets masked, safe to log.
+func (c *Config) Redacted() map[string]any {
+	return map[string]any{
+		"env":                         c.Env,
+		"db.host":                     c.DB.Host,
+		"db.port":                     c.DB.Port,
+		"db.user":                     c.DB.User,
+		"db.password":                 mask(c.DB.Password),
+		"db.name":                     c.DB.Name,
+		"db.sslmode":                  c.DB.SSLMode,
+		"db.migrate_on_start":         c.DB.MigrateOnStart,
+		"api.addr":                    c.API.Addr(),
+		"api.shutdown_timeout":        c.API.ShutdownTimeout.String(),
+		"jwt.algorithm":               string(c.JWT.Algorithm),
+		"jwt.secret":                  mask(c.JWT.Secret),
+		"jwt.previous_secret":         mask(c.JWT.PreviousSecret),
+		"jwt.issuer":                  c.JWT.Issuer,
+		"jwt.audience":                c.JWT.Audience,
+		"jwt.access_ttl":              c.JWT.AccessTTL.String(),
+		"jwt.refresh_ttl":             c.JWT.RefreshTTL.String(),
+		"jwt.refresh_idle_ttl":        c.JWT.RefreshIdleTTL.String(),
+		"frontend.url":                c.Frontend.URL,
+		"frontend.urls":               c.Frontend.URLs,
+		"invitation.ttl":              c.Invitation.TTL.String(),
+		"password.hash":               string(c.Password.Hash),
+		"password.bcrypt_cost":        c.Password.BcryptCost,
+		"password.argon2_time":        c.Password.Argon2Time,
+		"password.argon2_memory_kib":  c.Password.Argon2MemoryKiB,
+		"password.argon2_threads":     c.Password.Argon2Threads,
+		"password.min_length":         c.Password.MinLength,
+		"password.require_mixed_case": c.Password.RequireMixedCase,
+		"password.require_digit":      c.Password.RequireDigit,
+		"password.require_symbol":     c.Password.RequireSymbol,
+		"smtp.enabled":                c.SMTP.Enabled(),
+		"smtp.host":                   c.SMTP.Host,
+		"smtp.port":                   c.SMTP.Port,
+		"smtp.user":                   c.SMTP.User,
+		"smtp.password":               mask(c.SMTP.Password),
+		"smtp.from":                   c.SMTP.From,
+		"audit.undo_window":           c.Audit.UndoWindow.String(),
+		"transaction.max_future":      c.Transaction.MaxFuture.String(),
+		"transaction.post_interval":   c.Transaction.PostInterval.String(),
+		"transaction.max_tags":        c.Transaction.MaxTags,
+		"transaction.max_tag_length":  c.Transaction.MaxTagLength,
+		"transaction.max_amount":      c.Transaction.MaxAmount.String(),
+		"pagination.default_limit":    c.Pagination.DefaultLimit,
+		"pagination.max_limit":        c.Pagination.MaxLimit,
+		"household.default_currency":  c.Household.DefaultCurrency,
+		"household.seed_accounts":     c.Household.SeedDefaultAccounts,
+		"janitor.interval":            c.Janitor.Interval.String(),
+		"maintenance.mode":            string(c.Maintenance.Mode),
+		"maintenance.retry_after":     c.Maintenance.RetryAfter.String(),
+		"metrics.enabled":             c.Metrics.Enabled,
+		"metrics.addr":                c.Metrics.Addr,
+		"tracing.otlp_endpoint":       c.Tracing.OTLPEndpoint,
+		"tracing.service_name":        c.Tracing.ServiceName,
+	}
+}
+
+// mask hides a secret but still shows whether it is set.
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "********"
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 code: three upper-case letters.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFrontend reads FRONTEND_URLS (comma-separated) and FRONTEND_URL. Either may
+// be used alone: the single URL is allowed as an origin, and the first listed origin
+// is the primary URL.
+func loadFrontend() FrontendConfig {
+	var urls []string
+	for _, u := range strings.Split(getEnv("FRONTEND_URLS", ""), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	url := getEnv("FRONTEND_URL", "")
+	switch {
+	case len(urls) == 0:
+		if url == "" {
+			url = "http://localhost:3000"
+		}
+		urls = []string{url}
+	case url == "":
+		url = urls[0]
+	}
+	return FrontendConfig{URL: url, URLs: urls}
+}
+
 func getEnv(key, fallback string) string {
 	if val, ok := os.LookupEnv(key); ok {
 		return val
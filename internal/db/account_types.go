@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// --- Account types ---
+
+type CreateAccountTypeParams struct {
+	HouseholdID uuid.UUID
+	Name        string
+}
+
+func (q *Queries) CreateAccountType(ctx context.Context, arg CreateAccountTypeParams) error {
+	return q.exec(ctx,
+		`INSERT INTO account_types (household_id, name) VALUES ($1, $2)`,
+		arg.HouseholdID, arg.Name,
+	)
+}
+
+// ListAccountTypes returns the names of the household's custom account types.
+func (q *Queries) ListAccountTypes(ctx context.Context, householdID uuid.UUID) ([]string, error) {
+	rows, err := q.query(ctx,
+		`SELECT name FROM account_types WHERE household_id = $1 ORDER BY name`,
+		householdID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		out = append(out, name)
+	}
+	return out, rows.Err()
+}
+
+type AccountTypeExistsParams struct {
+	HouseholdID uuid.UUID
+	Name        string
+}
+
+// AccountTypeExists reports whether the household has the custom type, locking
+// it against deletion until the transaction ends.
+func (q *Queries) AccountTypeExists(ctx context.Context, arg AccountTypeExistsParams) (bool, error) {
+	var exists bool
+	err := q.queryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM account_types WHERE household_id = $1 AND name = $2 FOR KEY SHARE)`,
+		arg.HouseholdID, arg.Name,
+	).Scan(&exists)
+	return exists, err
+}
+
+type DeleteAccountTypeParams struct {
+	HouseholdID uuid.UUID
+	Name        string
+}
+
+func (q *Queries) DeleteAccountType(ctx context.Context, arg DeleteAccountTypeParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM account_types WHERE household_id = $1 AND name = $2`,
+		arg.HouseholdID, arg.Name,
+	)
+}
+
+type CountAccountsOfTypeParams struct {
+	HouseholdID uuid.UUID
+	Type        string
+}
+
+func (q *Queries) CountAccountsOfType(ctx context.Context, arg CountAccountsOfTypeParams) (int64, error) {
+	var count int64
+	err := q.queryRow(ctx,
+		`SELECT COUNT(*) FROM accounts WHERE household_id = $1 AND type = $2`,
+		arg.HouseholdID, arg.Type,
+	).Scan(&count)
+	return count, err
+}
@@ -2,30 +2,53 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
+// accountColumns lists the columns of the accounts table in scanAccount order.
+const accountColumns = `id, household_id, name, type, balance, currency,
+			created_by, created_at, updated_at, include_in_totals,
+			opening_balance, position, updated_by, is_liability,
+			daily_limit`
+
+func scanAccount(row pgx.Row) (Account, error) {
+	var a Account
+	err := row.Scan(
+		&a.ID, &a.HouseholdID, &a.Name, &a.Type, &a.Balance, &a.Currency,
+		&a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.IncludeInTotals,
+		&a.OpeningBalance, &a.Position, &a.UpdatedBy, &a.IsLiability,
+		&a.DailyLimit,
+	)
+	return a, err
+}
+
 type CreateAccountParams struct {
-	HouseholdID uuid.UUID
-	Name        string
-	Type        AccountType
-	Balance     decimal.Decimal
-	Currency    string
-	CreatedBy   uuid.UUID
+	HouseholdID     uuid.UUID
+	Name            string
+	Type            AccountType
+	Balance         decimal.Decimal
+	Currency        string
+	CreatedBy       uuid.UUID
+	IncludeInTotals bool
+	IsLiability     bool
+	DailyLimit      decimal.NullDecimal
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
 	row := q.queryRow(ctx,
-		`INSERT INTO accounts (household_id, name, type, balance, currency, created_by)
-		 VALUES ($1, $2, $3, $4, $5, $6)
-		 RETURNING id, household_id, name, type, balance, currency, created_by, created_at, updated_at`,
-		arg.HouseholdID, arg.Name, arg.Type, arg.Balance, arg.Currency, arg.CreatedBy,
+		`INSERT INTO accounts (household_id, name, type, balance, opening_balance, currency, created_by, include_in_totals, is_liability, daily_limit, position)
+		 VALUES ($1, $2, $3, $4, $4, $5, $6, $7, $8, $9,
+		         (SELECT COALESCE(MAX(position) + 1, 0) FROM accounts WHERE household_id = $1))
+		 RETURNING `+accountColumns,
+		arg.HouseholdID, arg.Name, arg.Type, arg.Balance, arg.Currency, arg.CreatedBy, arg.IncludeInTotals, arg.IsLiability,
+		arg.DailyLimit,
 	)
-	var a Account
-	err := row.Scan(&a.ID, &a.HouseholdID, &a.Name, &a.Type, &a.Balance, &a.Currency, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt)
-	return a, err
+	return scanAccount(row)
 }
 
 type GetAccountParams struct {
@@ -35,19 +58,29 @@ type GetAccountParams struct {
 
 func (q *Queries) GetAccount(ctx context.Context, arg GetAccountParams) (Account, error) {
 	row := q.queryRow(ctx,
-		`SELECT id, household_id, name, type, balance, currency, created_by, created_at, updated_at
+		`SELECT `+accountColumns+`
 		 FROM accounts WHERE id = $1 AND household_id = $2`,
 		arg.ID, arg.HouseholdID,
 	)
-	var a Account
-	err := row.Scan(&a.ID, &a.HouseholdID, &a.Name, &a.Type, &a.Balance, &a.Currency, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt)
-	return a, err
+	return scanAccount(row)
+}
+
+// GetAccountForUpdate is GetAccount that also locks the row until the end of
+// the transaction.
+func (q *Queries) GetAccountForUpdate(ctx context.Context, arg GetAccountParams) (Account, error) {
+	row := q.queryRow(ctx,
+		`SELECT `+accountColumns+`
+		 FROM accounts WHERE id = $1 AND household_id = $2
+		 FOR UPDATE`,
+		arg.ID, arg.HouseholdID,
+	)
+	return scanAccount(row)
 }
 
 func (q *Queries) ListAccountsByHousehold(ctx context.Context, householdID uuid.UUID) ([]Account, error) {
 	rows, err := q.query(ctx,
-		`SELECT id, household_id, name, type, balance, currency, created_by, created_at, updated_at
-		 FROM accounts WHERE household_id = $1 ORDER BY created_at`,
+		`SELECT `+accountColumns+`
+		 FROM accounts WHERE household_id = $1 ORDER BY position, created_at`,
 		householdID,
 	)
 	if err != nil {
@@ -57,8 +90,8 @@ func (q *Queries) ListAccountsByHousehold(ctx context.Context, householdID uuid.
 
 	var out []Account
 	for rows.Next() {
-		var a Account
-		if err := rows.Scan(&a.ID, &a.HouseholdID, &a.Name, &a.Type, &a.Balance, &a.Currency, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		a, err := scanAccount(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, a)
@@ -66,27 +99,105 @@ func (q *Queries) ListAccountsByHousehold(ctx context.Context, householdID uuid.
 	return out, rows.Err()
 }
 
-type UpdateAccountParams struct {
-	ID          uuid.UUID
+type SearchAccountsParams struct {
 	HouseholdID uuid.UUID
-	Name        *string
-	Type        *AccountType
-	Currency    *string
+	Query       string
+	Limit       int32
+}
+
+// AccountNameExists reports whether the household has an account named name,
+// ignoring case.
+func (q *Queries) AccountNameExists(ctx context.Context, householdID uuid.UUID, name string) (bool, error) {
+	var exists bool
+	err := q.queryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM accounts WHERE household_id = $1 AND lower(name) = lower($2))`,
+		householdID, name,
+	).Scan(&exists)
+	return exists, err
+}
+
+// SearchAccounts returns the household's accounts whose name contains Query,
+// ignoring case, in display order.
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	rows, err := q.query(ctx,
+		`SELECT `+accountColumns+`
+		 FROM accounts
+		 WHERE household_id = $1 AND strpos(lower(name), lower($2)) > 0
+		 ORDER BY position, created_at
+		 LIMIT $3`,
+		arg.HouseholdID, arg.Query, arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Account
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
 }
 
+type UpdateAccountParams struct {
+	ID              uuid.UUID
+	HouseholdID     uuid.UUID
+	Name            *string
+	Type            *AccountType
+	Currency        *string
+	IncludeInTotals *bool
+	IsLiability     *bool
+	// SetDailyLimit replaces daily_limit with DailyLimit, which may be null.
+	SetDailyLimit bool
+	DailyLimit    decimal.NullDecimal
+	UpdatedBy     uuid.UUID
+	// ExpectedUpdatedAt, when set, makes the update match nothing if the row has
+	// changed since the caller read it.
+	ExpectedUpdatedAt pgtype.Timestamptz
+}
+
+// UpdateAccount changes the set fields. Switching is_liability negates the
+// balances, so the account keeps its weight in household totals.
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
 	row := q.queryRow(ctx,
 		`UPDATE accounts
 		 SET name     = COALESCE($3, name),
 		     type     = COALESCE($4, type),
-		     currency = COALESCE($5, currency)
+		     currency = COALESCE($5, currency),
+		     include_in_totals = COALESCE($6, include_in_totals),
+		     balance = CASE WHEN $9::boolean <> is_liability THEN -balance ELSE balance END,
+		     opening_balance = CASE WHEN $9::boolean <> is_liability THEN -opening_balance ELSE opening_balance END,
+		     is_liability = COALESCE($9, is_liability),
+		     daily_limit = CASE WHEN $10::boolean THEN $11::numeric ELSE daily_limit END,
+		     updated_by = $8
 		 WHERE id = $1 AND household_id = $2
-		 RETURNING id, household_id, name, type, balance, currency, created_by, created_at, updated_at`,
-		arg.ID, arg.HouseholdID, arg.Name, arg.Type, arg.Currency,
+		   AND ($7::timestamptz IS NULL OR updated_at = $7)
+		 RETURNING `+accountColumns,
+		arg.ID, arg.HouseholdID, arg.Name, arg.Type, arg.Currency, arg.IncludeInTotals,
+		arg.ExpectedUpdatedAt, arg.UpdatedBy, arg.IsLiability,
+		arg.SetDailyLimit, arg.DailyLimit,
+	)
+	return scanAccount(row)
+}
+
+type ReorderAccountsParams struct {
+	HouseholdID uuid.UUID
+	AccountIDs  []uuid.UUID
+}
+
+// ReorderAccounts sets each listed account's position to its index in AccountIDs.
+func (q *Queries) ReorderAccounts(ctx context.Context, arg ReorderAccountsParams) (int64, error) {
+	return q.execRows(ctx,
+		`UPDATE accounts a
+		 SET position = o.pos - 1
+		 FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, pos)
+		 WHERE a.id = o.id AND a.household_id = $1`,
+		arg.HouseholdID, arg.AccountIDs,
 	)
-	var a Account
-	err := row.Scan(&a.ID, &a.HouseholdID, &a.Name, &a.Type, &a.Balance, &a.Currency, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt)
-	return a, err
 }
 
 type UpdateAccountBalanceParams struct {
@@ -94,9 +205,13 @@ type UpdateAccountBalanceParams struct {
 	Balance decimal.Decimal
 }
 
+// UpdateAccountBalance adds Balance to an asset account and subtracts it from a
+// liability, whose balance is what is owed.
 func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) error {
 	return q.exec(ctx,
-		`UPDATE accounts SET balance = balance + $2 WHERE id = $1`,
+		`UPDATE accounts
+		 SET balance = balance + CASE WHEN is_liability THEN -$2::numeric ELSE $2::numeric END
+		 WHERE id = $1`,
 		arg.ID, arg.Balance,
 	)
 }
@@ -125,6 +240,71 @@ func (q *Queries) DeleteAccount(ctx context.Context, arg DeleteAccountParams) er
 	)
 }
 
+type SumBalancesByTypeRow struct {
+	Type     string
+	Currency string
+	Total    decimal.Decimal
+	Count    int64
+}
+
+// SumBalancesByType totals the household's account balances per type and currency,
+// subtracting liabilities and skipping accounts excluded from household totals.
+func (q *Queries) SumBalancesByType(ctx context.Context, householdID uuid.UUID) ([]SumBalancesByTypeRow, error) {
+	rows, err := q.query(ctx,
+		`SELECT type, currency, SUM(CASE WHEN is_liability THEN -balance ELSE balance END), COUNT(*)
+		 FROM accounts
+		 WHERE household_id = $1 AND include_in_totals
+		 GROUP BY type, currency
+		 ORDER BY type, currency`,
+		householdID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SumBalancesByTypeRow
+	for rows.Next() {
+		var r SumBalancesByTypeRow
+		if err := rows.Scan(&r.Type, &r.Currency, &r.Total, &r.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type GetAccountBalanceAsOfParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	Before      time.Time
+}
+
+// GetAccountBalanceAsOf recomputes an account's balance from its opening balance
+// and the posted transactions dated before Before.
+func (q *Queries) GetAccountBalanceAsOf(ctx context.Context, arg GetAccountBalanceAsOfParams) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+	err := q.queryRow(ctx,
+		`SELECT a.opening_balance + CASE WHEN a.is_liability THEN -n.net ELSE n.net END
+		 FROM accounts a,
+		      LATERAL (
+		          SELECT COALESCE(SUM(CASE
+		                     WHEN t.type = 'income' THEN t.amount
+		                     WHEN t.type = 'expense' THEN -t.amount
+		                     WHEN t.account_id = a.id THEN -t.amount -- transfer out
+		                     ELSE t.amount                           -- transfer in
+		                 END), 0) AS net
+		          FROM transactions t
+		          WHERE (t.account_id = a.id OR t.destination_account_id = a.id)
+		            AND t.posted
+		            AND t.transacted_at < $3
+		      ) n
+		 WHERE a.id = $1 AND a.household_id = $2`,
+		arg.ID, arg.HouseholdID, arg.Before,
+	).Scan(&balance)
+	return balance, err
+}
+
 func (q *Queries) CountTransactionsByAccount(ctx context.Context, accountID uuid.UUID) (int64, error) {
 	var count int64
 	err := q.queryRow(ctx,
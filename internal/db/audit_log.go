@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// --- Audit log ---
+
+type CreateAuditEntryParams struct {
+	HouseholdID uuid.UUID
+	UserID      uuid.UUID
+	Action      string
+	EntityType  string
+	EntityID    uuid.UUID
+	Before      []byte
+	After       []byte
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditLog, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO audit_log (household_id, user_id, action, entity_type, entity_id, before, after)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING id, household_id, user_id, action, entity_type, entity_id, before, after, undone_at, created_at`,
+		arg.HouseholdID, arg.UserID, arg.Action, arg.EntityType, arg.EntityID, arg.Before, arg.After,
+	)
+	var a AuditLog
+	err := row.Scan(&a.ID, &a.HouseholdID, &a.UserID, &a.Action, &a.EntityType, &a.EntityID, &a.Before, &a.After, &a.UndoneAt, &a.CreatedAt)
+	return a, err
+}
+
+type GetLastUndoableAuditEntryParams struct {
+	HouseholdID uuid.UUID
+	UserID      uuid.UUID
+	Since       time.Time
+}
+
+func (q *Queries) GetLastUndoableAuditEntry(ctx context.Context, arg GetLastUndoableAuditEntryParams) (AuditLog, error) {
+	row := q.queryRow(ctx,
+		`SELECT id, household_id, user_id, action, entity_type, entity_id, before, after, undone_at, created_at
+		 FROM audit_log
+		 WHERE household_id = $1
+		   AND user_id = $2
+		   AND undone_at IS NULL
+		   AND created_at >= $3
+		 ORDER BY created_at DESC
+		 LIMIT 1
+		 FOR UPDATE`,
+		arg.HouseholdID, arg.UserID, arg.Since,
+	)
+	var a AuditLog
+	err := row.Scan(&a.ID, &a.HouseholdID, &a.UserID, &a.Action, &a.EntityType, &a.EntityID, &a.Before, &a.After, &a.UndoneAt, &a.CreatedAt)
+	return a, err
+}
+
+func (q *Queries) MarkAuditEntryUndone(ctx context.Context, id uuid.UUID) error {
+	return q.exec(ctx, `UPDATE audit_log SET undone_at = now() WHERE id = $1`, id)
+}
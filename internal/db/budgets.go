@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// --- Budgets ---
+
+// budgetColumns lists the columns of the budgets table in scanBudget order.
+const budgetColumns = `id, household_id, category_id, amount, thresholds, created_at, updated_at`
+
+func scanBudget(row pgx.Row) (Budget, error) {
+	var b Budget
+	err := row.Scan(&b.ID, &b.HouseholdID, &b.CategoryID, &b.Amount, &b.Thresholds, &b.CreatedAt, &b.UpdatedAt)
+	return b, err
+}
+
+func scanBudgets(rows pgx.Rows, err error) ([]Budget, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Budget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+type CreateBudgetParams struct {
+	HouseholdID uuid.UUID
+	CategoryID  uuid.UUID
+	Amount      decimal.Decimal
+	Thresholds  []int32
+}
+
+func (q *Queries) CreateBudget(ctx context.Context, arg CreateBudgetParams) (Budget, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO budgets (household_id, category_id, amount, thresholds)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+budgetColumns,
+		arg.HouseholdID, arg.CategoryID, arg.Amount, arg.Thresholds,
+	)
+	return scanBudget(row)
+}
+
+func (q *Queries) ListBudgets(ctx context.Context, householdID uuid.UUID) ([]Budget, error) {
+	return scanBudgets(q.query(ctx,
+		`SELECT `+budgetColumns+`
+		 FROM budgets WHERE household_id = $1
+		 ORDER BY created_at`,
+		householdID,
+	))
+}
+
+type ListBudgetsForCategoriesParams struct {
+	HouseholdID uuid.UUID
+	CategoryIds []uuid.UUID
+}
+
+func (q *Queries) ListBudgetsForCategories(ctx context.Context, arg ListBudgetsForCategoriesParams) ([]Budget, error) {
+	return scanBudgets(q.query(ctx,
+		`SELECT `+budgetColumns+`
+		 FROM budgets WHERE household_id = $1 AND category_id = ANY($2)`,
+		arg.HouseholdID, arg.CategoryIds,
+	))
+}
+
+type UpdateBudgetParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	CategoryID  uuid.UUID
+	Amount      decimal.Decimal
+	Thresholds  []int32
+}
+
+func (q *Queries) UpdateBudget(ctx context.Context, arg UpdateBudgetParams) (Budget, error) {
+	row := q.queryRow(ctx,
+		`UPDATE budgets SET category_id = $3, amount = $4, thresholds = $5
+		 WHERE id = $1 AND household_id = $2
+		 RETURNING `+budgetColumns,
+		arg.ID, arg.HouseholdID, arg.CategoryID, arg.Amount, arg.Thresholds,
+	)
+	return scanBudget(row)
+}
+
+type DeleteBudgetParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) DeleteBudget(ctx context.Context, arg DeleteBudgetParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM budgets WHERE id = $1 AND household_id = $2`,
+		arg.ID, arg.HouseholdID,
+	)
+}
+
+type SumCategorySpendParams struct {
+	HouseholdID uuid.UUID
+	CategoryID  uuid.UUID
+	From        time.Time
+	To          time.Time
+}
+
+// SumCategorySpend totals the posted expense splits of a category transacted in
+// [From, To).
+func (q *Queries) SumCategorySpend(ctx context.Context, arg SumCategorySpendParams) (decimal.Decimal, error) {
+	row := q.queryRow(ctx,
+		`SELECT COALESCE(SUM(s.amount), 0)
+		 FROM transaction_splits s
+		 JOIN transactions t ON t.id = s.transaction_id
+		 WHERE t.household_id = $1
+		   AND t.type = 'expense'
+		   AND t.posted
+		   AND s.category_id = $2
+		   AND t.transacted_at >= $3
+		   AND t.transacted_at < $4`,
+		arg.HouseholdID, arg.CategoryID, arg.From, arg.To,
+	)
+	var total decimal.Decimal
+	err := row.Scan(&total)
+	return total, err
+}
+
+type CreateBudgetAlertParams struct {
+	BudgetID    uuid.UUID
+	PeriodStart time.Time
+	Threshold   int32
+}
+
+// CreateBudgetAlert records that a threshold was reached in a period. It affects
+// no rows if the alert already fired.
+func (q *Queries) CreateBudgetAlert(ctx context.Context, arg CreateBudgetAlertParams) (int64, error) {
+	return q.execRows(ctx,
+		`INSERT INTO budget_alerts (budget_id, period_start, threshold)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT DO NOTHING`,
+		arg.BudgetID, arg.PeriodStart, arg.Threshold,
+	)
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// --- Categories ---
+
+type CreateCategoryParams struct {
+	HouseholdID uuid.UUID
+	Name        string
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO categories (household_id, name)
+		 VALUES ($1, $2)
+		 RETURNING id, household_id, name, created_at`,
+		arg.HouseholdID, arg.Name,
+	)
+	var c Category
+	err := row.Scan(&c.ID, &c.HouseholdID, &c.Name, &c.CreatedAt)
+	return c, err
+}
+
+func (q *Queries) ListCategories(ctx context.Context, householdID uuid.UUID) ([]Category, error) {
+	rows, err := q.query(ctx,
+		`SELECT id, household_id, name, created_at
+		 FROM categories WHERE household_id = $1
+		 ORDER BY name`,
+		householdID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Category
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.HouseholdID, &c.Name, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+type CountHouseholdCategoriesParams struct {
+	HouseholdID uuid.UUID
+	IDs         []uuid.UUID
+}
+
+// CountHouseholdCategories counts how many of the given category IDs belong to the household.
+func (q *Queries) CountHouseholdCategories(ctx context.Context, arg CountHouseholdCategoriesParams) (int64, error) {
+	var count int64
+	err := q.queryRow(ctx,
+		`SELECT COUNT(*) FROM categories WHERE household_id = $1 AND id = ANY($2::uuid[])`,
+		arg.HouseholdID, arg.IDs,
+	).Scan(&count)
+	return count, err
+}
@@ -42,3 +42,12 @@ func (q *Queries) exec(ctx context.Context, sql string, args ...interface{}) err
 	_, err := q.pool.Exec(ctx, sql, args...)
 	return err
 }
+
+func (q *Queries) execRows(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	if q.tx != nil {
+		tag, err := q.tx.Exec(ctx, sql, args...)
+		return tag.RowsAffected(), err
+	}
+	tag, err := q.pool.Exec(ctx, sql, args...)
+	return tag.RowsAffected(), err
+}
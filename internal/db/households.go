@@ -6,38 +6,45 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // --- Households ---
 
+const householdColumns = `id, name, owner_id, created_at, timezone, default_currency, case_sensitive_tags, settings`
+
+func scanHousehold(row pgx.Row) (Household, error) {
+	var h Household
+	err := row.Scan(&h.ID, &h.Name, &h.OwnerID, &h.CreatedAt, &h.Timezone, &h.DefaultCurrency, &h.CaseSensitiveTags, &h.Settings)
+	return h, err
+}
+
 type CreateHouseholdParams struct {
-	Name    string
-	OwnerID uuid.UUID
+	Name            string
+	OwnerID         uuid.UUID
+	Timezone        string
+	DefaultCurrency string
 }
 
 func (q *Queries) CreateHousehold(ctx context.Context, arg CreateHouseholdParams) (Household, error) {
-	row := q.queryRow(ctx,
-		`INSERT INTO households (name, owner_id) VALUES ($1, $2) RETURNING id, name, owner_id, created_at`,
-		arg.Name, arg.OwnerID,
-	)
-	var h Household
-	err := row.Scan(&h.ID, &h.Name, &h.OwnerID, &h.CreatedAt)
-	return h, err
+	return scanHousehold(q.queryRow(ctx,
+		`INSERT INTO households (name, owner_id, timezone, default_currency)
+		 VALUES ($1, $2, $3, $4) RETURNING `+householdColumns,
+		arg.Name, arg.OwnerID, arg.Timezone, arg.DefaultCurrency,
+	))
 }
 
 func (q *Queries) GetHousehold(ctx context.Context, id uuid.UUID) (Household, error) {
-	row := q.queryRow(ctx,
-		`SELECT id, name, owner_id, created_at FROM households WHERE id = $1`,
+	return scanHousehold(q.queryRow(ctx,
+		`SELECT `+householdColumns+` FROM households WHERE id = $1`,
 		id,
-	)
-	var h Household
-	err := row.Scan(&h.ID, &h.Name, &h.OwnerID, &h.CreatedAt)
-	return h, err
+	))
 }
 
 func (q *Queries) ListUserHouseholds(ctx context.Context, userID uuid.UUID) ([]Household, error) {
 	rows, err := q.query(ctx,
-		`SELECT h.id, h.name, h.owner_id, h.created_at
+		`SELECT h.id, h.name, h.owner_id, h.created_at, h.timezone, h.default_currency,
+		        h.case_sensitive_tags, h.settings
 		 FROM households h
 		 JOIN household_members hm ON hm.household_id = h.id
 		 WHERE hm.user_id = $1
@@ -51,8 +58,8 @@ func (q *Queries) ListUserHouseholds(ctx context.Context, userID uuid.UUID) ([]H
 
 	var out []Household
 	for rows.Next() {
-		var h Household
-		if err := rows.Scan(&h.ID, &h.Name, &h.OwnerID, &h.CreatedAt); err != nil {
+		h, err := scanHousehold(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, h)
@@ -60,6 +67,31 @@ func (q *Queries) ListUserHouseholds(ctx context.Context, userID uuid.UUID) ([]H
 	return out, rows.Err()
 }
 
+type UpdateHouseholdParams struct {
+	ID                uuid.UUID
+	Name              *string
+	Timezone          *string
+	CaseSensitiveTags *bool
+	DefaultCurrency   *string
+	// FiscalMonthStartDay is stored under settings.
+	FiscalMonthStartDay *int32
+}
+
+// UpdateHousehold changes the non-nil fields.
+func (q *Queries) UpdateHousehold(ctx context.Context, arg UpdateHouseholdParams) (Household, error) {
+	return scanHousehold(q.queryRow(ctx,
+		`UPDATE households
+		 SET name = COALESCE($2, name), timezone = COALESCE($3, timezone),
+		     case_sensitive_tags = COALESCE($4, case_sensitive_tags),
+		     default_currency = COALESCE($5, default_currency),
+		     settings = CASE WHEN $6::int IS NULL THEN settings
+		                     ELSE jsonb_set(settings, '{fiscal_month_start_day}', to_jsonb($6::int)) END
+		 WHERE id = $1
+		 RETURNING `+householdColumns,
+		arg.ID, arg.Name, arg.Timezone, arg.CaseSensitiveTags, arg.DefaultCurrency, arg.FiscalMonthStartDay,
+	))
+}
+
 // --- Household Members ---
 
 type AddHouseholdMemberParams struct {
@@ -112,14 +144,27 @@ type ListHouseholdMembersRow struct {
 	UserName    string
 }
 
-func (q *Queries) ListHouseholdMembers(ctx context.Context, householdID uuid.UUID) ([]ListHouseholdMembersRow, error) {
+type ListHouseholdMembersParams struct {
+	HouseholdID uuid.UUID
+	Column2     pgtype.Text // role filter
+	Column3     pgtype.Text // name/email search (case-insensitive substring)
+	Limit       pgtype.Int4 // NULL returns all
+	Offset      int32
+	Column6     []uuid.UUID // user_id filter
+}
+
+func (q *Queries) ListHouseholdMembers(ctx context.Context, arg ListHouseholdMembersParams) ([]ListHouseholdMembersRow, error) {
 	rows, err := q.query(ctx,
 		`SELECT hm.household_id, hm.user_id, hm.role, hm.joined_at, u.email, u.name
 		 FROM household_members hm
 		 JOIN users u ON u.id = hm.user_id
 		 WHERE hm.household_id = $1
-		 ORDER BY hm.joined_at`,
-		householdID,
+		   AND ($2::household_role IS NULL OR hm.role = $2)
+		   AND ($3::text IS NULL OR strpos(lower(u.name), lower($3)) > 0 OR strpos(lower(u.email), lower($3)) > 0)
+		   AND ($6::uuid[] IS NULL OR hm.user_id = ANY($6))
+		 ORDER BY hm.joined_at, hm.user_id
+		 LIMIT $4 OFFSET $5`,
+		arg.HouseholdID, arg.Column2, arg.Column3, arg.Limit, arg.Offset, arg.Column6,
 	)
 	if err != nil {
 		return nil, err
@@ -137,6 +182,28 @@ func (q *Queries) ListHouseholdMembers(ctx context.Context, householdID uuid.UUI
 	return out, rows.Err()
 }
 
+type CountHouseholdMembersParams struct {
+	HouseholdID uuid.UUID
+	Column2     pgtype.Text
+	Column3     pgtype.Text
+	Column4     []uuid.UUID
+}
+
+func (q *Queries) CountHouseholdMembers(ctx context.Context, arg CountHouseholdMembersParams) (int64, error) {
+	var count int64
+	err := q.queryRow(ctx,
+		`SELECT COUNT(*)
+		 FROM household_members hm
+		 JOIN users u ON u.id = hm.user_id
+		 WHERE hm.household_id = $1
+		   AND ($2::household_role IS NULL OR hm.role = $2)
+		   AND ($3::text IS NULL OR strpos(lower(u.name), lower($3)) > 0 OR strpos(lower(u.email), lower($3)) > 0)
+		   AND ($4::uuid[] IS NULL OR hm.user_id = ANY($4))`,
+		arg.HouseholdID, arg.Column2, arg.Column3, arg.Column4,
+	).Scan(&count)
+	return count, err
+}
+
 type IsHouseholdMemberParams struct {
 	HouseholdID uuid.UUID
 	UserID      uuid.UUID
@@ -173,30 +240,62 @@ func (q *Queries) CreateInvitation(ctx context.Context, arg CreateInvitationPara
 	return inv, err
 }
 
+// GetInvitationByToken reports a pending invitation past its expiry as expired,
+// even if the janitor hasn't flipped its stored status yet.
 func (q *Queries) GetInvitationByToken(ctx context.Context, token string) (Invitation, error) {
 	row := q.queryRow(ctx,
-		`SELECT id, household_id, email, invited_by, token, status, expires_at, created_at FROM invitations WHERE token = $1`,
+		`SELECT id, household_id, email, invited_by, token,
+		        CASE WHEN status = 'pending' AND expires_at <= now() THEN 'expired' ELSE status END,
+		        expires_at, created_at
+		 FROM invitations WHERE token = $1`,
 		token,
 	)
 	var inv Invitation
 	err := row.Scan(&inv.ID, &inv.HouseholdID, &inv.Email, &inv.InvitedBy, &inv.Token, &inv.Status, &inv.ExpiresAt, &inv.CreatedAt)
-	if err == pgx.ErrNoRows {
-		return inv, err
-	}
 	return inv, err
 }
 
-func (q *Queries) AcceptInvitation(ctx context.Context, id uuid.UUID) error {
-	return q.exec(ctx, `UPDATE invitations SET status = 'accepted' WHERE id = $1`, id)
+// AcceptInvitation marks a pending, unexpired invitation accepted and reports
+// whether it was.
+func (q *Queries) AcceptInvitation(ctx context.Context, id uuid.UUID) (int64, error) {
+	return q.execRows(ctx,
+		`UPDATE invitations SET status = 'accepted'
+		 WHERE id = $1 AND status = 'pending' AND expires_at > now()`,
+		id,
+	)
+}
+
+type DeletePendingInvitationParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+// DeletePendingInvitation removes a pending invitation and reports whether one was deleted.
+func (q *Queries) DeletePendingInvitation(ctx context.Context, arg DeletePendingInvitationParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM invitations WHERE id = $1 AND household_id = $2 AND status = 'pending'`,
+		arg.ID, arg.HouseholdID,
+	)
+}
+
+func (q *Queries) ExpireStaleInvitations(ctx context.Context) (int64, error) {
+	return q.execRows(ctx, `UPDATE invitations SET status = 'expired' WHERE status = 'pending' AND expires_at <= now()`)
+}
+
+type ListPendingInvitationsParams struct {
+	HouseholdID uuid.UUID
+	Limit       int32
+	Offset      int32
 }
 
-func (q *Queries) ListPendingInvitations(ctx context.Context, householdID uuid.UUID) ([]Invitation, error) {
+func (q *Queries) ListPendingInvitations(ctx context.Context, arg ListPendingInvitationsParams) ([]Invitation, error) {
 	rows, err := q.query(ctx,
 		`SELECT id, household_id, email, invited_by, token, status, expires_at, created_at
 		 FROM invitations
 		 WHERE household_id = $1 AND status = 'pending' AND expires_at > now()
-		 ORDER BY created_at DESC`,
-		householdID,
+		 ORDER BY created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		arg.HouseholdID, arg.Limit, arg.Offset,
 	)
 	if err != nil {
 		return nil, err
@@ -213,3 +312,13 @@ func (q *Queries) ListPendingInvitations(ctx context.Context, householdID uuid.U
 	}
 	return out, rows.Err()
 }
+
+func (q *Queries) CountPendingInvitations(ctx context.Context, householdID uuid.UUID) (int64, error) {
+	var count int64
+	err := q.queryRow(ctx,
+		`SELECT COUNT(*) FROM invitations
+		 WHERE household_id = $1 AND status = 'pending' AND expires_at > now()`,
+		householdID,
+	).Scan(&count)
+	return count, err
+}
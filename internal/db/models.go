@@ -8,7 +8,8 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// Enum types matching PostgreSQL enums
+// AccountType is free text: the built-in types below or one of the household's
+// account_types.
 type AccountType string
 
 const (
@@ -17,6 +18,7 @@ const (
 	AccountTypeCash    AccountType = "cash"
 )
 
+// Enum types matching PostgreSQL enums
 type TransactionType string
 
 const (
@@ -51,10 +53,14 @@ type User struct {
 }
 
 type Household struct {
-	ID        uuid.UUID          `json:"id"`
-	Name      string             `json:"name"`
-	OwnerID   uuid.UUID          `json:"owner_id"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID                uuid.UUID          `json:"id"`
+	Name              string             `json:"name"`
+	OwnerID           uuid.UUID          `json:"owner_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	Timezone          string             `json:"timezone"`
+	DefaultCurrency   string             `json:"default_currency"`
+	CaseSensitiveTags bool               `json:"case_sensitive_tags"`
+	Settings          []byte             `json:"settings"`
 }
 
 type HouseholdMember struct {
@@ -76,15 +82,21 @@ type Invitation struct {
 }
 
 type Account struct {
-	ID          uuid.UUID          `json:"id"`
-	HouseholdID uuid.UUID          `json:"household_id"`
-	Name        string             `json:"name"`
-	Type        AccountType        `json:"type"`
-	Balance     decimal.Decimal    `json:"balance"`
-	Currency    string             `json:"currency"`
-	CreatedBy   uuid.UUID          `json:"created_by"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ID              uuid.UUID           `json:"id"`
+	HouseholdID     uuid.UUID           `json:"household_id"`
+	Name            string              `json:"name"`
+	Type            AccountType         `json:"type"`
+	Balance         decimal.Decimal     `json:"balance"`
+	Currency        string              `json:"currency"`
+	CreatedBy       uuid.UUID           `json:"created_by"`
+	CreatedAt       pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz  `json:"updated_at"`
+	IncludeInTotals bool                `json:"include_in_totals"`
+	OpeningBalance  decimal.Decimal     `json:"opening_balance"`
+	Position        int32               `json:"position"`
+	UpdatedBy       pgtype.UUID         `json:"updated_by"`
+	IsLiability     bool                `json:"is_liability"`
+	DailyLimit      decimal.NullDecimal `json:"daily_limit"`
 }
 
 type Transaction struct {
@@ -101,14 +113,114 @@ type Transaction struct {
 	CreatedBy            uuid.UUID          `json:"created_by"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	Flagged              bool               `json:"flagged"`
+	FlagReason           pgtype.Text        `json:"flag_reason"`
+	Posted               bool               `json:"posted"`
 }
 
 type RefreshToken struct {
-	ID        uuid.UUID          `json:"id"`
-	UserID    uuid.UUID          `json:"user_id"`
-	TokenHash string             `json:"token_hash"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	TokenHash  string             `json:"token_hash"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	UserAgent  pgtype.Text        `json:"user_agent"`
+	IPAddress  pgtype.Text        `json:"ip_address"`
+	FamilyID   uuid.UUID          `json:"family_id"`
+	UsedAt     pgtype.Timestamptz `json:"used_at"`
+	ReplacedBy pgtype.UUID        `json:"replaced_by"`
+}
+
+type AuditLog struct {
+	ID          uuid.UUID          `json:"id"`
+	HouseholdID uuid.UUID          `json:"household_id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Action      string             `json:"action"`
+	EntityType  string             `json:"entity_type"`
+	EntityID    uuid.UUID          `json:"entity_id"`
+	Before      []byte             `json:"before"`
+	After       []byte             `json:"after"`
+	UndoneAt    pgtype.Timestamptz `json:"undone_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type Category struct {
+	ID          uuid.UUID          `json:"id"`
+	HouseholdID uuid.UUID          `json:"household_id"`
+	Name        string             `json:"name"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type TransactionSplit struct {
+	ID            uuid.UUID          `json:"id"`
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	CategoryID    uuid.UUID          `json:"category_id"`
+	Amount        decimal.Decimal    `json:"amount"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type Webhook struct {
+	ID          uuid.UUID          `json:"id"`
+	HouseholdID uuid.UUID          `json:"household_id"`
+	Url         string             `json:"url"`
+	Secret      string             `json:"secret"`
+	Events      []string           `json:"events"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type Budget struct {
+	ID          uuid.UUID          `json:"id"`
+	HouseholdID uuid.UUID          `json:"household_id"`
+	CategoryID  uuid.UUID          `json:"category_id"`
+	Amount      decimal.Decimal    `json:"amount"`
+	Thresholds  []int32            `json:"thresholds"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Notification struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	HouseholdID uuid.UUID          `json:"household_id"`
+	Kind        string             `json:"kind"`
+	Data        []byte             `json:"data"`
+	ReadAt      pgtype.Timestamptz `json:"read_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserID                    uuid.UUID           `json:"user_id"`
+	LargeTransactionThreshold decimal.NullDecimal `json:"large_transaction_threshold"`
+	MemberJoined              bool                `json:"member_joined"`
+	InvitationAccepted        bool                `json:"invitation_accepted"`
+	UpdatedAt                 pgtype.Timestamptz  `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID         uuid.UUID          `json:"id"`
+	WebhookID  uuid.UUID          `json:"webhook_id"`
+	Event      string             `json:"event"`
+	Payload    []byte             `json:"payload"`
+	Attempt    int32              `json:"attempt"`
+	StatusCode pgtype.Int4        `json:"status_code"`
+	Error      pgtype.Text        `json:"error"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type TransactionTemplate struct {
+	ID                   uuid.UUID           `json:"id"`
+	HouseholdID          uuid.UUID           `json:"household_id"`
+	Name                 string              `json:"name"`
+	Type                 TransactionType     `json:"type"`
+	Description          string              `json:"description"`
+	Amount               decimal.NullDecimal `json:"amount"`
+	AccountID            pgtype.UUID         `json:"account_id"`
+	DestinationAccountID pgtype.UUID         `json:"destination_account_id"`
+	Tags                 []string            `json:"tags"`
+	Note                 pgtype.Text         `json:"note"`
+	CreatedAt            pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz  `json:"updated_at"`
 }
 
 // Helper: convert time.Time to pgtype.Timestamptz
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// --- Notifications ---
+
+// notificationColumns lists the columns of the notifications table in scanNotification order.
+const notificationColumns = `n.id, n.user_id, n.household_id, n.kind, n.data, n.read_at, n.created_at`
+
+func scanNotification(row pgx.Row) (Notification, error) {
+	var n Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.HouseholdID, &n.Kind, &n.Data, &n.ReadAt, &n.CreatedAt)
+	return n, err
+}
+
+type CreateLargeTransactionNotificationsParams struct {
+	HouseholdID uuid.UUID
+	ActorID     uuid.UUID
+	Amount      decimal.Decimal
+	Data        []byte
+}
+
+// CreateLargeTransactionNotifications notifies the household's other members
+// whose threshold the amount reaches.
+func (q *Queries) CreateLargeTransactionNotifications(ctx context.Context, arg CreateLargeTransactionNotificationsParams) (int64, error) {
+	return q.execRows(ctx,
+		`INSERT INTO notifications (user_id, household_id, kind, data)
+		 SELECT hm.user_id, hm.household_id, 'large_transaction', $4
+		 FROM household_members hm
+		 JOIN notification_preferences np ON np.user_id = hm.user_id
+		 WHERE hm.household_id = $1
+		   AND hm.user_id <> $2
+		   AND np.large_transaction_threshold IS NOT NULL
+		   AND $3 >= np.large_transaction_threshold`,
+		arg.HouseholdID, arg.ActorID, arg.Amount, arg.Data,
+	)
+}
+
+type CreateMemberJoinedNotificationsParams struct {
+	HouseholdID uuid.UUID
+	MemberID    uuid.UUID
+	InviterID   uuid.UUID
+	Data        []byte
+}
+
+// CreateMemberJoinedNotifications notifies the household's members other than
+// the new member and the inviter (who is told separately) unless they opted out.
+func (q *Queries) CreateMemberJoinedNotifications(ctx context.Context, arg CreateMemberJoinedNotificationsParams) (int64, error) {
+	return q.execRows(ctx,
+		`INSERT INTO notifications (user_id, household_id, kind, data)
+		 SELECT hm.user_id, hm.household_id, 'member_joined', $4
+		 FROM household_members hm
+		 LEFT JOIN notification_preferences np ON np.user_id = hm.user_id
+		 WHERE hm.household_id = $1
+		   AND hm.user_id <> $2
+		   AND hm.user_id <> $3
+		   AND COALESCE(np.member_joined, true)`,
+		arg.HouseholdID, arg.MemberID, arg.InviterID, arg.Data,
+	)
+}
+
+type CreateInvitationAcceptedNotificationParams struct {
+	HouseholdID uuid.UUID
+	InviterID   uuid.UUID
+	Data        []byte
+}
+
+// CreateInvitationAcceptedNotification notifies the inviter if they are still a
+// member and haven't opted out.
+func (q *Queries) CreateInvitationAcceptedNotification(ctx context.Context, arg CreateInvitationAcceptedNotificationParams) (int64, error) {
+	return q.execRows(ctx,
+		`INSERT INTO notifications (user_id, household_id, kind, data)
+		 SELECT hm.user_id, hm.household_id, 'invitation_accepted', $3
+		 FROM household_members hm
+		 LEFT JOIN notification_preferences np ON np.user_id = hm.user_id
+		 WHERE hm.household_id = $1
+		   AND hm.user_id = $2
+		   AND COALESCE(np.invitation_accepted, true)`,
+		arg.HouseholdID, arg.InviterID, arg.Data,
+	)
+}
+
+type CreateBudgetNotificationsParams struct {
+	HouseholdID uuid.UUID
+	Data        []byte
+}
+
+// CreateBudgetNotifications notifies every member of the household.
+func (q *Queries) CreateBudgetNotifications(ctx context.Context, arg CreateBudgetNotificationsParams) (int64, error) {
+	return q.execRows(ctx,
+		`INSERT INTO notifications (user_id, household_id, kind, data)
+		 SELECT hm.user_id, hm.household_id, 'budget_threshold', $2
+		 FROM household_members hm
+		 WHERE hm.household_id = $1`,
+		arg.HouseholdID, arg.Data,
+	)
+}
+
+type ListNotificationsParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int32
+	Offset     int32
+}
+
+// ListNotifications returns the user's notifications from households they still
+// belong to, newest first.
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	rows, err := q.query(ctx,
+		`SELECT `+notificationColumns+`
+		 FROM notifications n
+		 JOIN household_members hm ON hm.household_id = n.household_id AND hm.user_id = n.user_id
+		 WHERE n.user_id = $1
+		   AND (NOT $2 OR n.read_at IS NULL)
+		 ORDER BY n.created_at DESC
+		 LIMIT $3 OFFSET $4`,
+		arg.UserID, arg.UnreadOnly, arg.Limit, arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+type CountNotificationsParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+}
+
+func (q *Queries) CountNotifications(ctx context.Context, arg CountNotificationsParams) (int64, error) {
+	row := q.queryRow(ctx,
+		`SELECT COUNT(*)
+		 FROM notifications n
+		 JOIN household_members hm ON hm.household_id = n.household_id AND hm.user_id = n.user_id
+		 WHERE n.user_id = $1
+		   AND (NOT $2 OR n.read_at IS NULL)`,
+		arg.UserID, arg.UnreadOnly,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+type MarkNotificationReadParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// MarkNotificationRead sets read_at once; marking a read notification again
+// keeps the original time.
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error) {
+	row := q.queryRow(ctx,
+		`UPDATE notifications n SET read_at = COALESCE(n.read_at, now())
+		 FROM household_members hm
+		 WHERE n.id = $1 AND n.user_id = $2
+		   AND hm.household_id = n.household_id AND hm.user_id = n.user_id
+		 RETURNING `+notificationColumns,
+		arg.ID, arg.UserID,
+	)
+	return scanNotification(row)
+}
+
+// --- Notification preferences ---
+
+func (q *Queries) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (NotificationPreference, error) {
+	row := q.queryRow(ctx,
+		`SELECT user_id, large_transaction_threshold, member_joined, invitation_accepted, updated_at
+		 FROM notification_preferences WHERE user_id = $1`,
+		userID,
+	)
+	var p NotificationPreference
+	err := row.Scan(&p.UserID, &p.LargeTransactionThreshold, &p.MemberJoined, &p.InvitationAccepted, &p.UpdatedAt)
+	return p, err
+}
+
+type UpsertNotificationPreferencesParams struct {
+	UserID                    uuid.UUID
+	LargeTransactionThreshold decimal.NullDecimal
+	MemberJoined              bool
+	InvitationAccepted        bool
+}
+
+func (q *Queries) UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO notification_preferences (user_id, large_transaction_threshold, member_joined, invitation_accepted)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET large_transaction_threshold = EXCLUDED.large_transaction_threshold,
+		     member_joined = EXCLUDED.member_joined,
+		     invitation_accepted = EXCLUDED.invitation_accepted,
+		     updated_at = now()
+		 RETURNING user_id, large_transaction_threshold, member_joined, invitation_accepted, updated_at`,
+		arg.UserID, arg.LargeTransactionThreshold, arg.MemberJoined, arg.InvitationAccepted,
+	)
+	var p NotificationPreference
+	err := row.Scan(&p.UserID, &p.LargeTransactionThreshold, &p.MemberJoined, &p.InvitationAccepted, &p.UpdatedAt)
+	return p, err
+}
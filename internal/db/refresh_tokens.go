@@ -5,39 +5,144 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// refreshTokenColumns lists the columns of the refresh_tokens table in scanRefreshToken order.
+const refreshTokenColumns = `id, user_id, token_hash, expires_at, created_at, last_used_at, user_agent, ip_address, family_id, used_at, replaced_by`
+
+func scanRefreshToken(row pgx.Row) (RefreshToken, error) {
+	var rt RefreshToken
+	err := row.Scan(&rt.ID, &rt.UserID, &rt.TokenHash, &rt.ExpiresAt, &rt.CreatedAt, &rt.LastUsedAt, &rt.UserAgent, &rt.IPAddress, &rt.FamilyID, &rt.UsedAt, &rt.ReplacedBy)
+	return rt, err
+}
+
 type CreateRefreshTokenParams struct {
 	UserID    uuid.UUID
 	TokenHash string
 	ExpiresAt time.Time
+	UserAgent pgtype.Text
+	IPAddress pgtype.Text
+	FamilyID  uuid.UUID
 }
 
-func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
-	return q.exec(ctx,
-		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
-		arg.UserID, arg.TokenHash, arg.ExpiresAt,
+// CreateRefreshToken stores a refresh token and returns its ID.
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (uuid.UUID, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at, user_agent, ip_address, family_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id`,
+		arg.UserID, arg.TokenHash, arg.ExpiresAt, arg.UserAgent, arg.IPAddress, arg.FamilyID,
 	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+type MarkRefreshTokenUsedParams struct {
+	ID         uuid.UUID
+	ReplacedBy uuid.UUID
+}
+
+// MarkRefreshTokenUsed records that a token was rotated. It affects no rows if
+// the token was already used, which lets concurrent replays be detected.
+func (q *Queries) MarkRefreshTokenUsed(ctx context.Context, arg MarkRefreshTokenUsedParams) (int64, error) {
+	return q.execRows(ctx,
+		`UPDATE refresh_tokens SET used_at = now(), replaced_by = $2
+		 WHERE id = $1 AND used_at IS NULL`,
+		arg.ID, arg.ReplacedBy,
+	)
+}
+
+// DeleteRefreshTokenFamily removes every token rotated from the same login.
+func (q *Queries) DeleteRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	return q.exec(ctx, `DELETE FROM refresh_tokens WHERE family_id = $1`, familyID)
 }
 
 func (q *Queries) GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error) {
 	row := q.queryRow(ctx,
-		`SELECT id, user_id, token_hash, expires_at, created_at FROM refresh_tokens WHERE token_hash = $1`,
+		`SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE token_hash = $1`,
 		tokenHash,
 	)
-	var rt RefreshToken
-	err := row.Scan(&rt.ID, &rt.UserID, &rt.TokenHash, &rt.ExpiresAt, &rt.CreatedAt)
-	return rt, err
+	return scanRefreshToken(row)
 }
 
+// ListRefreshTokensByUser returns the user's unexpired, unrotated refresh tokens, most recently used first.
+func (q *Queries) ListRefreshTokensByUser(ctx context.Context, userID uuid.UUID) ([]RefreshToken, error) {
+	rows, err := q.query(ctx,
+		`SELECT `+refreshTokenColumns+`
+		 FROM refresh_tokens
+		 WHERE user_id = $1 AND expires_at > now() AND used_at IS NULL
+		 ORDER BY last_used_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []RefreshToken
+	for rows.Next() {
+		rt, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rt)
+	}
+	return out, rows.Err()
+}
+
+type DeleteUserRefreshTokenParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// DeleteUserRefreshToken removes one of the user's refresh tokens by ID, along
+// with the rest of its family.
+func (q *Queries) DeleteUserRefreshToken(ctx context.Context, arg DeleteUserRefreshTokenParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM refresh_tokens
+		 WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE id = $1 AND user_id = $2)`,
+		arg.ID, arg.UserID,
+	)
+}
+
+type DeleteUserRefreshTokenByHashParams struct {
+	TokenHash string
+	UserID    uuid.UUID
+}
+
+// DeleteUserRefreshTokenByHash removes one of the user's refresh tokens by hash,
+// along with the rest of its family.
+func (q *Queries) DeleteUserRefreshTokenByHash(ctx context.Context, arg DeleteUserRefreshTokenByHashParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM refresh_tokens
+		 WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND user_id = $2)`,
+		arg.TokenHash, arg.UserID,
+	)
+}
+
+// DeleteRefreshToken removes the token with the given hash along with the rest
+// of its family.
 func (q *Queries) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
-	return q.exec(ctx, `DELETE FROM refresh_tokens WHERE token_hash = $1`, tokenHash)
+	return q.exec(ctx,
+		`DELETE FROM refresh_tokens
+		 WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)`,
+		tokenHash,
+	)
 }
 
 func (q *Queries) DeleteUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
 	return q.exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID)
 }
 
-func (q *Queries) DeleteExpiredRefreshTokens(ctx context.Context) error {
-	return q.exec(ctx, `DELETE FROM refresh_tokens WHERE expires_at < now()`)
+func (q *Queries) DeleteExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	return q.execRows(ctx, `DELETE FROM refresh_tokens WHERE expires_at < now()`)
+}
+
+// DeleteIdleRefreshTokens removes live tokens not used since idleBefore. Rotated
+// tokens are kept until they expire so that replaying them is still detected.
+func (q *Queries) DeleteIdleRefreshTokens(ctx context.Context, idleBefore time.Time) (int64, error) {
+	return q.execRows(ctx, `DELETE FROM refresh_tokens WHERE last_used_at < $1 AND used_at IS NULL`, idleBefore)
 }
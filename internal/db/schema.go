@@ -0,0 +1,165 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSchemaOutdated = errors.New("database schema is not migrated")
+	ErrSchemaDirty    = errors.New("database schema is dirty: a migration failed halfway")
+)
+
+// migrationLockID is the advisory lock held while migrating, so instances
+// starting together don't apply the same migration twice.
+const migrationLockID = 0x686f57616c6c6574 // "hoWallet"
+
+var upMigrationName = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+type migration struct {
+	version uint64
+	file    string
+}
+
+// upMigrations lists the up migrations in fsys, oldest first.
+func upMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var out []migration
+	for _, e := range entries {
+		m := upMigrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		out = append(out, migration{version: v, file: e.Name()})
+	}
+	slices.SortFunc(out, func(a, b migration) int { return cmp.Compare(a.version, b.version) })
+	return out, nil
+}
+
+// LatestMigration returns the version of the newest up migration in fsys.
+func LatestMigration(fsys fs.FS) (uint64, error) {
+	ms, err := upMigrations(fsys)
+	if err != nil {
+		return 0, err
+	}
+	if len(ms) == 0 {
+		return 0, errors.New("no migrations found")
+	}
+	return ms[len(ms)-1].version, nil
+}
+
+type schemaConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// schemaVersion reads golang-migrate's schema_migrations table; a database
+// without one is at version 0.
+func schemaVersion(ctx context.Context, conn schemaConn) (version uint64, dirty bool, err error) {
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return 0, false, err
+	}
+	if !exists {
+		return 0, false, nil
+	}
+	err = conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// CheckSchema reports whether the database is migrated to at least want.
+func CheckSchema(ctx context.Context, pool *pgxpool.Pool, want uint64) error {
+	version, dirty, err := schemaVersion(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w (version %d)", ErrSchemaDirty, version)
+	}
+	if version < want {
+		return fmt.Errorf("%w: at version %d, want %d", ErrSchemaOutdated, version, want)
+	}
+	return nil
+}
+
+// Migrate applies the up migrations in fsys newer than the database's version
+// and returns how many it applied. Each runs in its own transaction together
+// with the version bump, and the bookkeeping matches golang-migrate's, so the
+// two can be used interchangeably.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) (int, error) {
+	ms, err := upMigrations(fsys)
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, int64(migrationLockID)); err != nil {
+		return 0, fmt.Errorf("lock migrations: %w", err)
+	}
+	defer func() {
+		// Also released when the session ends, so a failure here is harmless.
+		_, _ = conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, int64(migrationLockID))
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return 0, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	current, dirty, err := schemaVersion(ctx, conn)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w (version %d)", ErrSchemaDirty, current)
+	}
+
+	applied := 0
+	for _, m := range ms {
+		if m.version <= current {
+			continue
+		}
+		sql, err := fs.ReadFile(fsys, m.file)
+		if err != nil {
+			return applied, err
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, int64(m.version))
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %s: %w", m.file, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
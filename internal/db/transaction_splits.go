@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// --- Transaction splits ---
+
+type CreateTransactionSplitParams struct {
+	TransactionID uuid.UUID
+	CategoryID    uuid.UUID
+	Amount        decimal.Decimal
+}
+
+func (q *Queries) CreateTransactionSplit(ctx context.Context, arg CreateTransactionSplitParams) (TransactionSplit, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO transaction_splits (transaction_id, category_id, amount)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, transaction_id, category_id, amount, created_at`,
+		arg.TransactionID, arg.CategoryID, arg.Amount,
+	)
+	var s TransactionSplit
+	err := row.Scan(&s.ID, &s.TransactionID, &s.CategoryID, &s.Amount, &s.CreatedAt)
+	return s, err
+}
+
+func (q *Queries) ListTransactionSplits(ctx context.Context, transactionID uuid.UUID) ([]TransactionSplit, error) {
+	rows, err := q.query(ctx,
+		`SELECT id, transaction_id, category_id, amount, created_at
+		 FROM transaction_splits WHERE transaction_id = $1
+		 ORDER BY created_at, id`,
+		transactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TransactionSplit
+	for rows.Next() {
+		var s TransactionSplit
+		if err := rows.Scan(&s.ID, &s.TransactionID, &s.CategoryID, &s.Amount, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (q *Queries) DeleteTransactionSplits(ctx context.Context, transactionID uuid.UUID) error {
+	return q.exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, transactionID)
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// --- Transaction templates ---
+
+// transactionTemplateColumns lists the columns of transaction_templates in
+// scanTransactionTemplate order.
+const transactionTemplateColumns = `id, household_id, name, type, description, amount,
+			account_id, destination_account_id, tags, note, created_at, updated_at`
+
+func scanTransactionTemplate(row pgx.Row) (TransactionTemplate, error) {
+	var t TransactionTemplate
+	err := row.Scan(
+		&t.ID, &t.HouseholdID, &t.Name, &t.Type, &t.Description, &t.Amount,
+		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note, &t.CreatedAt, &t.UpdatedAt,
+	)
+	return t, err
+}
+
+type CreateTransactionTemplateParams struct {
+	HouseholdID          uuid.UUID
+	Name                 string
+	Type                 TransactionType
+	Description          string
+	Amount               decimal.NullDecimal
+	AccountID            pgtype.UUID
+	DestinationAccountID pgtype.UUID
+	Tags                 []string
+	Note                 pgtype.Text
+}
+
+func (q *Queries) CreateTransactionTemplate(ctx context.Context, arg CreateTransactionTemplateParams) (TransactionTemplate, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO transaction_templates (
+			household_id, name, type, description, amount,
+			account_id, destination_account_id, tags, note
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+transactionTemplateColumns,
+		arg.HouseholdID, arg.Name, arg.Type, arg.Description, arg.Amount,
+		arg.AccountID, arg.DestinationAccountID, arg.Tags, arg.Note,
+	)
+	return scanTransactionTemplate(row)
+}
+
+type GetTransactionTemplateParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) GetTransactionTemplate(ctx context.Context, arg GetTransactionTemplateParams) (TransactionTemplate, error) {
+	row := q.queryRow(ctx,
+		`SELECT `+transactionTemplateColumns+`
+		 FROM transaction_templates WHERE id = $1 AND household_id = $2`,
+		arg.ID, arg.HouseholdID,
+	)
+	return scanTransactionTemplate(row)
+}
+
+func (q *Queries) ListTransactionTemplates(ctx context.Context, householdID uuid.UUID) ([]TransactionTemplate, error) {
+	rows, err := q.query(ctx,
+		`SELECT `+transactionTemplateColumns+`
+		 FROM transaction_templates WHERE household_id = $1
+		 ORDER BY name`,
+		householdID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TransactionTemplate
+	for rows.Next() {
+		t, err := scanTransactionTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+type UpdateTransactionTemplateParams struct {
+	ID                   uuid.UUID
+	HouseholdID          uuid.UUID
+	Name                 string
+	Type                 TransactionType
+	Description          string
+	Amount               decimal.NullDecimal
+	AccountID            pgtype.UUID
+	DestinationAccountID pgtype.UUID
+	Tags                 []string
+	Note                 pgtype.Text
+}
+
+// UpdateTransactionTemplate replaces every editable field of a template.
+func (q *Queries) UpdateTransactionTemplate(ctx context.Context, arg UpdateTransactionTemplateParams) (TransactionTemplate, error) {
+	row := q.queryRow(ctx,
+		`UPDATE transaction_templates
+		 SET name = $3, type = $4, description = $5, amount = $6,
+		     account_id = $7, destination_account_id = $8, tags = $9, note = $10
+		 WHERE id = $1 AND household_id = $2
+		 RETURNING `+transactionTemplateColumns,
+		arg.ID, arg.HouseholdID, arg.Name, arg.Type, arg.Description, arg.Amount,
+		arg.AccountID, arg.DestinationAccountID, arg.Tags, arg.Note,
+	)
+	return scanTransactionTemplate(row)
+}
+
+type DeleteTransactionTemplateParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) DeleteTransactionTemplate(ctx context.Context, arg DeleteTransactionTemplateParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM transaction_templates WHERE id = $1 AND household_id = $2`,
+		arg.ID, arg.HouseholdID,
+	)
+}
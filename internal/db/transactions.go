@@ -2,12 +2,31 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
+// transactionColumns lists the columns of the transactions table in scanTransaction order.
+const transactionColumns = `id, household_id, type, description, amount,
+			account_id, destination_account_id, tags, note,
+			transacted_at, created_by, created_at, updated_at,
+			flagged, flag_reason, posted`
+
+func scanTransaction(row pgx.Row) (Transaction, error) {
+	var t Transaction
+	err := row.Scan(
+		&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
+		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
+		&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+		&t.Flagged, &t.FlagReason, &t.Posted,
+	)
+	return t, err
+}
+
 type CreateTransactionParams struct {
 	HouseholdID          uuid.UUID
 	Type                 TransactionType
@@ -19,6 +38,7 @@ type CreateTransactionParams struct {
 	Note                 pgtype.Text
 	TransactedAt         pgtype.Timestamptz
 	CreatedBy            uuid.UUID
+	Posted               bool
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -26,23 +46,15 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		`INSERT INTO transactions (
 			household_id, type, description, amount,
 			account_id, destination_account_id, tags, note,
-			transacted_at, created_by
+			transacted_at, created_by, posted
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, household_id, type, description, amount,
-			account_id, destination_account_id, tags, note,
-			transacted_at, created_by, created_at, updated_at`,
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+transactionColumns,
 		arg.HouseholdID, arg.Type, arg.Description, arg.Amount,
 		arg.AccountID, arg.DestinationAccountID, arg.Tags, arg.Note,
-		arg.TransactedAt, arg.CreatedBy,
-	)
-	var t Transaction
-	err := row.Scan(
-		&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
-		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
-		&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+		arg.TransactedAt, arg.CreatedBy, arg.Posted,
 	)
-	return t, err
+	return scanTransaction(row)
 }
 
 type GetTransactionParams struct {
@@ -52,19 +64,11 @@ type GetTransactionParams struct {
 
 func (q *Queries) GetTransaction(ctx context.Context, arg GetTransactionParams) (Transaction, error) {
 	row := q.queryRow(ctx,
-		`SELECT id, household_id, type, description, amount,
-			account_id, destination_account_id, tags, note,
-			transacted_at, created_by, created_at, updated_at
+		`SELECT `+transactionColumns+`
 		 FROM transactions WHERE id = $1 AND household_id = $2`,
 		arg.ID, arg.HouseholdID,
 	)
-	var t Transaction
-	err := row.Scan(
-		&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
-		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
-		&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
-	)
-	return t, err
+	return scanTransaction(row)
 }
 
 type ListTransactionsParams struct {
@@ -72,26 +76,30 @@ type ListTransactionsParams struct {
 	Column2     pgtype.Timestamptz // from
 	Column3     pgtype.Timestamptz // to
 	Column4     pgtype.Text        // type filter
-	Column5     pgtype.UUID        // account filter
+	Column5     []uuid.UUID        // account filter (any of)
+	Column6     pgtype.Bool        // flagged filter
+	Column7     pgtype.UUID        // created_by filter
+	Column8     pgtype.Bool        // posted filter
 	Limit       int32
 	Offset      int32
 }
 
 func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]Transaction, error) {
 	rows, err := q.query(ctx,
-		`SELECT id, household_id, type, description, amount,
-			account_id, destination_account_id, tags, note,
-			transacted_at, created_by, created_at, updated_at
+		`SELECT `+transactionColumns+`
 		 FROM transactions
 		 WHERE household_id = $1
 		   AND ($2::timestamptz IS NULL OR transacted_at >= $2)
 		   AND ($3::timestamptz IS NULL OR transacted_at <= $3)
 		   AND ($4::transaction_type IS NULL OR type = $4)
-		   AND ($5::uuid IS NULL OR account_id = $5 OR destination_account_id = $5)
+		   AND ($5::uuid[] IS NULL OR account_id = ANY($5) OR destination_account_id = ANY($5))
+		   AND ($6::boolean IS NULL OR flagged = $6)
+		   AND ($7::uuid IS NULL OR created_by = $7)
+		   AND ($8::boolean IS NULL OR posted = $8)
 		 ORDER BY transacted_at DESC
-		 LIMIT $6 OFFSET $7`,
+		 LIMIT $9 OFFSET $10`,
 		arg.HouseholdID, arg.Column2, arg.Column3, arg.Column4, arg.Column5,
-		arg.Limit, arg.Offset,
+		arg.Column6, arg.Column7, arg.Column8, arg.Limit, arg.Offset,
 	)
 	if err != nil {
 		return nil, err
@@ -100,12 +108,8 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 
 	var out []Transaction
 	for rows.Next() {
-		var t Transaction
-		if err := rows.Scan(
-			&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
-			&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
-			&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
-		); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, t)
@@ -118,7 +122,10 @@ type CountTransactionsParams struct {
 	Column2     pgtype.Timestamptz
 	Column3     pgtype.Timestamptz
 	Column4     pgtype.Text
-	Column5     pgtype.UUID
+	Column5     []uuid.UUID
+	Column6     pgtype.Bool
+	Column7     pgtype.UUID
+	Column8     pgtype.Bool
 }
 
 func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsParams) (int64, error) {
@@ -129,12 +136,343 @@ func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsPa
 		   AND ($2::timestamptz IS NULL OR transacted_at >= $2)
 		   AND ($3::timestamptz IS NULL OR transacted_at <= $3)
 		   AND ($4::transaction_type IS NULL OR type = $4)
-		   AND ($5::uuid IS NULL OR account_id = $5 OR destination_account_id = $5)`,
+		   AND ($5::uuid[] IS NULL OR account_id = ANY($5) OR destination_account_id = ANY($5))
+		   AND ($6::boolean IS NULL OR flagged = $6)
+		   AND ($7::uuid IS NULL OR created_by = $7)
+		   AND ($8::boolean IS NULL OR posted = $8)`,
+		arg.HouseholdID, arg.Column2, arg.Column3, arg.Column4, arg.Column5,
+		arg.Column6, arg.Column7, arg.Column8,
+	).Scan(&count)
+	return count, err
+}
+
+type SummarizeTransactionsParams struct {
+	HouseholdID uuid.UUID
+	Column2     pgtype.Timestamptz
+	Column3     pgtype.Timestamptz
+	Column4     pgtype.Text
+	Column5     []uuid.UUID
+	Column6     pgtype.Bool
+	Column7     pgtype.UUID
+	Column8     pgtype.Bool
+}
+
+type SummarizeTransactionsRow struct {
+	Currency     string
+	IncomeTotal  decimal.Decimal
+	ExpenseTotal decimal.Decimal
+	Net          decimal.Decimal
+	Count        int64
+}
+
+// SummarizeTransactions totals income and expense per account currency over the same
+// filters as ListTransactions. Transfers are counted but don't contribute to the sums.
+func (q *Queries) SummarizeTransactions(ctx context.Context, arg SummarizeTransactionsParams) ([]SummarizeTransactionsRow, error) {
+	rows, err := q.query(ctx,
+		`SELECT a.currency,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total,
+		        COALESCE(SUM(CASE t.type WHEN 'income' THEN t.amount
+		                                 WHEN 'expense' THEN -t.amount END), 0) AS net,
+		        COUNT(*)
+		 FROM transactions t
+		 JOIN accounts a ON a.id = t.account_id
+		 WHERE t.household_id = $1
+		   AND ($2::timestamptz IS NULL OR t.transacted_at >= $2)
+		   AND ($3::timestamptz IS NULL OR t.transacted_at <= $3)
+		   AND ($4::transaction_type IS NULL OR t.type = $4)
+		   AND ($5::uuid[] IS NULL OR t.account_id = ANY($5) OR t.destination_account_id = ANY($5))
+		   AND ($6::boolean IS NULL OR t.flagged = $6)
+		   AND ($7::uuid IS NULL OR t.created_by = $7)
+		   AND ($8::boolean IS NULL OR t.posted = $8)
+		 GROUP BY a.currency
+		 ORDER BY a.currency`,
 		arg.HouseholdID, arg.Column2, arg.Column3, arg.Column4, arg.Column5,
+		arg.Column6, arg.Column7, arg.Column8,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SummarizeTransactionsRow
+	for rows.Next() {
+		var r SummarizeTransactionsRow
+		if err := rows.Scan(&r.Currency, &r.IncomeTotal, &r.ExpenseTotal, &r.Net, &r.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type SumTransactionsByMemberParams struct {
+	HouseholdID uuid.UUID
+	Column2     pgtype.Timestamptz
+	Column3     pgtype.Timestamptz
+}
+
+type SumTransactionsByMemberRow struct {
+	UserID       uuid.UUID
+	UserName     string
+	UserEmail    string
+	AccountID    uuid.UUID
+	AccountName  string
+	Currency     string
+	IncomeTotal  decimal.Decimal
+	ExpenseTotal decimal.Decimal
+	Count        int64
+}
+
+// SumTransactionsByMember totals income and expense per creator and account.
+// Transfers are left out entirely.
+func (q *Queries) SumTransactionsByMember(ctx context.Context, arg SumTransactionsByMemberParams) ([]SumTransactionsByMemberRow, error) {
+	rows, err := q.query(ctx,
+		`SELECT t.created_by, u.name, u.email, t.account_id, a.name, a.currency,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total,
+		        COUNT(*)
+		 FROM transactions t
+		 JOIN users u ON u.id = t.created_by
+		 JOIN accounts a ON a.id = t.account_id
+		 WHERE t.household_id = $1
+		   AND t.type <> 'transfer'
+		   AND ($2::timestamptz IS NULL OR t.transacted_at >= $2)
+		   AND ($3::timestamptz IS NULL OR t.transacted_at <= $3)
+		 GROUP BY t.created_by, u.name, u.email, t.account_id, a.name, a.currency
+		 ORDER BY u.name, u.email, a.name`,
+		arg.HouseholdID, arg.Column2, arg.Column3,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SumTransactionsByMemberRow
+	for rows.Next() {
+		var r SumTransactionsByMemberRow
+		if err := rows.Scan(&r.UserID, &r.UserName, &r.UserEmail, &r.AccountID, &r.AccountName,
+			&r.Currency, &r.IncomeTotal, &r.ExpenseTotal, &r.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type SumTransactionsByDayParams struct {
+	HouseholdID uuid.UUID
+	Timezone    string
+	Currency    string
+	From        time.Time
+	To          time.Time
+}
+
+type SumTransactionsByDayRow struct {
+	Day          time.Time
+	IncomeTotal  decimal.Decimal
+	ExpenseTotal decimal.Decimal
+}
+
+// SumTransactionsByDay totals income and expense per calendar day in the given
+// timezone, for accounts in one currency. Days without transactions are absent.
+func (q *Queries) SumTransactionsByDay(ctx context.Context, arg SumTransactionsByDayParams) ([]SumTransactionsByDayRow, error) {
+	rows, err := q.query(ctx,
+		`SELECT date_trunc('day', t.transacted_at AT TIME ZONE $2)::date AS day,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+		        COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total
+		 FROM transactions t
+		 JOIN accounts a ON a.id = t.account_id
+		 WHERE t.household_id = $1
+		   AND t.type <> 'transfer'
+		   AND a.currency = $3
+		   AND t.transacted_at >= $4
+		   AND t.transacted_at < $5
+		 GROUP BY day
+		 ORDER BY day`,
+		arg.HouseholdID, arg.Timezone, arg.Currency, arg.From, arg.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SumTransactionsByDayRow
+	for rows.Next() {
+		var r SumTransactionsByDayRow
+		if err := rows.Scan(&r.Day, &r.IncomeTotal, &r.ExpenseTotal); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type SearchTransactionsParams struct {
+	HouseholdID uuid.UUID
+	Query       string
+	Limit       int32
+}
+
+// SearchTransactions returns the household's newest transactions whose
+// description, note or one of whose tags contains Query, ignoring case.
+func (q *Queries) SearchTransactions(ctx context.Context, arg SearchTransactionsParams) ([]Transaction, error) {
+	rows, err := q.query(ctx,
+		`SELECT `+transactionColumns+`
+		 FROM transactions
+		 WHERE household_id = $1
+		   AND (strpos(lower(description), lower($2)) > 0
+		     OR strpos(lower(COALESCE(note, '')), lower($2)) > 0
+		     OR EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE strpos(lower(tag), lower($2)) > 0))
+		 ORDER BY transacted_at DESC
+		 LIMIT $3`,
+		arg.HouseholdID, arg.Query, arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+type ListDistinctTagsParams struct {
+	HouseholdID uuid.UUID
+	Column2     pgtype.Text // case-insensitive prefix
+}
+
+func (q *Queries) ListDistinctTags(ctx context.Context, arg ListDistinctTagsParams) ([]string, error) {
+	rows, err := q.query(ctx,
+		`SELECT DISTINCT tag
+		 FROM transactions, unnest(tags) AS tag
+		 WHERE household_id = $1
+		   AND ($2::text IS NULL OR starts_with(lower(tag), lower($2)))
+		 ORDER BY 1`,
+		arg.HouseholdID, arg.Column2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		out = append(out, tag)
+	}
+	return out, rows.Err()
+}
+
+type RenameTagParams struct {
+	HouseholdID uuid.UUID
+	From        string
+	To          string
+}
+
+// RenameTag replaces a tag in every transaction of the household that has it. When the
+// new tag is already present the array is de-duplicated, keeping first occurrences.
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (int64, error) {
+	return q.execRows(ctx,
+		`UPDATE transactions
+		 SET tags = ARRAY(
+		     SELECT tag
+		     FROM unnest(array_replace(tags, $2, $3)) WITH ORDINALITY AS u(tag, ord)
+		     GROUP BY tag
+		     ORDER BY min(ord)
+		 )
+		 WHERE household_id = $1 AND $2 = ANY(tags)`,
+		arg.HouseholdID, arg.From, arg.To,
+	)
+}
+
+type AccountTransactionsParams struct {
+	HouseholdID uuid.UUID
+	AccountID   uuid.UUID
+}
+
+// AccountNetEffect sums what the household's posted transactions have added to
+// (or taken from) an account's balance, counting both sides of transfers.
+func (q *Queries) AccountNetEffect(ctx context.Context, arg AccountTransactionsParams) (decimal.Decimal, error) {
+	var net decimal.Decimal
+	err := q.queryRow(ctx,
+		`SELECT COALESCE(SUM(CASE
+		            WHEN account_id = $2 AND destination_account_id = $2 THEN 0
+		            WHEN account_id = $2 AND type = 'income' THEN amount
+		            WHEN account_id = $2 THEN -amount
+		            ELSE amount -- incoming transfer
+		        END), 0)
+		 FROM transactions
+		 WHERE household_id = $1 AND (account_id = $2 OR destination_account_id = $2)
+		   AND posted`,
+		arg.HouseholdID, arg.AccountID,
+	).Scan(&net)
+	return net, err
+}
+
+type SumAccountOutflowParams struct {
+	HouseholdID uuid.UUID
+	AccountID   uuid.UUID
+	From        pgtype.Timestamptz
+	To          pgtype.Timestamptz
+}
+
+// SumAccountOutflow totals the expenses and outgoing transfers from an account
+// transacted in [From, To).
+func (q *Queries) SumAccountOutflow(ctx context.Context, arg SumAccountOutflowParams) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := q.queryRow(ctx,
+		`SELECT COALESCE(SUM(amount), 0)
+		 FROM transactions
+		 WHERE household_id = $1 AND account_id = $2
+		   AND type IN ('expense', 'transfer')
+		   AND transacted_at >= $3 AND transacted_at < $4`,
+		arg.HouseholdID, arg.AccountID, arg.From, arg.To,
+	).Scan(&total)
+	return total, err
+}
+
+type ReassignTransactionsParams struct {
+	HouseholdID uuid.UUID
+	FromAccount uuid.UUID
+	ToAccount   uuid.UUID
+}
+
+// CountTransfersBetween counts transfers in either direction between the two accounts.
+func (q *Queries) CountTransfersBetween(ctx context.Context, arg ReassignTransactionsParams) (int64, error) {
+	var count int64
+	err := q.queryRow(ctx,
+		`SELECT COUNT(*) FROM transactions
+		 WHERE household_id = $1 AND type = 'transfer'
+		   AND ((account_id = $2 AND destination_account_id = $3)
+		     OR (account_id = $3 AND destination_account_id = $2))`,
+		arg.HouseholdID, arg.FromAccount, arg.ToAccount,
 	).Scan(&count)
 	return count, err
 }
 
+// ReassignTransactions moves every reference to FromAccount, as source or as
+// transfer destination, to ToAccount. Balances are left to the caller.
+func (q *Queries) ReassignTransactions(ctx context.Context, arg ReassignTransactionsParams) (int64, error) {
+	return q.execRows(ctx,
+		`UPDATE transactions
+		 SET account_id = CASE WHEN account_id = $2 THEN $3 ELSE account_id END,
+		     destination_account_id = CASE WHEN destination_account_id = $2 THEN $3 ELSE destination_account_id END
+		 WHERE household_id = $1 AND (account_id = $2 OR destination_account_id = $2)`,
+		arg.HouseholdID, arg.FromAccount, arg.ToAccount,
+	)
+}
+
 type UpdateTransactionParams struct {
 	ID                   uuid.UUID
 	HouseholdID          uuid.UUID
@@ -146,6 +484,13 @@ type UpdateTransactionParams struct {
 	Note                 pgtype.Text
 	TransactedAt         pgtype.Timestamptz
 	Type                 TransactionType
+	// ExpectedUpdatedAt, when set, makes the update match nothing if the row has
+	// changed since the caller read it.
+	ExpectedUpdatedAt pgtype.Timestamptz
+	Posted            bool
+	// WasPosted must match the row's current posted flag, so an update based on
+	// a read from before the poster ran matches nothing.
+	WasPosted bool
 }
 
 func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error) {
@@ -158,22 +503,37 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		     tags                   = $7,
 		     note                   = $8,
 		     transacted_at          = $9,
-		     type                   = $10
+		     type                   = $10,
+		     posted                 = $12
 		 WHERE id = $1 AND household_id = $2
-		 RETURNING id, household_id, type, description, amount,
-			account_id, destination_account_id, tags, note,
-			transacted_at, created_by, created_at, updated_at`,
+		   AND ($11::timestamptz IS NULL OR updated_at = $11)
+		   AND posted = $13
+		 RETURNING `+transactionColumns,
 		arg.ID, arg.HouseholdID, arg.Description, arg.Amount,
 		arg.AccountID, arg.DestinationAccountID, arg.Tags, arg.Note,
-		arg.TransactedAt, arg.Type,
+		arg.TransactedAt, arg.Type, arg.ExpectedUpdatedAt,
+		arg.Posted, arg.WasPosted,
 	)
-	var t Transaction
-	err := row.Scan(
-		&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
-		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
-		&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+	return scanTransaction(row)
+}
+
+type SetTransactionFlagParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	Flagged     bool
+	FlagReason  pgtype.Text
+}
+
+func (q *Queries) SetTransactionFlag(ctx context.Context, arg SetTransactionFlagParams) (Transaction, error) {
+	row := q.queryRow(ctx,
+		`UPDATE transactions
+		 SET flagged     = $3,
+		     flag_reason = $4
+		 WHERE id = $1 AND household_id = $2
+		 RETURNING `+transactionColumns,
+		arg.ID, arg.HouseholdID, arg.Flagged, arg.FlagReason,
 	)
-	return t, err
+	return scanTransaction(row)
 }
 
 type DeleteTransactionParams struct {
@@ -184,18 +544,90 @@ type DeleteTransactionParams struct {
 func (q *Queries) DeleteTransaction(ctx context.Context, arg DeleteTransactionParams) (Transaction, error) {
 	row := q.queryRow(ctx,
 		`DELETE FROM transactions WHERE id = $1 AND household_id = $2
-		 RETURNING id, household_id, type, description, amount,
-			account_id, destination_account_id, tags, note,
-			transacted_at, created_by, created_at, updated_at`,
+		 RETURNING `+transactionColumns,
 		arg.ID, arg.HouseholdID,
 	)
-	var t Transaction
-	err := row.Scan(
-		&t.ID, &t.HouseholdID, &t.Type, &t.Description, &t.Amount,
-		&t.AccountID, &t.DestinationAccountID, &t.Tags, &t.Note,
-		&t.TransactedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+	return scanTransaction(row)
+}
+
+type RestoreTransactionParams struct {
+	ID                   uuid.UUID
+	HouseholdID          uuid.UUID
+	Type                 TransactionType
+	Description          string
+	Amount               decimal.Decimal
+	AccountID            uuid.UUID
+	DestinationAccountID pgtype.UUID
+	Tags                 []string
+	Note                 pgtype.Text
+	TransactedAt         pgtype.Timestamptz
+	CreatedBy            uuid.UUID
+	CreatedAt            pgtype.Timestamptz
+	Flagged              bool
+	FlagReason           pgtype.Text
+	Posted               bool
+}
+
+// RestoreTransaction re-inserts a previously deleted transaction under its original ID.
+func (q *Queries) RestoreTransaction(ctx context.Context, arg RestoreTransactionParams) (Transaction, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO transactions (
+			id, household_id, type, description, amount,
+			account_id, destination_account_id, tags, note,
+			transacted_at, created_by, created_at,
+			flagged, flag_reason, posted
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING `+transactionColumns,
+		arg.ID, arg.HouseholdID, arg.Type, arg.Description, arg.Amount,
+		arg.AccountID, arg.DestinationAccountID, arg.Tags, arg.Note,
+		arg.TransactedAt, arg.CreatedBy, arg.CreatedAt,
+		arg.Flagged, arg.FlagReason, arg.Posted,
 	)
-	return t, err
+	return scanTransaction(row)
+}
+
+// ListDueTransactions returns the IDs of scheduled transactions dated at or
+// before now, oldest first.
+func (q *Queries) ListDueTransactions(ctx context.Context, now pgtype.Timestamptz, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.query(ctx,
+		`SELECT id FROM transactions
+		 WHERE NOT posted AND transacted_at <= $1
+		 ORDER BY transacted_at
+		 LIMIT $2`,
+		now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+type PostTransactionParams struct {
+	ID  uuid.UUID
+	Now pgtype.Timestamptz
+}
+
+// PostTransaction marks a due scheduled transaction posted. It matches nothing
+// if the transaction was posted, deleted or moved later in the meantime.
+func (q *Queries) PostTransaction(ctx context.Context, arg PostTransactionParams) (Transaction, error) {
+	row := q.queryRow(ctx,
+		`UPDATE transactions SET posted = true
+		 WHERE id = $1 AND NOT posted AND transacted_at <= $2
+		 RETURNING `+transactionColumns,
+		arg.ID, arg.Now,
+	)
+	return scanTransaction(row)
 }
 
 // --- Export query ---
@@ -207,28 +639,36 @@ type ListTransactionsForExportParams struct {
 }
 
 type ListTransactionsForExportRow struct {
+	ID                     uuid.UUID
 	TransactedAt           pgtype.Timestamptz
 	Description            string
 	Amount                 decimal.Decimal
 	Type                   TransactionType
 	Tags                   []string
 	Note                   pgtype.Text
+	AccountID              uuid.UUID
 	AccountName            string
 	AccountCurrency        string
+	DestinationAccountID   pgtype.UUID
 	DestinationAccountName *string
 }
 
-func (q *Queries) ListTransactionsForExport(ctx context.Context, arg ListTransactionsForExportParams) ([]ListTransactionsForExportRow, error) {
+// ListTransactionsForExport calls fn for each row as it is read, so the result set
+// is never held in memory. Iteration stops at the first error fn returns.
+func (q *Queries) ListTransactionsForExport(ctx context.Context, arg ListTransactionsForExportParams, fn func(ListTransactionsForExportRow) error) error {
 	rows, err := q.query(ctx,
 		`SELECT
+			t.id,
 			t.transacted_at,
 			t.description,
 			t.amount,
 			t.type,
 			t.tags,
 			t.note,
+			t.account_id,
 			a.name  AS account_name,
 			a.currency AS account_currency,
+			t.destination_account_id,
 			da.name AS destination_account_name
 		 FROM transactions t
 		 JOIN accounts a ON a.id = t.account_id
@@ -240,21 +680,22 @@ func (q *Queries) ListTransactionsForExport(ctx context.Context, arg ListTransac
 		arg.HouseholdID, arg.Column2, arg.Column3,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var out []ListTransactionsForExportRow
 	for rows.Next() {
 		var r ListTransactionsForExportRow
 		if err := rows.Scan(
-			&r.TransactedAt, &r.Description, &r.Amount, &r.Type,
-			&r.Tags, &r.Note, &r.AccountName, &r.AccountCurrency,
-			&r.DestinationAccountName,
+			&r.ID, &r.TransactedAt, &r.Description, &r.Amount, &r.Type,
+			&r.Tags, &r.Note, &r.AccountID, &r.AccountName, &r.AccountCurrency,
+			&r.DestinationAccountID, &r.DestinationAccountName,
 		); err != nil {
-			return nil, err
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
 		}
-		out = append(out, r)
 	}
-	return out, rows.Err()
+	return rows.Err()
 }
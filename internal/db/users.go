@@ -16,7 +16,7 @@ type CreateUserParams struct {
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	row := q.queryRow(ctx,
-		`INSERT INTO users (email, password_hash, name) VALUES ($1, $2, $3) RETURNING id, email, password_hash, name, created_at, updated_at`,
+		`INSERT INTO users (email, password_hash, name) VALUES (lower($1), $2, $3) RETURNING id, email, password_hash, name, created_at, updated_at`,
 		arg.Email, arg.PasswordHash, arg.Name,
 	)
 	var u User
@@ -26,7 +26,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
 	row := q.queryRow(ctx,
-		`SELECT id, email, password_hash, name, created_at, updated_at FROM users WHERE email = $1`,
+		`SELECT id, email, password_hash, name, created_at, updated_at FROM users WHERE lower(email) = lower($1)`,
 		email,
 	)
 	var u User
@@ -43,3 +43,35 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.CreatedAt, &u.UpdatedAt)
 	return u, err
 }
+
+type UpdateUserParams struct {
+	ID    uuid.UUID
+	Name  *string
+	Email *string
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.queryRow(ctx,
+		`UPDATE users
+		 SET name  = COALESCE($2, name),
+		     email = COALESCE(lower($3), email)
+		 WHERE id = $1
+		 RETURNING id, email, password_hash, name, created_at, updated_at`,
+		arg.ID, arg.Name, arg.Email,
+	)
+	var u User
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
+
+type UpdateUserPasswordParams struct {
+	ID           uuid.UUID
+	PasswordHash string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	return q.exec(ctx,
+		`UPDATE users SET password_hash = $2 WHERE id = $1`,
+		arg.ID, arg.PasswordHash,
+	)
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// --- Webhooks ---
+
+// webhookColumns lists the columns of the webhooks table in scanWebhook order.
+const webhookColumns = `id, household_id, url, secret, events, created_at`
+
+func scanWebhook(row pgx.Row) (Webhook, error) {
+	var wh Webhook
+	err := row.Scan(&wh.ID, &wh.HouseholdID, &wh.Url, &wh.Secret, &wh.Events, &wh.CreatedAt)
+	return wh, err
+}
+
+func scanWebhooks(rows pgx.Rows, err error) ([]Webhook, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Webhook
+	for rows.Next() {
+		wh, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, wh)
+	}
+	return out, rows.Err()
+}
+
+type CreateWebhookParams struct {
+	HouseholdID uuid.UUID
+	Url         string
+	Secret      string
+	Events      []string
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.queryRow(ctx,
+		`INSERT INTO webhooks (household_id, url, secret, events)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+webhookColumns,
+		arg.HouseholdID, arg.Url, arg.Secret, arg.Events,
+	)
+	return scanWebhook(row)
+}
+
+type GetWebhookParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	row := q.queryRow(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1 AND household_id = $2`,
+		arg.ID, arg.HouseholdID,
+	)
+	return scanWebhook(row)
+}
+
+func (q *Queries) ListWebhooks(ctx context.Context, householdID uuid.UUID) ([]Webhook, error) {
+	return scanWebhooks(q.query(ctx,
+		`SELECT `+webhookColumns+`
+		 FROM webhooks WHERE household_id = $1
+		 ORDER BY created_at`,
+		householdID,
+	))
+}
+
+type ListWebhooksForEventParams struct {
+	HouseholdID uuid.UUID
+	Event       string
+}
+
+func (q *Queries) ListWebhooksForEvent(ctx context.Context, arg ListWebhooksForEventParams) ([]Webhook, error) {
+	return scanWebhooks(q.query(ctx,
+		`SELECT `+webhookColumns+`
+		 FROM webhooks WHERE household_id = $1 AND $2 = ANY(events)`,
+		arg.HouseholdID, arg.Event,
+	))
+}
+
+type UpdateWebhookParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	Url         pgtype.Text
+	Events      []string
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.queryRow(ctx,
+		`UPDATE webhooks
+		 SET url    = COALESCE($3, url),
+		     events = COALESCE($4, events)
+		 WHERE id = $1 AND household_id = $2
+		 RETURNING `+webhookColumns,
+		arg.ID, arg.HouseholdID, arg.Url, arg.Events,
+	)
+	return scanWebhook(row)
+}
+
+type DeleteWebhookParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error) {
+	return q.execRows(ctx,
+		`DELETE FROM webhooks WHERE id = $1 AND household_id = $2`,
+		arg.ID, arg.HouseholdID,
+	)
+}
+
+// --- Deliveries ---
+
+type CreateWebhookDeliveryParams struct {
+	WebhookID  uuid.UUID
+	Event      string
+	Payload    []byte
+	Attempt    int32
+	StatusCode pgtype.Int4
+	Error      pgtype.Text
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error {
+	return q.exec(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event, payload, attempt, status_code, error)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		arg.WebhookID, arg.Event, arg.Payload, arg.Attempt, arg.StatusCode, arg.Error,
+	)
+}
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID uuid.UUID
+	Limit     int32
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.query(ctx,
+		`SELECT id, webhook_id, event, payload, attempt, status_code, error, created_at
+		 FROM webhook_deliveries WHERE webhook_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2`,
+		arg.WebhookID, arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Attempt, &d.StatusCode, &d.Error, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,6 +22,32 @@ func NewAccountHandler(accSvc *service.AccountService) *AccountHandler {
 	return &AccountHandler{accSvc: accSvc}
 }
 
+// POST /api/households/{id}/accounts/import
+func (h *AccountHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req model.ImportAccountsRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	accounts, err := h.accSvc.Import(r.Context(), hhID, userID, req.Accounts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAccountImport):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to import accounts")
+		}
+		return
+	}
+	JSON(w, http.StatusCreated, accounts)
+}
+
 // POST /api/accounts
 func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req model.CreateAccountRequest
@@ -35,12 +63,25 @@ func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserIDFromCtx(r.Context())
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
 
-	acc, err := h.accSvc.Create(r.Context(), hhID, userID, req)
+	// ?allow_duplicate=true creates the account even if one of the same name exists.
+	allowDuplicate, _ := strconv.ParseBool(r.URL.Query().Get("allow_duplicate"))
+
+	acc, err := h.accSvc.Create(r.Context(), hhID, userID, req, allowDuplicate)
 	if err != nil {
-		ErrorJSON(w, http.StatusInternalServerError, "failed to create account")
+		switch {
+		case errors.Is(err, service.ErrAccountNameExists):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidAccountType), errors.Is(err, service.ErrInvalidDailyLimit),
+			errors.Is(err, service.ErrInvalidBalance):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to create account")
+		}
 		return
 	}
-	JSON(w, http.StatusCreated, acc)
+	Created(w, "/api/accounts/"+acc.ID.String(), acc)
 }
 
 // GET /api/accounts
@@ -55,6 +96,51 @@ func (h *AccountHandler) List(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, accounts)
 }
 
+// GET /api/accounts/summary
+func (h *AccountHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	summary, err := h.accSvc.Summary(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to summarize accounts")
+		return
+	}
+	JSON(w, http.StatusOK, summary)
+}
+
+// GET /api/accounts/net-worth
+func (h *AccountHandler) NetWorth(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	totals, err := h.accSvc.NetWorth(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to compute net worth")
+		return
+	}
+	JSON(w, http.StatusOK, totals)
+}
+
+// PUT /api/accounts/reorder
+func (h *AccountHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	var req model.ReorderAccountsRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	accounts, err := h.accSvc.Reorder(r.Context(), hhID, req.AccountIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAccountOrder) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to reorder accounts")
+		return
+	}
+	JSON(w, http.StatusOK, accounts)
+}
+
 // GET /api/accounts/{id}
 func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
 	accID, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -66,10 +152,41 @@ func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
 	acc, err := h.accSvc.Get(r.Context(), accID, hhID)
 	if err != nil {
-		ErrorJSON(w, http.StatusNotFound, "account not found")
+		if errors.Is(err, service.ErrAccountNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to get account")
 		return
 	}
-	JSON(w, http.StatusOK, acc)
+	JSONWithETag(w, r, acc)
+}
+
+// GET /api/accounts/{id}/balance
+func (h *AccountHandler) BalanceAsOf(w http.ResponseWriter, r *http.Request) {
+	accID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid account id")
+		return
+	}
+	asOf, err := time.Parse(time.DateOnly, r.URL.Query().Get("as_of"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "as_of is a required date (YYYY-MM-DD)")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	balance, err := h.accSvc.BalanceAsOf(r.Context(), accID, hhID, asOf)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound), errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to compute balance")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, balance)
 }
 
 // PUT /api/accounts/{id}
@@ -86,15 +203,61 @@ func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID := middleware.UserIDFromCtx(r.Context())
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
-	acc, err := h.accSvc.Update(r.Context(), accID, hhID, req)
+	acc, err := h.accSvc.Update(r.Context(), accID, hhID, userID, req)
 	if err != nil {
-		ErrorJSON(w, http.StatusNotFound, "account not found")
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrConflict):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidAccountType), errors.Is(err, service.ErrInvalidDailyLimit):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to update account")
+		}
 		return
 	}
 	JSON(w, http.StatusOK, acc)
 }
 
+// POST /api/accounts/{id}/reassign-transactions
+func (h *AccountHandler) ReassignTransactions(w http.ResponseWriter, r *http.Request) {
+	accID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	var req model.ReassignTransactionsRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.TargetAccountID == uuid.Nil {
+		ErrorJSON(w, http.StatusBadRequest, "target_account_id is required")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	moved, err := h.accSvc.ReassignTransactions(r.Context(), hhID, accID, req.TargetAccountID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidReassign):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrReassignTransfers):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to reassign transactions")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, map[string]int64{"reassigned": moved})
+}
+
 // DELETE /api/accounts/{id}
 func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	accID, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -115,3 +278,60 @@ func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	JSON(w, http.StatusOK, map[string]string{"message": "account deleted"})
 }
+
+// GET /api/account-types
+func (h *AccountHandler) ListTypes(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	types, err := h.accSvc.ListTypes(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list account types")
+		return
+	}
+	JSON(w, http.StatusOK, types)
+}
+
+// POST /api/account-types
+func (h *AccountHandler) CreateType(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateAccountTypeRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	t, err := h.accSvc.CreateType(r.Context(), hhID, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTypeName):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAccountTypeExists):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to create account type")
+		}
+		return
+	}
+	JSON(w, http.StatusCreated, t)
+}
+
+// DELETE /api/account-types/{name}
+func (h *AccountHandler) DeleteType(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	err := h.accSvc.DeleteType(r.Context(), hhID, chi.URLParam(r, "name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAccountType):
+			ErrorJSON(w, http.StatusBadRequest, "built-in account types cannot be deleted")
+		case errors.Is(err, service.ErrAccountTypeNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrAccountTypeInUse):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to delete account type")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "account type deleted"})
+}
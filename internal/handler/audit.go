@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type AuditHandler struct {
+	auditSvc *service.AuditService
+}
+
+func NewAuditHandler(auditSvc *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditSvc: auditSvc}
+}
+
+// POST /api/households/{id}/undo
+func (h *AuditHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	entry, err := h.auditSvc.Undo(r.Context(), hhID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNothingToUndo):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrUndoConflict), errors.Is(err, service.ErrUndoReferenceGone):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to undo")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, entry)
+}
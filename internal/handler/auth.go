@@ -2,8 +2,13 @@ package handler
 
 import (
 	"errors"
+	"io"
+	"net"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
 	"github.com/howallet/howallet/internal/middleware"
 	"github.com/howallet/howallet/internal/model"
 	"github.com/howallet/howallet/internal/service"
@@ -30,18 +35,18 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Password) < 8 {
-		ErrorJSON(w, http.StatusBadRequest, "password must be at least 8 characters")
-		return
-	}
-
-	resp, err := h.authSvc.Register(r.Context(), req)
+	resp, err := h.authSvc.Register(r.Context(), req, clientInfo(r))
 	if err != nil {
-		if errors.Is(err, service.ErrEmailTaken) {
+		switch {
+		case errors.Is(err, service.ErrInvalidEmail):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrWeakPassword):
+			writePasswordPolicyError(w, err)
+		case errors.Is(err, service.ErrEmailTaken):
 			ErrorJSON(w, http.StatusConflict, err.Error())
-			return
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "registration failed")
 		}
-		ErrorJSON(w, http.StatusInternalServerError, "registration failed")
 		return
 	}
 
@@ -61,7 +66,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.authSvc.Login(r.Context(), req)
+	resp, err := h.authSvc.Login(r.Context(), req, clientInfo(r))
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			ErrorJSON(w, http.StatusUnauthorized, err.Error())
@@ -87,7 +92,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.authSvc.Refresh(r.Context(), req.RefreshToken)
+	resp, err := h.authSvc.Refresh(r.Context(), req.RefreshToken, clientInfo(r))
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidToken) {
 			ErrorJSON(w, http.StatusUnauthorized, err.Error())
@@ -101,11 +106,167 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 }
 
 // POST /auth/logout
+// With {"refresh_token": "..."} only that session is ended; without a body every
+// session is, as with /auth/logout-all.
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if err := Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.RefreshToken == "" {
+		h.LogoutAll(w, r)
+		return
+	}
+
 	userID := middleware.UserIDFromCtx(r.Context())
-	if err := h.authSvc.Logout(r.Context(), userID); err != nil {
+	if err := h.authSvc.LogoutSession(r.Context(), userID, req.RefreshToken); err != nil {
 		ErrorJSON(w, http.StatusInternalServerError, "logout failed")
 		return
 	}
 	JSON(w, http.StatusOK, map[string]string{"message": "logged out"})
 }
+
+// POST /auth/logout-all
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserIDFromCtx(r.Context())
+	if err := h.authSvc.Logout(r.Context(), userID); err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "logout failed")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "logged out of all sessions"})
+}
+
+// GET /auth/me
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserIDFromCtx(r.Context())
+	user, err := h.authSvc.Me(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+	JSON(w, http.StatusOK, user)
+}
+
+// PATCH /auth/me
+func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateProfileRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	user, err := h.authSvc.UpdateProfile(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidEmail), errors.Is(err, service.ErrInvalidProfile):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrEmailChangeUnsupported):
+			ErrorJSON(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to update profile")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, user)
+}
+
+// POST /auth/password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ChangePasswordRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		ErrorJSON(w, http.StatusBadRequest, "current_password and new_password are required")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	if err := h.authSvc.ChangePassword(r.Context(), userID, req); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWeakPassword):
+			writePasswordPolicyError(w, err)
+		case errors.Is(err, service.ErrPasswordUnchanged):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInvalidCredentials):
+			ErrorJSON(w, http.StatusForbidden, "current password is incorrect")
+		case errors.Is(err, service.ErrUserNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to change password")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "password changed"})
+}
+
+// GET /auth/sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserIDFromCtx(r.Context())
+	sessions, err := h.authSvc.ListSessions(r.Context(), userID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+	JSON(w, http.StatusOK, sessions)
+}
+
+// DELETE /auth/sessions/{id}
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	if err := h.authSvc.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to revoke session")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "session revoked"})
+}
+
+// maxUserAgentLen caps what we store from the User-Agent header.
+const maxUserAgentLen = 512
+
+// clientInfo captures the caller's device details for session listing.
+// RemoteAddr has already been rewritten by chi's RealIP middleware.
+func clientInfo(r *http.Request) model.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLen {
+		ua = ua[:maxUserAgentLen]
+	}
+	return model.ClientInfo{UserAgent: ua, IPAddress: ip}
+}
+
+// writePasswordPolicyError reports a rejected password with the rules it failed
+// under "failed_rules", so clients can show them next to the field.
+func writePasswordPolicyError(w http.ResponseWriter, err error) {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ErrorJSONDetails(w, http.StatusBadRequest, service.ErrWeakPassword.Error(), map[string]any{
+		"failed_rules": policyErr.Failed,
+	})
+}
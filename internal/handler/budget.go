@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type BudgetHandler struct {
+	budgetSvc *service.BudgetService
+}
+
+func NewBudgetHandler(budgetSvc *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{budgetSvc: budgetSvc}
+}
+
+// POST /api/budgets
+func (h *BudgetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.BudgetRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	b, err := h.budgetSvc.Create(r.Context(), hhID, req)
+	if err != nil {
+		writeBudgetError(w, err, "failed to create budget")
+		return
+	}
+	JSON(w, http.StatusCreated, b)
+}
+
+// GET /api/budgets
+func (h *BudgetHandler) List(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	budgets, err := h.budgetSvc.List(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list budgets")
+		return
+	}
+	JSON(w, http.StatusOK, budgets)
+}
+
+// PUT /api/budgets/{id}
+func (h *BudgetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid budget id")
+		return
+	}
+	var req model.BudgetRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	b, err := h.budgetSvc.Update(r.Context(), id, hhID, req)
+	if err != nil {
+		writeBudgetError(w, err, "failed to update budget")
+		return
+	}
+	JSON(w, http.StatusOK, b)
+}
+
+// DELETE /api/budgets/{id}
+func (h *BudgetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid budget id")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	if err := h.budgetSvc.Delete(r.Context(), id, hhID); err != nil {
+		writeBudgetError(w, err, "failed to delete budget")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "budget deleted"})
+}
+
+func writeBudgetError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrBudgetNotFound):
+		ErrorJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidBudget), errors.Is(err, service.ErrCategoryNotFound):
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrBudgetExists):
+		ErrorJSON(w, http.StatusConflict, err.Error())
+	default:
+		ErrorJSON(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type CategoryHandler struct {
+	catSvc *service.CategoryService
+}
+
+func NewCategoryHandler(catSvc *service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{catSvc: catSvc}
+}
+
+// POST /api/categories
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateCategoryRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		ErrorJSON(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	cat, err := h.catSvc.Create(r.Context(), hhID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCategoryExists):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to create category")
+		}
+		return
+	}
+	JSON(w, http.StatusCreated, cat)
+}
+
+// GET /api/categories
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	categories, err := h.catSvc.List(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list categories")
+		return
+	}
+	JSON(w, http.StatusOK, categories)
+}
@@ -1,24 +1,116 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	chimw "github.com/go-chi/chi/v5/middleware"
+
 	"github.com/howallet/howallet/internal/middleware"
 	"github.com/howallet/howallet/internal/service"
 )
 
+// exportWriteTimeout replaces the server's write timeout for exports, which
+// stream for as long as the household has rows.
+const exportWriteTimeout = 5 * time.Minute
+
 type ExportHandler struct {
 	exportSvc *service.ExportService
+	logger    *slog.Logger
+}
+
+func NewExportHandler(exportSvc *service.ExportService, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{exportSvc: exportSvc, logger: logger}
 }
 
-func NewExportHandler(exportSvc *service.ExportService) *ExportHandler {
-	return &ExportHandler{exportSvc: exportSvc}
+// exportWriter sets the download headers on the first write, so an export that
+// fails before producing any output can still be answered with a JSON error.
+type exportWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// exportMediaTypes maps the media types /api/export can serve to formats.
+var exportMediaTypes = map[string]service.ExportFormat{
+	"text/csv":          service.ExportFormatCSV,
+	"application/json":  service.ExportFormatJSON,
+	"application/x-ofx": service.ExportFormatOFX,
+	"text/*":            service.ExportFormatCSV,
+	"application/*":     service.ExportFormatJSON,
+	"*/*":               service.ExportFormatCSV,
+}
+
+// negotiateExportFormat picks the export format: ?format= wins, then the
+// supported type the Accept header prefers most (the first one on a q-value
+// tie). A missing Accept header means CSV; ok is false when nothing the client
+// accepts can be served.
+func negotiateExportFormat(r *http.Request) (format service.ExportFormat, ok bool) {
+	if v := r.URL.Query().Get("format"); v != "" {
+		switch f := service.ExportFormat(strings.ToLower(v)); f {
+		case service.ExportFormatCSV, service.ExportFormatJSON, service.ExportFormatOFX:
+			return f, true
+		}
+		return "", false
+	}
+
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		return service.ExportFormatCSV, true
+	}
+	bestQ := 0.0
+	for _, part := range strings.Split(strings.Join(accept, ","), ",") {
+		params := strings.Split(part, ";")
+		f, known := exportMediaTypes[strings.ToLower(strings.TrimSpace(params[0]))]
+		if !known {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			if k, v, _ := strings.Cut(strings.TrimSpace(p), "="); strings.EqualFold(k, "q") {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ {
+			format, bestQ, ok = f, q, true
+		}
+	}
+	return format, ok
+}
+
+// GET /api/export
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateExportFormat(r)
+	if !ok {
+		ErrorJSON(w, http.StatusNotAcceptable, "export is available as text/csv, application/json or application/x-ofx")
+		return
+	}
+	h.export(w, r, format)
 }
 
 // GET /api/export/csv
 func (h *ExportHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, service.ExportFormatCSV)
+}
+
+func (h *ExportHandler) export(w http.ResponseWriter, r *http.Request, format service.ExportFormat) {
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
 
 	var from, to *time.Time
@@ -33,12 +125,124 @@ func (h *ExportHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	filename := fmt.Sprintf("hoWallet_export_%s.csv", time.Now().Format("2006-01-02"))
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	dateLayout, msg := exportDateLayout(r)
+	if msg != "" {
+		ErrorJSON(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if format != service.ExportFormatCSV {
+		for _, p := range csvOnlyExportParams {
+			if r.URL.Query().Has(p) {
+				ErrorJSON(w, http.StatusBadRequest, p+" applies to CSV exports only")
+				return
+			}
+		}
+	}
+
+	ew := &exportWriter{
+		ResponseWriter: w,
+		filename:       fmt.Sprintf("hoWallet_export_%s.%s", time.Now().Format("2006-01-02"), format),
+	}
+	var run func() error
+	switch format {
+	case service.ExportFormatJSON:
+		ew.contentType = "application/json"
+		run = func() error { return h.exportSvc.ExportJSON(r.Context(), ew, hhID, from, to, dateLayout) }
+	case service.ExportFormatOFX:
+		// OFX fixes its own date format; a different one would break importers.
+		if dateLayout != "" {
+			ErrorJSON(w, http.StatusBadRequest, "date_format does not apply to OFX exports")
+			return
+		}
+		ew.contentType = "application/x-ofx"
+		run = func() error { return h.exportSvc.ExportOFX(r.Context(), ew, hhID, from, to) }
+	default:
+		opts, msg := csvExportOptions(r)
+		if msg != "" {
+			ErrorJSON(w, http.StatusBadRequest, msg)
+			return
+		}
+		opts.DateLayout = dateLayout
+		ew.contentType = "text/csv; charset=" + opts.Encoding
+		run = func() error { return h.exportSvc.ExportCSV(r.Context(), ew, hhID, from, to, opts) }
+	}
+
+	// Best-effort: not every ResponseWriter supports deadlines.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+
+	err := run()
+	if err == nil {
+		return
+	}
+	if !ew.started {
+		if errors.Is(err, service.ErrHouseholdNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "export failed")
+		return
+	}
+
+	// The status and part of the body are already out. Abort the connection so
+	// the client sees a failed download instead of a truncated file.
+	h.logger.Error("export aborted mid-stream",
+		slog.String("household_id", hhID.String()),
+		slog.String("request_id", chimw.GetReqID(r.Context())),
+		slog.String("error", err.Error()),
+	)
+	panic(http.ErrAbortHandler)
+}
+
+// exportDateLayout reads ?date_format=iso|us|eu; raw Go layouts are not
+// accepted. An empty layout means the format's default. A non-empty message
+// means the request is invalid.
+func exportDateLayout(r *http.Request) (string, string) {
+	v := r.URL.Query().Get("date_format")
+	if v == "" {
+		return "", ""
+	}
+	layout, ok := service.ExportDateFormats[v]
+	if !ok {
+		return "", "date_format must be iso, us or eu"
+	}
+	return layout, ""
+}
+
+// csvOnlyExportParams are the query options other formats reject rather than
+// silently ignore.
+var csvOnlyExportParams = []string{"bom", "encoding", "decimal"}
+
+// csvExportOptions reads the CSV-only query options. A non-empty message means
+// the request is invalid.
+func csvExportOptions(r *http.Request) (service.ExportOptions, string) {
+	// ?bom=true prefixes a UTF-8 byte-order mark so Excel reads non-ASCII text correctly.
+	opts := service.ExportOptions{Encoding: service.ExportEncodingUTF8}
+	if v := r.URL.Query().Get("bom"); v != "" {
+		bom, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, "bom must be true or false"
+		}
+		opts.BOM = bom
+	}
+
+	// ?encoding=windows-1251 serves legacy Excel, which reads CSVs in the system
+	// code page.
+	switch v := strings.ToLower(r.URL.Query().Get("encoding")); v {
+	case "", service.ExportEncodingUTF8:
+	case service.ExportEncodingWindows1251:
+		opts.Encoding = v
+	default:
+		return opts, "encoding must be utf-8 or windows-1251"
+	}
 
-	if err := h.exportSvc.ExportCSV(r.Context(), w, hhID, from, to); err != nil {
-		// Headers already sent, just log
-		http.Error(w, "export failed", http.StatusInternalServerError)
+	// ?decimal=comma suits spreadsheets set up for comma decimals (e.g. UA/EU).
+	switch r.URL.Query().Get("decimal") {
+	case "", "dot":
+	case "comma":
+		opts.DecimalComma = true
+	default:
+		return opts, "decimal must be dot or comma"
 	}
+	return opts, ""
 }
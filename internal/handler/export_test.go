@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+	"github.com/howallet/howallet/internal/service"
+)
+
+// exportTransactions streams rows, then fails with err (if set) once failAfter
+// rows have gone out.
+type exportTransactions struct {
+	repository.TransactionRepository
+	rows      []repository.ExportRow
+	failAfter int
+	err       error
+}
+
+func (f *exportTransactions) StreamForExport(_ context.Context, _ uuid.UUID, _, _ *time.Time, fn func(repository.ExportRow) error) error {
+	for i, r := range f.rows {
+		if f.err != nil && i == f.failAfter {
+			return f.err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+type exportHouseholds struct {
+	repository.HouseholdRepository
+	missing bool
+}
+
+func (f *exportHouseholds) GetByID(_ context.Context, id uuid.UUID) (model.Household, error) {
+	if f.missing {
+		return model.Household{}, pgx.ErrNoRows
+	}
+	return model.Household{ID: id, Timezone: "UTC"}, nil
+}
+
+type exportAccounts struct {
+	repository.AccountRepository
+}
+
+func (exportAccounts) ListByHousehold(context.Context, uuid.UUID) ([]model.Account, error) {
+	return nil, nil
+}
+
+func newTestExportHandler(txns *exportTransactions, households *exportHouseholds) *ExportHandler {
+	svc := service.NewExportService(txns, households, exportAccounts{})
+	return NewExportHandler(svc, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func exportRows(n int) []repository.ExportRow {
+	rows := make([]repository.ExportRow, n)
+	for i := range rows {
+		rows[i] = repository.ExportRow{
+			ID: uuid.New(), TransactedAt: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+			Description: "Coffee", Amount: decimal.NewFromInt(3), Type: model.TransactionTypeExpense,
+			AccountID: uuid.New(), AccountName: "Card", AccountCurrency: "USD",
+		}
+	}
+	return rows
+}
+
+func TestExportEarlyFailureIsJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     service.ExportFormat
+		households *exportHouseholds
+		wantStatus int
+	}{
+		{"csv query error", service.ExportFormatCSV, &exportHouseholds{}, http.StatusInternalServerError},
+		{"json query error", service.ExportFormatJSON, &exportHouseholds{}, http.StatusInternalServerError},
+		{"ofx query error", service.ExportFormatOFX, &exportHouseholds{}, http.StatusInternalServerError},
+		{"household gone", service.ExportFormatCSV, &exportHouseholds{missing: true}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txns := &exportTransactions{rows: exportRows(3), err: errors.New("connection reset")}
+			rec := httptest.NewRecorder()
+			newTestExportHandler(txns, tt.households).export(rec, httptest.NewRequest(http.MethodGet, "/api/export", nil), tt.format)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			if cd := rec.Header().Get("Content-Disposition"); cd != "" {
+				t.Errorf("Content-Disposition = %q, want none", cd)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("body = %q, want a JSON error", rec.Body)
+			}
+		})
+	}
+}
+
+func TestExportMidStreamFailureAborts(t *testing.T) {
+	// Enough rows to get past the CSV writer's buffer before the failure.
+	txns := &exportTransactions{rows: exportRows(500), failAfter: 400, err: errors.New("connection reset")}
+	rec := httptest.NewRecorder()
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", p)
+		}
+		if cd := rec.Header().Get("Content-Disposition"); cd == "" {
+			t.Error("download headers missing although output was written")
+		}
+	}()
+	newTestExportHandler(txns, &exportHouseholds{}).export(rec, httptest.NewRequest(http.MethodGet, "/api/export", nil), service.ExportFormatCSV)
+}
+
+// Invalid or inapplicable options are rejected before the export touches the
+// service, so a nil one is enough here.
+func TestExportRejectsBadOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		format service.ExportFormat
+		query  string
+	}{
+		{"csv bad date_format", service.ExportFormatCSV, "date_format=2006-01-02"},
+		{"csv bad bom", service.ExportFormatCSV, "bom=maybe"},
+		{"csv bad encoding", service.ExportFormatCSV, "encoding=latin1"},
+		{"csv bad decimal", service.ExportFormatCSV, "decimal=point"},
+		{"json bad date_format", service.ExportFormatJSON, "date_format=nope"},
+		{"json bom", service.ExportFormatJSON, "bom=true"},
+		{"json encoding", service.ExportFormatJSON, "encoding=windows-1251"},
+		{"json decimal", service.ExportFormatJSON, "decimal=comma"},
+		{"ofx date_format", service.ExportFormatOFX, "date_format=us"},
+		{"ofx bom", service.ExportFormatOFX, "bom=false"},
+	}
+	h := NewExportHandler(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.export(rec, httptest.NewRequest(http.MethodGet, "/api/export?"+tt.query, nil), tt.format)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400 (body %s)", rec.Code, rec.Body)
+			}
+		})
+	}
+}
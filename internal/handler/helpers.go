@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
 )
 
 // JSON writes a JSON response.
@@ -14,9 +21,65 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
-// ErrorJSON writes a JSON error response.
+// Created writes a 201 JSON response with a Location header pointing at the
+// new resource's canonical URL.
+func Created(w http.ResponseWriter, location string, data interface{}) {
+	w.Header().Set("Location", location)
+	JSON(w, http.StatusCreated, data)
+}
+
+// JSONWithETag writes a 200 JSON response with an ETag derived from the body, so
+// it changes whenever anything in the representation does (e.g. an account's
+// balance, which doesn't move updated_at). A request whose If-None-Match holds
+// that ETag gets 304 Not Modified without a body.
+func JSONWithETag(w http.ResponseWriter, r *http.Request, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires for GET.
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorJSON writes a JSON error response. The request ID, when the router set one,
+// is included so clients can quote it in bug reports.
 func ErrorJSON(w http.ResponseWriter, status int, msg string) {
-	JSON(w, status, map[string]string{"error": msg})
+	ErrorJSONDetails(w, status, msg, nil)
+}
+
+// ErrorJSONDetails is ErrorJSON with extra fields next to "error".
+func ErrorJSONDetails(w http.ResponseWriter, status int, msg string, details map[string]any) {
+	body := map[string]any{"error": msg}
+	for k, v := range details {
+		body[k] = v
+	}
+	if id := w.Header().Get(middleware.RequestIDHeader); id != "" {
+		body["request_id"] = id
+	}
+	JSON(w, status, body)
 }
 
 // Decode reads JSON from the request body into the target.
@@ -24,3 +87,13 @@ func Decode(r *http.Request, target interface{}) error {
 	defer r.Body.Close()
 	return json.NewDecoder(r.Body).Decode(target)
 }
+
+// requireOwner writes 403 and returns false unless the caller owns the household
+// resolved by HouseholdCtx / HouseholdParamCtx.
+func requireOwner(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.RoleFromCtx(r.Context()) != model.HouseholdRoleOwner {
+		ErrorJSON(w, http.StatusForbidden, service.ErrNotHouseholdOwner.Error())
+		return false
+	}
+	return true
+}
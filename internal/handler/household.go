@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -35,12 +37,106 @@ func (h *HouseholdHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserIDFromCtx(r.Context())
 	hh, err := h.hhSvc.Create(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimezone) || errors.Is(err, service.ErrInvalidCurrency) ||
+			errors.Is(err, service.ErrInvalidAccountImport) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		ErrorJSON(w, http.StatusInternalServerError, "failed to create household")
 		return
 	}
 	JSON(w, http.StatusCreated, hh)
 }
 
+// PATCH /api/households/{id}
+func (h *HouseholdHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	var req model.UpdateHouseholdRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	hh, err := h.hhSvc.Update(r.Context(), hhID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTimezone), errors.Is(err, service.ErrInvalidHousehold):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to update household")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, hh)
+}
+
+// GET /api/households/{id}
+func (h *HouseholdHandler) Get(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	userID := middleware.UserIDFromCtx(r.Context())
+	hh, err := h.hhSvc.Get(r.Context(), hhID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrNotMember):
+			ErrorJSON(w, http.StatusForbidden, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to get household")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, hh)
+}
+
+// GET /api/households/{id}/settings
+func (h *HouseholdHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	settings, err := h.hhSvc.GetSettings(r.Context(), hhID)
+	if err != nil {
+		if errors.Is(err, service.ErrHouseholdNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to get household settings")
+		return
+	}
+	JSON(w, http.StatusOK, settings)
+}
+
+// PATCH /api/households/{id}/settings
+func (h *HouseholdHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	var req model.UpdateHouseholdSettingsRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	settings, err := h.hhSvc.UpdateSettings(r.Context(), hhID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTimezone), errors.Is(err, service.ErrInvalidCurrency),
+			errors.Is(err, service.ErrInvalidSettings):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrHouseholdNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to update household settings")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, settings)
+}
+
 // GET /api/households
 func (h *HouseholdHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.UserIDFromCtx(r.Context())
@@ -54,31 +150,40 @@ func (h *HouseholdHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // GET /api/households/{id}/members
 func (h *HouseholdHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
-	hhID, err := uuid.Parse(chi.URLParam(r, "id"))
-	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, "invalid household id")
-		return
+	var q model.ListMembersQuery
+	if v := r.URL.Query().Get("role"); v != "" {
+		role := model.HouseholdRole(v)
+		if role != model.HouseholdRoleOwner && role != model.HouseholdRoleMember {
+			ErrorJSON(w, http.StatusBadRequest, "invalid role")
+			return
+		}
+		q.Role = &role
 	}
+	q.Search = strings.TrimSpace(r.URL.Query().Get("search"))
 
-	userID := middleware.UserIDFromCtx(r.Context())
-	if err := h.hhSvc.CheckMembership(r.Context(), hhID, userID); err != nil {
-		ErrorJSON(w, http.StatusForbidden, "not a member")
-		return
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			q.Limit = int32(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			q.Offset = int32(n)
+		}
 	}
 
-	members, err := h.hhSvc.ListMembers(r.Context(), hhID)
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	result, err := h.hhSvc.ListMembers(r.Context(), hhID, q)
 	if err != nil {
 		ErrorJSON(w, http.StatusInternalServerError, "failed to list members")
 		return
 	}
-	JSON(w, http.StatusOK, members)
+	JSON(w, http.StatusOK, result)
 }
 
 // POST /api/households/{id}/invite
 func (h *HouseholdHandler) Invite(w http.ResponseWriter, r *http.Request) {
-	hhID, err := uuid.Parse(chi.URLParam(r, "id"))
-	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, "invalid household id")
+	if !requireOwner(w, r) {
 		return
 	}
 
@@ -93,11 +198,12 @@ func (h *HouseholdHandler) Invite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
 	inv, err := h.hhSvc.Invite(r.Context(), hhID, userID, req.Email)
 	if err != nil {
 		switch {
-		case errors.Is(err, service.ErrNotHouseholdOwner):
-			ErrorJSON(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrInvalidEmail):
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrAlreadyMember):
 			ErrorJSON(w, http.StatusConflict, err.Error())
 		default:
@@ -118,11 +224,16 @@ func (h *HouseholdHandler) AcceptInvitation(w http.ResponseWriter, r *http.Reque
 
 	userID := middleware.UserIDFromCtx(r.Context())
 	if err := h.hhSvc.AcceptInvitation(r.Context(), token, userID); err != nil {
-		if errors.Is(err, service.ErrInvitationInvalid) {
+		switch {
+		case errors.Is(err, service.ErrInvitationInvalid):
 			ErrorJSON(w, http.StatusBadRequest, err.Error())
-			return
+		case errors.Is(err, service.ErrAlreadyMember):
+			ErrorJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvitationEmail):
+			ErrorJSON(w, http.StatusForbidden, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to accept invitation")
 		}
-		ErrorJSON(w, http.StatusInternalServerError, "failed to accept invitation")
 		return
 	}
 	JSON(w, http.StatusOK, map[string]string{"message": "invitation accepted"})
@@ -130,25 +241,18 @@ func (h *HouseholdHandler) AcceptInvitation(w http.ResponseWriter, r *http.Reque
 
 // DELETE /api/households/{id}/members/{userId}
 func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
-	hhID, err := uuid.Parse(chi.URLParam(r, "id"))
-	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, "invalid household id")
-		return
-	}
 	targetUID, err := uuid.Parse(chi.URLParam(r, "userId"))
 	if err != nil {
 		ErrorJSON(w, http.StatusBadRequest, "invalid user id")
 		return
 	}
+	if !requireOwner(w, r) {
+		return
+	}
 
-	ownerID := middleware.UserIDFromCtx(r.Context())
-	if err := h.hhSvc.RemoveMember(r.Context(), hhID, ownerID, targetUID); err != nil {
-		switch {
-		case errors.Is(err, service.ErrNotHouseholdOwner):
-			ErrorJSON(w, http.StatusForbidden, err.Error())
-		default:
-			ErrorJSON(w, http.StatusInternalServerError, "failed to remove member")
-		}
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	if err := h.hhSvc.RemoveMember(r.Context(), hhID, targetUID); err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to remove member")
 		return
 	}
 	JSON(w, http.StatusOK, map[string]string{"message": "member removed"})
@@ -156,22 +260,51 @@ func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request)
 
 // GET /api/households/{id}/invitations
 func (h *HouseholdHandler) ListPendingInvitations(w http.ResponseWriter, r *http.Request) {
-	hhID, err := uuid.Parse(chi.URLParam(r, "id"))
-	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, "invalid household id")
+	if !requireOwner(w, r) {
 		return
 	}
 
-	userID := middleware.UserIDFromCtx(r.Context())
-	if err := h.hhSvc.CheckMembership(r.Context(), hhID, userID); err != nil {
-		ErrorJSON(w, http.StatusForbidden, "not a member")
-		return
+	var limit, offset int32
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = int32(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = int32(n)
+		}
 	}
 
-	invitations, err := h.hhSvc.ListPendingInvitations(r.Context(), hhID)
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	result, err := h.hhSvc.ListPendingInvitations(r.Context(), hhID, limit, offset)
 	if err != nil {
 		ErrorJSON(w, http.StatusInternalServerError, "failed to list invitations")
 		return
 	}
-	JSON(w, http.StatusOK, invitations)
+	JSON(w, http.StatusOK, result)
+}
+
+// DELETE /api/households/{id}/invitations/{invitationId}
+func (h *HouseholdHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	invID, err := uuid.Parse(chi.URLParam(r, "invitationId"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+	if !requireOwner(w, r) {
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	if err := h.hhSvc.RevokeInvitation(r.Context(), hhID, invID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvitationNotFound):
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+		default:
+			ErrorJSON(w, http.StatusInternalServerError, "failed to revoke invitation")
+		}
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "invitation revoked"})
 }
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+type MetaHandler struct {
+	meta model.Meta
+}
+
+func NewMetaHandler(meta model.Meta) *MetaHandler {
+	return &MetaHandler{meta: meta}
+}
+
+// GET /api/meta
+func (h *MetaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	JSON(w, http.StatusOK, h.meta)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+// NotificationHandler serves the caller's in-app notifications across all of
+// their households.
+type NotificationHandler struct {
+	notificationSvc *service.NotificationService
+}
+
+func NewNotificationHandler(notificationSvc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationSvc: notificationSvc}
+}
+
+// GET /api/notifications
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	var q model.ListNotificationsQuery
+	if v := r.URL.Query().Get("unread"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			q.UnreadOnly = b
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			q.Limit = int32(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			q.Offset = int32(n)
+		}
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	result, err := h.notificationSvc.List(r.Context(), userID, q)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list notifications")
+		return
+	}
+	JSON(w, http.StatusOK, result)
+}
+
+// POST /api/notifications/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	n, err := h.notificationSvc.MarkRead(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			ErrorJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to mark notification read")
+		return
+	}
+	JSON(w, http.StatusOK, n)
+}
+
+// GET /api/notifications/preferences
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.UserIDFromCtx(r.Context())
+	prefs, err := h.notificationSvc.Preferences(r.Context(), userID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to get notification preferences")
+		return
+	}
+	JSON(w, http.StatusOK, prefs)
+}
+
+// PUT /api/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateNotificationPreferencesRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	prefs, err := h.notificationSvc.UpdatePreferences(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidThreshold) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to update notification preferences")
+		return
+	}
+	JSON(w, http.StatusOK, prefs)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type OnboardingHandler struct {
+	onboardingSvc *service.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingSvc *service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{onboardingSvc: onboardingSvc}
+}
+
+// POST /api/onboarding
+func (h *OnboardingHandler) Onboard(w http.ResponseWriter, r *http.Request) {
+	var req model.OnboardingRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	resp, err := h.onboardingSvc.Onboard(r.Context(), hhID, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOnboarding) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "onboarding failed")
+		return
+	}
+	JSON(w, http.StatusCreated, resp)
+}
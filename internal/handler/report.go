@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type ReportHandler struct {
+	reportSvc *service.ReportService
+}
+
+func NewReportHandler(reportSvc *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportSvc: reportSvc}
+}
+
+// GET /api/reports/by-member
+func (h *ReportHandler) ByMember(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	from, to, msg := parseReportRange(r)
+	if msg != "" {
+		ErrorJSON(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	report, err := h.reportSvc.ByMember(r.Context(), hhID, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportRange) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to build report")
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
+
+// GET /api/reports/daily
+func (h *ReportHandler) Daily(w http.ResponseWriter, r *http.Request) {
+	from, errFrom := time.Parse(time.DateOnly, r.URL.Query().Get("from"))
+	to, errTo := time.Parse(time.DateOnly, r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil {
+		ErrorJSON(w, http.StatusBadRequest, "from and to are required dates (YYYY-MM-DD)")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	report, err := h.reportSvc.Daily(r.Context(), hhID, from, to, r.URL.Query().Get("currency"))
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
+
+// GET /api/reports/monthly
+func (h *ReportHandler) Monthly(w http.ResponseWriter, r *http.Request) {
+	from, errFrom := time.Parse(time.DateOnly, r.URL.Query().Get("from"))
+	to, errTo := time.Parse(time.DateOnly, r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil {
+		ErrorJSON(w, http.StatusBadRequest, "from and to are required dates (YYYY-MM-DD)")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	report, err := h.reportSvc.Monthly(r.Context(), hhID, from, to, r.URL.Query().Get("currency"))
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
+
+// GET /api/reports/budgets
+func (h *ReportHandler) BudgetStatus(w http.ResponseWriter, r *http.Request) {
+	var date *time.Time
+	if v := r.URL.Query().Get("date"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			ErrorJSON(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+			return
+		}
+		date = &d
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	report, err := h.reportSvc.BudgetStatus(r.Context(), hhID, date)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReportRange), errors.Is(err, service.ErrInvalidCurrency):
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrHouseholdNotFound):
+		ErrorJSON(w, http.StatusNotFound, err.Error())
+	default:
+		ErrorJSON(w, http.StatusInternalServerError, "failed to build report")
+	}
+}
+
+// parseReportRange reads the optional RFC 3339 from and to parameters. A
+// malformed value yields a message for a 400 instead.
+func parseReportRange(r *http.Request) (from, to *time.Time, msg string) {
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, nil, "from must be an RFC 3339 timestamp"
+		}
+		from = &t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, nil, "to must be an RFC 3339 timestamp"
+		}
+		to = &t
+	}
+	return from, to, ""
+}
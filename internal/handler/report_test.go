@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type memberTotals struct {
+	repository.TransactionRepository
+	calls int
+}
+
+func (f *memberTotals) SumByMember(context.Context, uuid.UUID, *time.Time, *time.Time) ([]model.MemberContribution, error) {
+	f.calls++
+	return []model.MemberContribution{}, nil
+}
+
+func TestByMemberRejectsMalformedRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"no range", "", http.StatusOK},
+		{"valid range", "?from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z", http.StatusOK},
+		{"date without time", "?from=2024-01-01", http.StatusBadRequest},
+		{"malformed to", "?from=2024-01-01T00:00:00Z&to=tomorrow", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txns := &memberTotals{}
+			h := NewReportHandler(service.NewReportService(&repository.Repos{Transactions: txns}))
+
+			rec := httptest.NewRecorder()
+			h.ByMember(rec, httptest.NewRequest(http.MethodGet, "/api/reports/by-member"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK && txns.calls != 0 {
+				t.Errorf("report built despite a malformed range")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type SearchHandler struct {
+	searchSvc *service.SearchService
+}
+
+func NewSearchHandler(searchSvc *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchSvc: searchSvc}
+}
+
+// GET /api/search
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	results, err := h.searchSvc.Search(r.Context(), hhID, r.URL.Query().Get("q"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to search")
+		return
+	}
+	JSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+type TemplateHandler struct {
+	templateSvc *service.TemplateService
+}
+
+func NewTemplateHandler(templateSvc *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateSvc: templateSvc}
+}
+
+// POST /api/templates
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.TemplateRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	tpl, err := h.templateSvc.Create(r.Context(), hhID, req)
+	if err != nil {
+		writeTemplateError(w, err, "failed to create template")
+		return
+	}
+	Created(w, "/api/templates/"+tpl.ID.String(), tpl)
+}
+
+// GET /api/templates
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	templates, err := h.templateSvc.List(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list templates")
+		return
+	}
+	JSON(w, http.StatusOK, templates)
+}
+
+// GET /api/templates/{id}
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	tpl, err := h.templateSvc.Get(r.Context(), id, hhID)
+	if err != nil {
+		writeTemplateError(w, err, "failed to get template")
+		return
+	}
+	JSON(w, http.StatusOK, tpl)
+}
+
+// PUT /api/templates/{id}
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+	var req model.TemplateRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	tpl, err := h.templateSvc.Update(r.Context(), id, hhID, req)
+	if err != nil {
+		writeTemplateError(w, err, "failed to update template")
+		return
+	}
+	JSON(w, http.StatusOK, tpl)
+}
+
+// DELETE /api/templates/{id}
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	if err := h.templateSvc.Delete(r.Context(), id, hhID); err != nil {
+		writeTemplateError(w, err, "failed to delete template")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "template deleted"})
+}
+
+// POST /api/templates/{id}/apply
+func (h *TemplateHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	// Body is optional: without one the template is used as is.
+	var req model.TransactionOverrides
+	if err := Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	txn, err := h.templateSvc.Apply(r.Context(), id, hhID, userID, req)
+	if err != nil {
+		writeTemplateError(w, err, "failed to apply template")
+		return
+	}
+	Created(w, "/api/transactions/"+txn.ID.String(), txn)
+}
+
+// writeTemplateError maps template errors, falling back to the transaction
+// mapping for errors from applying a template.
+func writeTemplateError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		ErrorJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidTemplate):
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+	default:
+		writeTransactionError(w, err, fallback)
+	}
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,6 +16,9 @@ import (
 	"github.com/howallet/howallet/internal/service"
 )
 
+// notSavedRetryAfter is the Retry-After sent when a commit was rolled back.
+const notSavedRetryAfter = time.Second
+
 type TransactionHandler struct {
 	txnSvc *service.TransactionService
 }
@@ -38,21 +44,17 @@ func (h *TransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	txn, err := h.txnSvc.Create(r.Context(), hhID, userID, req)
 	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, err.Error())
+		writeTransactionError(w, err, "failed to create transaction")
 		return
 	}
-	JSON(w, http.StatusCreated, txn)
+	Created(w, "/api/transactions/"+txn.ID.String(), txn)
 }
 
 // GET /api/transactions
 func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
 
-	q := model.ListTransactionsQuery{
-		Limit:  50,
-		Offset: 0,
-	}
-
+	q := parseTransactionFilters(r)
 	if v := r.URL.Query().Get("limit"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
 			q.Limit = int32(n)
@@ -63,6 +65,96 @@ func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
 			q.Offset = int32(n)
 		}
 	}
+
+	q.ExpandCreatedBy = expandsCreatedBy(r)
+
+	result, err := h.txnSvc.List(r.Context(), hhID, q)
+	if err != nil {
+		if errors.Is(err, service.ErrNotMember) {
+			ErrorJSON(w, http.StatusBadRequest, "created_by is not a member of this household")
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list transactions")
+		return
+	}
+	JSON(w, http.StatusOK, result)
+}
+
+// GET /api/transactions/summary
+func (h *TransactionHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	summary, err := h.txnSvc.Summary(r.Context(), hhID, parseTransactionFilters(r))
+	if err != nil {
+		if errors.Is(err, service.ErrNotMember) {
+			ErrorJSON(w, http.StatusBadRequest, "created_by is not a member of this household")
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to summarize transactions")
+		return
+	}
+	JSON(w, http.StatusOK, summary)
+}
+
+// GET /api/transactions/tags
+func (h *TransactionHandler) Tags(w http.ResponseWriter, r *http.Request) {
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	tags, err := h.txnSvc.ListTags(r.Context(), hhID, r.URL.Query().Get("prefix"))
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list tags")
+		return
+	}
+	JSON(w, http.StatusOK, tags)
+}
+
+// POST /api/transactions/tags/rename
+func (h *TransactionHandler) RenameTag(w http.ResponseWriter, r *http.Request) {
+	var req model.RenameTagRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	n, err := h.txnSvc.RenameTag(r.Context(), hhID, req.From, req.To)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTagRename) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ErrorJSON(w, http.StatusInternalServerError, "failed to rename tag")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]int64{"updated": n})
+}
+
+// POST /api/transactions/batch-delete
+func (h *TransactionHandler) BatchDelete(w http.ResponseWriter, r *http.Request) {
+	var req model.BatchDeleteRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	n, err := h.txnSvc.DeleteBatch(r.Context(), hhID, userID, req.IDs, req.IgnoreMissing)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatch) {
+			ErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeTransactionError(w, err, "failed to delete transactions")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]int{"deleted": n})
+}
+
+// parseTransactionFilters reads the filters shared by List and Summary. Malformed
+// values are ignored.
+func parseTransactionFilters(r *http.Request) model.ListTransactionsQuery {
+	var q model.ListTransactionsQuery
 	if v := r.URL.Query().Get("from"); v != "" {
 		if t, err := time.Parse(time.RFC3339, v); err == nil {
 			q.From = &t
@@ -77,18 +169,31 @@ func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
 		tt := model.TransactionType(v)
 		q.Type = &tt
 	}
-	if v := r.URL.Query().Get("account_id"); v != "" {
+	// Repeated account_id params combine: ?account_id=A&account_id=B
+	for _, v := range r.URL.Query()["account_id"] {
 		if id, err := uuid.Parse(v); err == nil {
-			q.AccountID = &id
+			q.AccountIDs = append(q.AccountIDs, id)
 		}
 	}
-
-	result, err := h.txnSvc.List(r.Context(), hhID, q)
-	if err != nil {
-		ErrorJSON(w, http.StatusInternalServerError, "failed to list transactions")
-		return
+	if v := r.URL.Query().Get("flagged"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			q.Flagged = &b
+		}
 	}
-	JSON(w, http.StatusOK, result)
+	if v := r.URL.Query().Get("created_by"); v != "" {
+		if id, err := uuid.Parse(v); err == nil {
+			q.CreatedBy = &id
+		}
+	}
+	switch r.URL.Query().Get("status") {
+	case "posted":
+		posted := true
+		q.Posted = &posted
+	case "scheduled":
+		posted := false
+		q.Posted = &posted
+	}
+	return q
 }
 
 // GET /api/transactions/{id}
@@ -102,7 +207,92 @@ func (h *TransactionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
 	txn, err := h.txnSvc.Get(r.Context(), txnID, hhID)
 	if err != nil {
-		ErrorJSON(w, http.StatusNotFound, "transaction not found")
+		writeTransactionError(w, err, "failed to get transaction")
+		return
+	}
+	if expandsCreatedBy(r) {
+		if err := h.txnSvc.ExpandCreators(r.Context(), hhID, txn); err != nil {
+			ErrorJSON(w, http.StatusInternalServerError, "failed to get transaction")
+			return
+		}
+	}
+	JSONWithETag(w, r, txn)
+}
+
+// expandsCreatedBy reports whether ?expand=created_by was requested. Expand
+// takes a comma-separated list and may be repeated.
+func expandsCreatedBy(r *http.Request) bool {
+	for _, v := range r.URL.Query()["expand"] {
+		for f := range strings.SplitSeq(v, ",") {
+			if strings.TrimSpace(f) == "created_by" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// POST /api/transactions/{id}/flag
+func (h *TransactionHandler) Flag(w http.ResponseWriter, r *http.Request) {
+	txnID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	// Body is optional: {"reason": "is this ours?"}
+	var req model.FlagTransactionRequest
+	if err := Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	txn, err := h.txnSvc.Flag(r.Context(), txnID, hhID, req.Reason)
+	if err != nil {
+		writeTransactionError(w, err, "failed to flag transaction")
+		return
+	}
+	JSON(w, http.StatusOK, txn)
+}
+
+// POST /api/transactions/{id}/duplicate
+func (h *TransactionHandler) Duplicate(w http.ResponseWriter, r *http.Request) {
+	txnID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	// Body is optional: {"amount": "12.50", "transacted_at": "..."} overrides the copy.
+	var req model.TransactionOverrides
+	if err := Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	txn, err := h.txnSvc.Duplicate(r.Context(), txnID, hhID, userID, req)
+	if err != nil {
+		writeTransactionError(w, err, "failed to duplicate transaction")
+		return
+	}
+	Created(w, "/api/transactions/"+txn.ID.String(), txn)
+}
+
+// POST /api/transactions/{id}/unflag
+func (h *TransactionHandler) Unflag(w http.ResponseWriter, r *http.Request) {
+	txnID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	txn, err := h.txnSvc.Unflag(r.Context(), txnID, hhID)
+	if err != nil {
+		writeTransactionError(w, err, "failed to unflag transaction")
 		return
 	}
 	JSON(w, http.StatusOK, txn)
@@ -127,7 +317,32 @@ func (h *TransactionHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	txn, err := h.txnSvc.Update(r.Context(), txnID, hhID, userID, req)
 	if err != nil {
-		ErrorJSON(w, http.StatusBadRequest, err.Error())
+		writeTransactionError(w, err, "failed to update transaction")
+		return
+	}
+	JSON(w, http.StatusOK, txn)
+}
+
+// PATCH /api/transactions/{id}
+func (h *TransactionHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	txnID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	var req model.PatchTransactionRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := middleware.UserIDFromCtx(r.Context())
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+
+	txn, err := h.txnSvc.Patch(r.Context(), txnID, hhID, userID, req)
+	if err != nil {
+		writeTransactionError(w, err, "failed to update transaction")
 		return
 	}
 	JSON(w, http.StatusOK, txn)
@@ -141,10 +356,43 @@ func (h *TransactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID := middleware.UserIDFromCtx(r.Context())
 	hhID := middleware.HouseholdIDFromCtx(r.Context())
-	if err := h.txnSvc.Delete(r.Context(), txnID, hhID); err != nil {
-		ErrorJSON(w, http.StatusNotFound, "transaction not found")
+	balances, err := h.txnSvc.Delete(r.Context(), txnID, hhID, userID)
+	if err != nil {
+		writeTransactionError(w, err, "failed to delete transaction")
 		return
 	}
-	JSON(w, http.StatusOK, map[string]string{"message": "transaction deleted"})
+	JSON(w, http.StatusOK, map[string]any{"message": "transaction deleted", "balances": balances})
+}
+
+// writeTransactionError maps transaction service errors to statuses without
+// exposing database details: not found is 404, validation errors are 400, an
+// exceeded daily limit is 422, a rolled-back commit is 503 (safe to retry) and
+// anything else is 500.
+func writeTransactionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrTransactionNotFound):
+		ErrorJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidAmount),
+		errors.Is(err, service.ErrTransferMissingDest),
+		errors.Is(err, service.ErrSameAccountTransfer),
+		errors.Is(err, service.ErrCurrencyMismatch),
+		errors.Is(err, service.ErrInvalidSplits),
+		errors.Is(err, service.ErrInvalidTransactedAt),
+		errors.Is(err, service.ErrInvalidTags),
+		errors.Is(err, service.ErrAccountNotFound),
+		errors.Is(err, service.ErrCategoryNotFound):
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrConflict):
+		ErrorJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrDailyLimitExceeded):
+		ErrorJSON(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, service.ErrTransactionNotSaved):
+		middleware.RetryAfterJSON(w, http.StatusServiceUnavailable, "not_saved", service.ErrTransactionNotSaved.Error(), notSavedRetryAfter)
+	case errors.Is(err, service.ErrTransactionOutcomeUnknown):
+		ErrorJSON(w, http.StatusInternalServerError, service.ErrTransactionOutcomeUnknown.Error())
+	default:
+		ErrorJSON(w, http.StatusInternalServerError, fallback)
+	}
 }
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/howallet/howallet/internal/service"
+)
+
+func TestWriteTransactionErrorCommit(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantRetry  string
+	}{
+		{"rolled back", fmt.Errorf("%w: boom", service.ErrTransactionNotSaved), http.StatusServiceUnavailable, "1"},
+		{"unknown outcome", fmt.Errorf("%w: boom", service.ErrTransactionOutcomeUnknown), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeTransactionError(rec, tt.err, "failed")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/middleware"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/service"
+)
+
+// WebhookHandler manages household webhooks. Only the owner may use it, since
+// webhooks send household data to third parties.
+type WebhookHandler struct {
+	webhookSvc *service.WebhookService
+}
+
+func NewWebhookHandler(webhookSvc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookSvc: webhookSvc}
+}
+
+// POST /api/webhooks
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	var req model.CreateWebhookRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	wh, err := h.webhookSvc.Create(r.Context(), hhID, req)
+	if err != nil {
+		writeWebhookError(w, err, "failed to create webhook")
+		return
+	}
+	JSON(w, http.StatusCreated, wh)
+}
+
+// GET /api/webhooks
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	webhooks, err := h.webhookSvc.List(r.Context(), hhID)
+	if err != nil {
+		ErrorJSON(w, http.StatusInternalServerError, "failed to list webhooks")
+		return
+	}
+	JSON(w, http.StatusOK, webhooks)
+}
+
+// PATCH /api/webhooks/{id}
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	if !requireOwner(w, r) {
+		return
+	}
+	var req model.UpdateWebhookRequest
+	if err := Decode(r, &req); err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	wh, err := h.webhookSvc.Update(r.Context(), id, hhID, req)
+	if err != nil {
+		writeWebhookError(w, err, "failed to update webhook")
+		return
+	}
+	JSON(w, http.StatusOK, wh)
+}
+
+// DELETE /api/webhooks/{id}
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	if !requireOwner(w, r) {
+		return
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	if err := h.webhookSvc.Delete(r.Context(), id, hhID); err != nil {
+		writeWebhookError(w, err, "failed to delete webhook")
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"message": "webhook deleted"})
+}
+
+// GET /api/webhooks/{id}/deliveries
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorJSON(w, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var limit int32
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = int32(n)
+		}
+	}
+
+	hhID := middleware.HouseholdIDFromCtx(r.Context())
+	deliveries, err := h.webhookSvc.Deliveries(r.Context(), id, hhID, limit)
+	if err != nil {
+		writeWebhookError(w, err, "failed to list deliveries")
+		return
+	}
+	JSON(w, http.StatusOK, deliveries)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhook):
+		ErrorJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrWebhookNotFound), errors.Is(err, service.ErrHouseholdNotFound):
+		ErrorJSON(w, http.StatusNotFound, err.Error())
+	default:
+		ErrorJSON(w, http.StatusInternalServerError, fallback)
+	}
+}
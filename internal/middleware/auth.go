@@ -2,12 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
 )
 
 type contextKey string
@@ -15,6 +18,7 @@ type contextKey string
 const (
 	ContextKeyUserID      contextKey = "user_id"
 	ContextKeyHouseholdID contextKey = "household_id"
+	ContextKeyRole        contextKey = "household_role"
 )
 
 // UserIDFromCtx extracts the authenticated user ID from context.
@@ -33,45 +37,59 @@ func HouseholdIDFromCtx(ctx context.Context) uuid.UUID {
 	return uuid.Nil
 }
 
+// RoleFromCtx returns the caller's role in the active household, or "" outside
+// HouseholdCtx / HouseholdParamCtx.
+func RoleFromCtx(ctx context.Context) model.HouseholdRole {
+	if v, ok := ctx.Value(ContextKeyRole).(model.HouseholdRole); ok {
+		return v
+	}
+	return ""
+}
+
 // JWTAuth validates the Bearer token from the Authorization header.
 func JWTAuth(cfg *config.JWTConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, `{"error":"missing authorization header"}`, http.StatusUnauthorized)
+				errorJSON(w, http.StatusUnauthorized, "missing authorization header")
 				return
 			}
 
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
-				http.Error(w, `{"error":"invalid authorization header format"}`, http.StatusUnauthorized)
+				errorJSON(w, http.StatusUnauthorized, "invalid authorization header format")
 				return
 			}
 
 			tokenStr := parts[1]
 
+			// Only the configured alg is accepted, so an RS256 public key can't
+			// be passed off as an HMAC secret, nor the other way round.
 			token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
+				kid, _ := t.Header["kid"].(string)
+				key, ok := cfg.VerificationKey(kid)
+				if !ok {
+					return nil, jwt.ErrTokenUnverifiable
 				}
-				return []byte(cfg.Secret), nil
-			})
+				return key, nil
+			}, jwt.WithValidMethods([]string{string(cfg.Algorithm)}),
+				jwt.WithIssuer(cfg.Issuer), jwt.WithAudience(cfg.Audience))
 			if err != nil || !token.Valid {
-				http.Error(w, `{"error":"invalid or expired token"}`, http.StatusUnauthorized)
+				errorJSON(w, http.StatusUnauthorized, "invalid or expired token")
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, `{"error":"invalid token claims"}`, http.StatusUnauthorized)
+				errorJSON(w, http.StatusUnauthorized, "invalid token claims")
 				return
 			}
 
 			userIDStr, _ := claims["sub"].(string)
 			userID, err := uuid.Parse(userIDStr)
-			if err != nil {
-				http.Error(w, `{"error":"invalid user id in token"}`, http.StatusUnauthorized)
+			if err != nil || userID == uuid.Nil {
+				errorJSON(w, http.StatusUnauthorized, "invalid user id in token")
 				return
 			}
 
@@ -82,8 +100,15 @@ func JWTAuth(cfg *config.JWTConfig) func(http.Handler) http.Handler {
 	}
 }
 
-// MembershipChecker is a function that verifies a user belongs to a household.
-type MembershipChecker func(ctx context.Context, householdID, userID uuid.UUID) error
+// Errors a MembershipChecker returns for a user outside the household and for
+// a household that doesn't exist; any other error is treated as a failed lookup.
+var (
+	ErrNotMember         = errors.New("not a member of this household")
+	ErrHouseholdNotFound = errors.New("household not found")
+)
+
+// MembershipChecker verifies a user belongs to a household and returns their role.
+type MembershipChecker func(ctx context.Context, householdID, userID uuid.UUID) (model.HouseholdRole, error)
 
 // HouseholdCtx reads X-Household-ID header, puts it into context,
 // and verifies the authenticated user is a member of that household.
@@ -92,24 +117,62 @@ func HouseholdCtx(checkMembership MembershipChecker) func(http.Handler) http.Han
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			hhIDStr := r.Header.Get("X-Household-ID")
 			if hhIDStr == "" {
-				http.Error(w, `{"error":"missing X-Household-ID header"}`, http.StatusBadRequest)
+				errorJSON(w, http.StatusBadRequest, "missing X-Household-ID header")
 				return
 			}
 
 			hhID, err := uuid.Parse(hhIDStr)
-			if err != nil {
-				http.Error(w, `{"error":"invalid X-Household-ID"}`, http.StatusBadRequest)
+			if err != nil || hhID == uuid.Nil {
+				errorJSON(w, http.StatusBadRequest, "invalid X-Household-ID")
 				return
 			}
 
-			userID := UserIDFromCtx(r.Context())
-			if err := checkMembership(r.Context(), hhID, userID); err != nil {
-				http.Error(w, `{"error":"not a member of this household"}`, http.StatusForbidden)
+			serveMember(w, r, next, checkMembership, hhID)
+		})
+	}
+}
+
+// HouseholdParamCtx is HouseholdCtx for routes that carry the household ID in
+// the URL (e.g. /api/households/{id}/...) instead of the header.
+func HouseholdParamCtx(checkMembership MembershipChecker, param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hhID, err := uuid.Parse(chi.URLParam(r, param))
+			if err != nil || hhID == uuid.Nil {
+				errorJSON(w, http.StatusBadRequest, "invalid household id")
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), ContextKeyHouseholdID, hhID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			serveMember(w, r, next, checkMembership, hhID)
 		})
 	}
 }
+
+// serveMember checks membership and stores the household ID and role in context.
+// Handlers behind it can rely on both IDs being set: the zero UUID, which is what
+// the FromCtx helpers return when a value is missing, is never let through.
+func serveMember(w http.ResponseWriter, r *http.Request, next http.Handler, checkMembership MembershipChecker, hhID uuid.UUID) {
+	userID := UserIDFromCtx(r.Context())
+	if userID == uuid.Nil {
+		// Only reachable if a route is mounted without JWTAuth.
+		errorJSON(w, http.StatusUnauthorized, "missing user")
+		return
+	}
+	role, err := checkMembership(r.Context(), hhID, userID)
+	if errors.Is(err, ErrNotMember) {
+		errorJSON(w, http.StatusForbidden, "not a member of this household")
+		return
+	}
+	if errors.Is(err, ErrHouseholdNotFound) {
+		errorJSON(w, http.StatusNotFound, "household not found")
+		return
+	}
+	if err != nil {
+		errorJSON(w, http.StatusInternalServerError, "failed to check membership")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), ContextKeyHouseholdID, hhID)
+	ctx = context.WithValue(ctx, ContextKeyRole, role)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+)
+
+// memberOfAll lets everyone in, so the tests below only exercise the ID guards.
+func memberOfAll(context.Context, uuid.UUID, uuid.UUID) (model.HouseholdRole, error) {
+	return model.HouseholdRoleMember, nil
+}
+
+// requireIDs fails the test if a handler behind the middleware runs without
+// both IDs set.
+func requireIDs(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if UserIDFromCtx(r.Context()) == uuid.Nil || HouseholdIDFromCtx(r.Context()) == uuid.Nil {
+			t.Error("handler reached with a zero user or household ID")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func withUser(r *http.Request, userID uuid.UUID) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ContextKeyUserID, userID))
+}
+
+func TestHouseholdCtxZeroIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		userID     uuid.UUID
+		wantStatus int
+	}{
+		{"valid", uuid.NewString(), uuid.New(), http.StatusNoContent},
+		{"missing header", "", uuid.New(), http.StatusBadRequest},
+		{"malformed header", "not-a-uuid", uuid.New(), http.StatusBadRequest},
+		{"zero household", uuid.Nil.String(), uuid.New(), http.StatusBadRequest},
+		{"no user", uuid.NewString(), uuid.Nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/accounts", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Household-ID", tt.header)
+			}
+			if tt.userID != uuid.Nil {
+				r = withUser(r, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			HouseholdCtx(memberOfAll)(requireIDs(t)).ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHouseholdParamCtxZeroIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"valid", uuid.NewString(), http.StatusNoContent},
+		{"malformed", "not-a-uuid", http.StatusBadRequest},
+		{"zero", uuid.Nil.String(), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.With(HouseholdParamCtx(memberOfAll, "id")).Get("/api/households/{id}", requireIDs(t).ServeHTTP)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/households/"+tt.id, nil), uuid.New()))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestJWTAuthZeroSubject(t *testing.T) {
+	cfg := &config.JWTConfig{
+		Algorithm: config.JWTAlgorithmHS256,
+		Secret:    "test-secret-test-secret-test-secret",
+		Issuer:    "howallet",
+		Audience:  "howallet",
+	}
+	sign := func(sub string) string {
+		key, kid := cfg.SigningKey()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": sub, "iss": cfg.Issuer, "aud": cfg.Audience, "exp": time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return s
+	}
+	tests := []struct {
+		name       string
+		sub        string
+		wantStatus int
+	}{
+		{"valid", uuid.NewString(), http.StatusNoContent},
+		{"zero subject", uuid.Nil.String(), http.StatusUnauthorized},
+		{"malformed subject", "someone", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if UserIDFromCtx(r.Context()) == uuid.Nil {
+					t.Error("handler reached with a zero user ID")
+				}
+				w.WriteHeader(http.StatusNoContent)
+			})
+			r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			r.Header.Set("Authorization", "Bearer "+sign(tt.sub))
+			rec := httptest.NewRecorder()
+			JWTAuth(cfg)(next).ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHouseholdCtxMembershipErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"member", nil, http.StatusNoContent},
+		{"not a member", ErrNotMember, http.StatusForbidden},
+		{"wrapped not a member", fmt.Errorf("check: %w", ErrNotMember), http.StatusForbidden},
+		{"unknown household", ErrHouseholdNotFound, http.StatusNotFound},
+		{"lookup failed", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(context.Context, uuid.UUID, uuid.UUID) (model.HouseholdRole, error) {
+				if tt.err != nil {
+					return "", tt.err
+				}
+				return model.HouseholdRoleMember, nil
+			}
+			r := withUser(httptest.NewRequest(http.MethodGet, "/api/accounts", nil), uuid.New())
+			r.Header.Set("X-Household-ID", uuid.NewString())
+			rec := httptest.NewRecorder()
+			rec.Header().Set(RequestIDHeader, "req-1") // as RequestIDResponse does
+			HouseholdCtx(check)(requireIDs(t)).ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.err == nil {
+				return
+			}
+			var body map[string]string
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["error"] == "" || body["request_id"] != "req-1" {
+				t.Errorf("body = %v, want an error with request_id req-1", body)
+			}
+		})
+	}
+}
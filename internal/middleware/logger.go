@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
 )
 
 // Logger is a simple request logging middleware.
@@ -21,6 +23,7 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 				slog.Int("status", ww.statusCode),
 				slog.Duration("duration", time.Since(start)),
 				slog.String("remote", r.RemoteAddr),
+				slog.String("request_id", chimw.GetReqID(r.Context())),
 			)
 		})
 	}
@@ -35,3 +38,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushing,
+// write deadlines).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/howallet/howallet/internal/config"
+)
+
+// Maintenance rejects requests while the service is in maintenance mode.
+// The mode can be switched at runtime with SetMode.
+type Maintenance struct {
+	mode       atomic.Value
+	retryAfter time.Duration
+}
+
+// NewMaintenance creates the middleware; retryAfter is advertised to rejected clients.
+func NewMaintenance(mode config.MaintenanceMode, retryAfter time.Duration) *Maintenance {
+	m := &Maintenance{retryAfter: retryAfter}
+	m.SetMode(mode)
+	return m
+}
+
+// Mode returns the current maintenance mode.
+func (m *Maintenance) Mode() config.MaintenanceMode {
+	return m.mode.Load().(config.MaintenanceMode)
+}
+
+// SetMode switches the maintenance mode for subsequent requests.
+func (m *Maintenance) SetMode(mode config.MaintenanceMode) {
+	m.mode.Store(mode)
+}
+
+// Handler returns 503 for writes in read-only mode and for everything in full mode.
+// The health check is always served so orchestrators don't restart the process.
+func (m *Maintenance) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch m.Mode() {
+		case config.MaintenanceFull:
+			RetryAfterJSON(w, http.StatusServiceUnavailable, "maintenance",
+				"service is down for maintenance, please try again later", m.retryAfter)
+			return
+		case config.MaintenanceReadOnly:
+			if !isReadMethod(r.Method) {
+				RetryAfterJSON(w, http.StatusServiceUnavailable, "maintenance_read_only",
+					"service is in read-only maintenance mode, changes are temporarily disabled", m.retryAfter)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
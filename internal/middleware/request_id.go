@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the response header carrying the request ID. chi's RequestID
+// middleware also accepts it on incoming requests, so a caller-supplied ID is kept.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDResponse echoes the request ID set by chimw.RequestID back to the client,
+// so it can be quoted in bug reports and matched to the log line. Must run after
+// chimw.RequestID.
+func RequestIDResponse(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := chimw.GetReqID(r.Context()); id != "" {
+			w.Header().Set(RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorJSON writes {"error": msg} with the request ID set by RequestIDResponse,
+// matching the error bodies the handlers write.
+func errorJSON(w http.ResponseWriter, status int, msg string) {
+	body := map[string]string{"error": msg}
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		body["request_id"] = id
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAfterResponse is the body returned with every 429/503 response.
+type RetryAfterResponse struct {
+	Error             string `json:"error"`
+	Code              string `json:"code"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+	RequestID         string `json:"request_id,omitempty"`
+}
+
+// RetryAfterJSON writes a throttling/unavailable response with a Retry-After header
+// and a consistent JSON body so clients can back off uniformly.
+// retryAfter is rounded up to whole seconds (minimum 1).
+func RetryAfterJSON(w http.ResponseWriter, status int, code, msg string, retryAfter time.Duration) {
+	secs := int(math.Ceil(retryAfter.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(RetryAfterResponse{
+		Error:             msg,
+		Code:              code,
+		RetryAfterSeconds: secs,
+		RequestID:         w.Header().Get(RequestIDHeader),
+	})
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +13,7 @@ import (
 // Enums
 // ------------------------------------------------------------------
 
+// AccountType is one of the built-in types or a custom type the household added.
 type AccountType string
 
 const (
@@ -19,6 +22,14 @@ const (
 	AccountTypeCash    AccountType = "cash"
 )
 
+// BuiltinAccountTypes are valid in every household.
+var BuiltinAccountTypes = []AccountType{AccountTypeCard, AccountTypeDeposit, AccountTypeCash}
+
+// Builtin reports whether t is one of BuiltinAccountTypes.
+func (t AccountType) Builtin() bool {
+	return slices.Contains(BuiltinAccountTypes, t)
+}
+
 type TransactionType string
 
 const (
@@ -42,6 +53,43 @@ const (
 	InvitationStatusExpired  InvitationStatus = "expired"
 )
 
+type AuditAction string
+
+const (
+	AuditActionTransactionCreated AuditAction = "transaction.created"
+	AuditActionTransactionUpdated AuditAction = "transaction.updated"
+	AuditActionTransactionDeleted AuditAction = "transaction.deleted"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventTransactionCreated WebhookEvent = "transaction.created"
+	WebhookEventTransactionDeleted WebhookEvent = "transaction.deleted"
+	WebhookEventTransactionPosted  WebhookEvent = "transaction.posted"
+	WebhookEventMemberAdded        WebhookEvent = "member.added"
+	WebhookEventBudgetThreshold    WebhookEvent = "budget.threshold_reached"
+)
+
+type NotificationKind string
+
+const (
+	NotificationKindLargeTransaction   NotificationKind = "large_transaction"
+	NotificationKindMemberJoined       NotificationKind = "member_joined"
+	NotificationKindInvitationAccepted NotificationKind = "invitation_accepted"
+	NotificationKindBudgetThreshold    NotificationKind = "budget_threshold"
+)
+
+// Valid reports whether e is an event webhooks can subscribe to.
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventTransactionCreated, WebhookEventTransactionDeleted, WebhookEventTransactionPosted,
+		WebhookEventMemberAdded, WebhookEventBudgetThreshold:
+		return true
+	}
+	return false
+}
+
 // ------------------------------------------------------------------
 // Domain entities
 // ------------------------------------------------------------------
@@ -60,6 +108,17 @@ type Household struct {
 	Name      string    `json:"name"`
 	OwnerID   uuid.UUID `json:"owner_id"`
 	CreatedAt time.Time `json:"created_at"`
+	// Timezone is the IANA zone in which transaction timestamps become dates.
+	Timezone string `json:"timezone"`
+	// DefaultCurrency is given to new accounts created without a currency.
+	DefaultCurrency string `json:"default_currency"`
+	// CaseSensitiveTags keeps the case of tags; otherwise they are lower-cased.
+	CaseSensitiveTags bool `json:"case_sensitive_tags"`
+	// FiscalMonthStartDay is the day of the month budget months start on, e.g.
+	// payday; 1 means calendar months.
+	FiscalMonthStartDay int `json:"fiscal_month_start_day"`
+	// Accounts is only populated when creating a household with accounts.
+	Accounts []Account `json:"accounts,omitempty"`
 }
 
 type HouseholdMember struct {
@@ -83,15 +142,29 @@ type Invitation struct {
 }
 
 type Account struct {
-	ID          uuid.UUID       `json:"id"`
-	HouseholdID uuid.UUID       `json:"household_id"`
-	Name        string          `json:"name"`
-	Type        AccountType     `json:"type"`
-	Balance     decimal.Decimal `json:"balance"`
-	Currency    string          `json:"currency"`
-	CreatedBy   uuid.UUID       `json:"created_by"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID              uuid.UUID       `json:"id"`
+	HouseholdID     uuid.UUID       `json:"household_id"`
+	Name            string          `json:"name"`
+	Type            AccountType     `json:"type"`
+	Balance         decimal.Decimal `json:"balance"`
+	OpeningBalance  decimal.Decimal `json:"opening_balance"` // balance at creation, never changes
+	Currency        string          `json:"currency"`
+	IncludeInTotals bool            `json:"include_in_totals"` // false: excluded from household totals
+	IsLiability     bool            `json:"is_liability"`      // balance is what is owed; subtracted from totals
+	// DailyLimit caps a day's expenses and outgoing transfers; nil: no limit.
+	DailyLimit *decimal.Decimal `json:"daily_limit"`
+	Position   int32            `json:"position"` // display order, lowest first
+	CreatedBy  uuid.UUID        `json:"created_by"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	UpdatedBy  *uuid.UUID       `json:"updated_by,omitempty"` // last member to edit the account's settings
+}
+
+// AccountBalanceAsOf is an account's balance at the end of AsOf (YYYY-MM-DD in
+// the household's timezone).
+type AccountBalanceAsOf struct {
+	AccountBalance
+	AsOf string `json:"as_of"`
 }
 
 type Transaction struct {
@@ -105,9 +178,178 @@ type Transaction struct {
 	Tags                 []string        `json:"tags"`
 	Note                 *string         `json:"note,omitempty"`
 	TransactedAt         time.Time       `json:"transacted_at"`
-	CreatedBy            uuid.UUID       `json:"created_by"`
-	CreatedAt            time.Time       `json:"created_at"`
-	UpdatedAt            time.Time       `json:"updated_at"`
+	Flagged              bool            `json:"flagged"`
+	FlagReason           *string         `json:"flag_reason,omitempty"`
+	// Posted is false while a future-dated transaction is scheduled: it
+	// affects balances only once its transacted_at arrives.
+	Posted    bool      `json:"posted"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	// Creator is only populated with ?expand=created_by, and only while the
+	// creator is still a household member.
+	Creator   *UserSummary `json:"creator,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	// Splits is only populated when fetching a single transaction.
+	Splits []TransactionSplit `json:"splits,omitempty"`
+	// Balances is only populated when creating or updating the transaction:
+	// the resulting balances of the accounts it touched.
+	Balances []AccountBalance `json:"balances,omitempty"`
+}
+
+// AccountBalance is an account's balance right after a change to its transactions.
+type AccountBalance struct {
+	AccountID uuid.UUID       `json:"account_id"`
+	Balance   decimal.Decimal `json:"balance"`
+	Currency  string          `json:"currency"`
+}
+
+// UserSummary is the public part of a user, embedded in other resources.
+type UserSummary struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+// TransactionSplit assigns part of a transaction's amount to a category.
+type TransactionSplit struct {
+	ID         uuid.UUID       `json:"id"`
+	CategoryID uuid.UUID       `json:"category_id"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// TransactionTemplate is a saved, possibly partial transaction that members can
+// turn into a real one with a single call. Fields left empty must be supplied
+// when applying it.
+type TransactionTemplate struct {
+	ID                   uuid.UUID        `json:"id"`
+	HouseholdID          uuid.UUID        `json:"household_id"`
+	Name                 string           `json:"name"`
+	Type                 TransactionType  `json:"type"`
+	Description          string           `json:"description"`
+	Amount               *decimal.Decimal `json:"amount,omitempty"`
+	AccountID            *uuid.UUID       `json:"account_id,omitempty"`
+	DestinationAccountID *uuid.UUID       `json:"destination_account_id,omitempty"`
+	Tags                 []string         `json:"tags"`
+	Note                 *string          `json:"note,omitempty"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+}
+
+type Category struct {
+	ID          uuid.UUID `json:"id"`
+	HouseholdID uuid.UUID `json:"household_id"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuditEntry records a single mutation with before/after snapshots of the entity.
+type AuditEntry struct {
+	ID          uuid.UUID       `json:"id"`
+	HouseholdID uuid.UUID       `json:"household_id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	Action      AuditAction     `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    uuid.UUID       `json:"entity_id"`
+	Before      json.RawMessage `json:"before,omitempty"`
+	After       json.RawMessage `json:"after,omitempty"`
+	UndoneAt    *time.Time      `json:"undone_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Webhook posts household events to an external URL. The secret signs each payload
+// and is only returned when the webhook is created.
+type Webhook struct {
+	ID          uuid.UUID      `json:"id"`
+	HouseholdID uuid.UUID      `json:"household_id"`
+	URL         string         `json:"url"`
+	Secret      string         `json:"secret,omitempty"`
+	Events      []WebhookEvent `json:"events"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// WebhookDelivery logs one attempt to deliver an event.
+type WebhookDelivery struct {
+	ID         uuid.UUID       `json:"id"`
+	WebhookID  uuid.UUID       `json:"webhook_id"`
+	Event      WebhookEvent    `json:"event"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempt    int32           `json:"attempt"`
+	StatusCode *int32          `json:"status_code,omitempty"`
+	Error      *string         `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Budget limits a category's expenses per month (in the household's timezone,
+// starting on its fiscal month start day). Thresholds are percentages of Amount at which members are alerted.
+type Budget struct {
+	ID          uuid.UUID       `json:"id"`
+	HouseholdID uuid.UUID       `json:"household_id"`
+	CategoryID  uuid.UUID       `json:"category_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	Thresholds  []int32         `json:"thresholds"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// BudgetRequest creates or replaces a budget. Thresholds default to 80 and 100.
+type BudgetRequest struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Amount     string    `json:"amount"`
+	Thresholds []int32   `json:"thresholds,omitempty"`
+}
+
+// BudgetAlert reports a budget threshold reached; it is the data of budget
+// notifications and webhook events.
+type BudgetAlert struct {
+	BudgetID    uuid.UUID       `json:"budget_id"`
+	CategoryID  uuid.UUID       `json:"category_id"`
+	PeriodStart string          `json:"period_start"`
+	Threshold   int32           `json:"threshold"`
+	Amount      decimal.Decimal `json:"amount"`
+	Spent       decimal.Decimal `json:"spent"`
+}
+
+// Notification is an in-app message for one user about an event in one of their
+// households. Data depends on Kind.
+type Notification struct {
+	ID          uuid.UUID        `json:"id"`
+	HouseholdID uuid.UUID        `json:"household_id"`
+	Kind        NotificationKind `json:"kind"`
+	Data        json.RawMessage  `json:"data"`
+	ReadAt      *time.Time       `json:"read_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// NotificationPreferences choose which events notify a user. A nil
+// LargeTransactionThreshold turns large-transaction notifications off.
+type NotificationPreferences struct {
+	LargeTransactionThreshold *decimal.Decimal `json:"large_transaction_threshold"`
+	MemberJoined              bool             `json:"member_joined"`
+	InvitationAccepted        bool             `json:"invitation_accepted"`
+}
+
+// UpdateNotificationPreferencesRequest replaces a user's preferences. An empty
+// or missing threshold turns large-transaction notifications off.
+type UpdateNotificationPreferencesRequest struct {
+	LargeTransactionThreshold *string `json:"large_transaction_threshold"`
+	MemberJoined              bool    `json:"member_joined"`
+	InvitationAccepted        bool    `json:"invitation_accepted"`
+}
+
+// ListNotificationsQuery pages through a user's notifications.
+type ListNotificationsQuery struct {
+	UnreadOnly bool  `json:"unread_only"`
+	Limit      int32 `json:"limit"`
+	Offset     int32 `json:"offset"`
+}
+
+// WebhookPayload is the JSON body POSTed to webhook URLs.
+type WebhookPayload struct {
+	ID          uuid.UUID    `json:"id"`
+	Event       WebhookEvent `json:"event"`
+	HouseholdID uuid.UUID    `json:"household_id"`
+	OccurredAt  time.Time    `json:"occurred_at"`
+	Data        interface{}  `json:"data"`
 }
 
 // ------------------------------------------------------------------
@@ -126,6 +368,16 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type UpdateProfileRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Email *string `json:"email,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
 type AuthResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token,omitempty"`
@@ -136,27 +388,152 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// ClientInfo identifies the device a session was started from.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session describes an active refresh token without exposing the token itself.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
 // Household
 type CreateHouseholdRequest struct {
-	Name string `json:"name"`
+	Name            string `json:"name"`
+	Timezone        string `json:"timezone,omitempty"`         // defaults to UTC
+	DefaultCurrency string `json:"default_currency,omitempty"` // defaults to DEFAULT_CURRENCY
+	// Accounts are created along with the household.
+	Accounts []CreateAccountRequest `json:"accounts,omitempty"`
+}
+
+// ImportAccountsRequest creates several accounts at once.
+type ImportAccountsRequest struct {
+	Accounts []CreateAccountRequest `json:"accounts"`
+}
+
+// HouseholdDetail is a household as seen by one of its members.
+type HouseholdDetail struct {
+	Household
+	MemberCount int64         `json:"member_count"`
+	Role        HouseholdRole `json:"role"` // the caller's role
+}
+
+// MaxFiscalMonthStartDay is the latest fiscal month start day. In months too
+// short for it, the fiscal month starts on the month's last day.
+const MaxFiscalMonthStartDay = 31
+
+// HouseholdSettings are the household-wide preferences, read and changed
+// together at /api/households/{id}/settings.
+type HouseholdSettings struct {
+	DefaultCurrency     string `json:"default_currency"`
+	Timezone            string `json:"timezone"`
+	FiscalMonthStartDay int    `json:"fiscal_month_start_day"`
+	CaseSensitiveTags   bool   `json:"case_sensitive_tags"`
+}
+
+// Settings returns the household's settings.
+func (h Household) Settings() HouseholdSettings {
+	return HouseholdSettings{
+		DefaultCurrency:     h.DefaultCurrency,
+		Timezone:            h.Timezone,
+		FiscalMonthStartDay: h.FiscalMonthStartDay,
+		CaseSensitiveTags:   h.CaseSensitiveTags,
+	}
+}
+
+// UpdateHouseholdSettingsRequest changes the settings that are set.
+type UpdateHouseholdSettingsRequest struct {
+	DefaultCurrency     *string `json:"default_currency,omitempty"`
+	Timezone            *string `json:"timezone,omitempty"`
+	FiscalMonthStartDay *int    `json:"fiscal_month_start_day,omitempty"`
+	CaseSensitiveTags   *bool   `json:"case_sensitive_tags,omitempty"`
+}
+
+type UpdateHouseholdRequest struct {
+	Name              *string `json:"name,omitempty"`
+	Timezone          *string `json:"timezone,omitempty"`
+	CaseSensitiveTags *bool   `json:"case_sensitive_tags,omitempty"`
 }
 
 type InviteRequest struct {
 	Email string `json:"email"`
 }
 
+// InviteResponse is the created invitation plus whether the mail server accepted
+// the invitation email. AcceptURL lets the owner share the link themselves when
+// email is unavailable or failed.
+type InviteResponse struct {
+	Invitation
+	AcceptURL string `json:"accept_url"`
+	EmailSent bool   `json:"email_sent"`
+}
+
+// Meta describes server capabilities clients can adapt to.
+type Meta struct {
+	EmailEnabled bool `json:"email_enabled"`
+}
+
 // Account
 type CreateAccountRequest struct {
-	Name     string      `json:"name"`
-	Type     AccountType `json:"type"`
-	Balance  string      `json:"balance"`
-	Currency string      `json:"currency"`
+	Name            string      `json:"name"`
+	Type            AccountType `json:"type"`
+	Balance         string      `json:"balance"`
+	Currency        string      `json:"currency"`
+	IncludeInTotals *bool       `json:"include_in_totals,omitempty"` // defaults to true
+	IsLiability     bool        `json:"is_liability,omitempty"`
+	DailyLimit      *string     `json:"daily_limit,omitempty"` // positive; omitted: no limit
 }
 
 type UpdateAccountRequest struct {
-	Name     *string      `json:"name,omitempty"`
-	Type     *AccountType `json:"type,omitempty"`
-	Currency *string      `json:"currency,omitempty"`
+	Name            *string      `json:"name,omitempty"`
+	Type            *AccountType `json:"type,omitempty"`
+	Currency        *string      `json:"currency,omitempty"`
+	IncludeInTotals *bool        `json:"include_in_totals,omitempty"`
+	IsLiability     *bool        `json:"is_liability,omitempty"` // switching negates the balance
+	DailyLimit      *string      `json:"daily_limit,omitempty"`  // "" removes the limit
+	// ExpectedUpdatedAt is the updated_at the client last saw; the update fails
+	// with 409 if the account has changed since.
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"`
+}
+
+// ReorderAccountsRequest lists every account of the household in display order.
+type ReorderAccountsRequest struct {
+	AccountIDs []uuid.UUID `json:"account_ids"`
+}
+
+// AccountTypeInfo describes a type the household's accounts may have.
+type AccountTypeInfo struct {
+	Name    AccountType `json:"name"`
+	BuiltIn bool        `json:"built_in"`
+}
+
+type CreateAccountTypeRequest struct {
+	Name string `json:"name"`
+}
+
+// AccountTypeSummary totals the balances of one account type. Totals holds one
+// entry per currency and is empty when the household has no such accounts.
+type AccountTypeSummary struct {
+	Type         AccountType       `json:"type"`
+	AccountCount int64             `json:"account_count"`
+	Totals       []CurrencyBalance `json:"totals"`
+}
+
+type CurrencyBalance struct {
+	Currency string          `json:"currency"`
+	Balance  decimal.Decimal `json:"balance"`
+}
+
+// ReassignTransactionsRequest names the account that receives the transactions.
+type ReassignTransactionsRequest struct {
+	TargetAccountID uuid.UUID `json:"target_account_id"`
 }
 
 // Transaction
@@ -169,6 +546,7 @@ type CreateTransactionRequest struct {
 	Tags                 []string        `json:"tags"`
 	Note                 *string         `json:"note,omitempty"`
 	TransactedAt         time.Time       `json:"transacted_at"`
+	Splits               []SplitRequest  `json:"splits,omitempty"`
 }
 
 type UpdateTransactionRequest struct {
@@ -180,21 +558,235 @@ type UpdateTransactionRequest struct {
 	Tags                 []string        `json:"tags"`
 	Note                 *string         `json:"note,omitempty"`
 	TransactedAt         time.Time       `json:"transacted_at"`
+	Splits               []SplitRequest  `json:"splits,omitempty"`
+	// ExpectedUpdatedAt is the updated_at the client last saw; the update fails
+	// with 409 if the transaction has changed since.
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"`
+}
+
+// PatchTransactionRequest changes only the fields that are set. Tags and Splits
+// are replaced when present (an empty list clears them).
+type PatchTransactionRequest struct {
+	Type                 *TransactionType `json:"type,omitempty"`
+	Description          *string          `json:"description,omitempty"`
+	Amount               *string          `json:"amount,omitempty"`
+	AccountID            *uuid.UUID       `json:"account_id,omitempty"`
+	DestinationAccountID *uuid.UUID       `json:"destination_account_id,omitempty"`
+	Tags                 []string         `json:"tags,omitempty"`
+	Note                 *string          `json:"note,omitempty"`
+	TransactedAt         *time.Time       `json:"transacted_at,omitempty"`
+	Splits               []SplitRequest   `json:"splits,omitempty"`
+	ExpectedUpdatedAt    *time.Time       `json:"expected_updated_at,omitempty"`
+}
+
+// SplitRequest is one category share of a transaction; amounts must sum to the total.
+type SplitRequest struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Amount     string    `json:"amount"`
+}
+
+type RenameTagRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// BatchDeleteRequest deletes transactions all-or-nothing. With IgnoreMissing,
+// IDs not found in the household are skipped instead of failing the batch.
+type BatchDeleteRequest struct {
+	IDs           []uuid.UUID `json:"ids"`
+	IgnoreMissing bool        `json:"ignore_missing"`
+}
+
+type FlagTransactionRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+// Category
+type CreateCategoryRequest struct {
+	Name string `json:"name"`
+}
+
+// Transaction templates
+
+// TemplateRequest creates a template or replaces all of its fields.
+type TemplateRequest struct {
+	Name                 string          `json:"name"`
+	Type                 TransactionType `json:"type"`
+	Description          string          `json:"description"`
+	Amount               *string         `json:"amount,omitempty"`
+	AccountID            *uuid.UUID      `json:"account_id,omitempty"`
+	DestinationAccountID *uuid.UUID      `json:"destination_account_id,omitempty"`
+	Tags                 []string        `json:"tags"`
+	Note                 *string         `json:"note,omitempty"`
+}
+
+// TransactionOverrides replaces fields of a transaction created from a template or
+// by duplicating another one. transacted_at defaults to now.
+type TransactionOverrides struct {
+	Description          *string    `json:"description,omitempty"`
+	Amount               *string    `json:"amount,omitempty"`
+	AccountID            *uuid.UUID `json:"account_id,omitempty"`
+	DestinationAccountID *uuid.UUID `json:"destination_account_id,omitempty"`
+	Tags                 []string   `json:"tags,omitempty"`
+	Note                 *string    `json:"note,omitempty"`
+	TransactedAt         *time.Time `json:"transacted_at,omitempty"`
+}
+
+// Webhook
+type CreateWebhookRequest struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string        `json:"url,omitempty"`
+	Events []WebhookEvent `json:"events,omitempty"`
+}
+
+// Onboarding
+type OnboardingRequest struct {
+	Account      CreateAccountRequest       `json:"account"`
+	Transactions []CreateTransactionRequest `json:"transactions"`
+}
+
+type OnboardingResponse struct {
+	Account      Account       `json:"account"`
+	Transactions []Transaction `json:"transactions"`
 }
 
 // Pagination
 type ListTransactionsQuery struct {
-	From      *time.Time       `json:"from,omitempty"`
-	To        *time.Time       `json:"to,omitempty"`
-	Type      *TransactionType `json:"type,omitempty"`
-	AccountID *uuid.UUID       `json:"account_id,omitempty"`
-	Limit     int32            `json:"limit"`
-	Offset    int32            `json:"offset"`
+	From       *time.Time       `json:"from,omitempty"`
+	To         *time.Time       `json:"to,omitempty"`
+	Type       *TransactionType `json:"type,omitempty"`
+	AccountIDs []uuid.UUID      `json:"account_ids,omitempty"`
+	Flagged    *bool            `json:"flagged,omitempty"`
+	CreatedBy  *uuid.UUID       `json:"created_by,omitempty"`
+	Posted     *bool            `json:"posted,omitempty"` // false: scheduled only
+	// ExpandCreatedBy embeds each transaction's Creator.
+	ExpandCreatedBy bool  `json:"-"`
+	Limit           int32 `json:"limit"`
+	Offset          int32 `json:"offset"`
+}
+
+// TransactionSummary totals transactions in one currency. Transfers count toward
+// Count but not toward the income and expense totals.
+type TransactionSummary struct {
+	Currency     string          `json:"currency"`
+	IncomeTotal  decimal.Decimal `json:"income_total"`
+	ExpenseTotal decimal.Decimal `json:"expense_total"`
+	Net          decimal.Decimal `json:"net"`
+	Count        int64           `json:"count"`
+}
+
+// MemberContribution totals what one member recorded against one account.
+// Transfers are not included.
+type MemberContribution struct {
+	UserID       uuid.UUID       `json:"user_id"`
+	Name         string          `json:"name"`
+	Email        string          `json:"email"`
+	AccountID    uuid.UUID       `json:"account_id"`
+	AccountName  string          `json:"account_name"`
+	Currency     string          `json:"currency"`
+	IncomeTotal  decimal.Decimal `json:"income_total"`
+	ExpenseTotal decimal.Decimal `json:"expense_total"`
+	Count        int64           `json:"count"`
+}
+
+// DailyTotal is one day of a daily report; Date is YYYY-MM-DD in the
+// household's timezone.
+type DailyTotal struct {
+	Date    string          `json:"date"`
+	Income  decimal.Decimal `json:"income"`
+	Expense decimal.Decimal `json:"expense"`
+}
+
+// DailyReport lists every day of the requested range, oldest first, including
+// days without transactions. Only accounts in Currency are counted.
+type DailyReport struct {
+	Currency string       `json:"currency"`
+	Days     []DailyTotal `json:"days"`
+}
+
+// MonthlyTotal is one fiscal month of a monthly report; Start and End are its
+// first and last day (YYYY-MM-DD, household timezone).
+type MonthlyTotal struct {
+	Start   string          `json:"start"`
+	End     string          `json:"end"`
+	Income  decimal.Decimal `json:"income"`
+	Expense decimal.Decimal `json:"expense"`
+}
+
+// MonthlyReport lists every fiscal month of the requested range, oldest first,
+// including months without transactions. Only accounts in Currency are counted.
+type MonthlyReport struct {
+	Currency            string         `json:"currency"`
+	FiscalMonthStartDay int            `json:"fiscal_month_start_day"`
+	Months              []MonthlyTotal `json:"months"`
+}
+
+// BudgetStatus is a budget's spending so far in a fiscal month. Remaining is
+// negative once the budget is exceeded.
+type BudgetStatus struct {
+	BudgetID   uuid.UUID       `json:"budget_id"`
+	CategoryID uuid.UUID       `json:"category_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	Spent      decimal.Decimal `json:"spent"`
+	Remaining  decimal.Decimal `json:"remaining"`
+}
+
+// BudgetStatusReport covers every budget for the fiscal month from PeriodStart
+// through PeriodEnd (inclusive dates).
+type BudgetStatusReport struct {
+	PeriodStart string         `json:"period_start"`
+	PeriodEnd   string         `json:"period_end"`
+	Budgets     []BudgetStatus `json:"budgets"`
+}
+
+// ListMembersQuery filters a household's members. Without a limit every match is returned.
+type ListMembersQuery struct {
+	Role   *HouseholdRole `json:"role,omitempty"`
+	Search string         `json:"search,omitempty"`
+	Limit  int32          `json:"limit"`
+	Offset int32          `json:"offset"`
 }
 
 type PaginatedResponse struct {
-	Data   interface{} `json:"data"`
-	Total  int64       `json:"total"`
-	Limit  int32       `json:"limit"`
-	Offset int32       `json:"offset"`
+	Data       interface{} `json:"data"`
+	Total      int64       `json:"total"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	HasMore    bool        `json:"has_more"`    // items exist past this page
+	TotalPages int64       `json:"total_pages"` // pages of Limit items; 0 when Total is 0
+}
+
+// NewPaginatedResponse wraps one page of data and derives HasMore and TotalPages
+// from total, limit and offset.
+func NewPaginatedResponse(data interface{}, total int64, limit, offset int32) *PaginatedResponse {
+	resp := &PaginatedResponse{
+		Data:    data,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: int64(offset)+int64(limit) < total,
+	}
+	if limit > 0 {
+		resp.TotalPages = (total + int64(limit) - 1) / int64(limit)
+	}
+	return resp
+}
+
+// SearchResultType tells which field of a SearchResult is set.
+type SearchResultType string
+
+const (
+	SearchResultAccount     SearchResultType = "account"
+	SearchResultTransaction SearchResultType = "transaction"
+)
+
+// SearchResult is one match of a household search.
+type SearchResult struct {
+	Type        SearchResultType `json:"type"`
+	Account     *Account         `json:"account,omitempty"`
+	Transaction *Transaction     `json:"transaction,omitempty"`
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/howallet/howallet/internal/model"
@@ -12,28 +13,66 @@ import (
 type AccountRepository interface {
 	Create(ctx context.Context, params CreateAccountParams) (model.Account, error)
 	GetByID(ctx context.Context, id, householdID uuid.UUID) (model.Account, error)
+	// GetForUpdate is GetByID that also locks the account until the transaction ends.
+	GetForUpdate(ctx context.Context, id, householdID uuid.UUID) (model.Account, error)
 	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Account, error)
+	// NameExists reports whether the household has an account named name, ignoring case.
+	NameExists(ctx context.Context, householdID uuid.UUID, name string) (bool, error)
+	// Search returns up to limit accounts whose name contains query, ignoring case.
+	Search(ctx context.Context, householdID uuid.UUID, query string, limit int32) ([]model.Account, error)
 	Update(ctx context.Context, params UpdateAccountParams) (model.Account, error)
 	Delete(ctx context.Context, id, householdID uuid.UUID) error
+	// Reorder sets account positions to their order in ids and returns how many
+	// of the household's accounts were updated.
+	Reorder(ctx context.Context, householdID uuid.UUID, ids []uuid.UUID) (int64, error)
+	// UpdateBalance applies delta in asset terms: a liability's balance moves
+	// the other way.
 	UpdateBalance(ctx context.Context, id uuid.UUID, delta decimal.Decimal) error
 	CountTransactions(ctx context.Context, accountID uuid.UUID) (int64, error)
+	// BalanceAsOf recomputes the balance from the opening balance and the posted
+	// transactions dated before before.
+	BalanceAsOf(ctx context.Context, id, householdID uuid.UUID, before time.Time) (decimal.Decimal, error)
+	// SumBalancesByType totals balances per account type and currency,
+	// liabilities counting negatively.
+	SumBalancesByType(ctx context.Context, householdID uuid.UUID) ([]AccountBalanceTotal, error)
 }
 
 // CreateAccountParams holds parameters for creating an account.
 type CreateAccountParams struct {
-	HouseholdID uuid.UUID
-	Name        string
-	Type        model.AccountType
-	Balance     decimal.Decimal
-	Currency    string
-	CreatedBy   uuid.UUID
+	HouseholdID     uuid.UUID
+	Name            string
+	Type            model.AccountType
+	Balance         decimal.Decimal
+	Currency        string
+	CreatedBy       uuid.UUID
+	IncludeInTotals bool
+	IsLiability     bool
+	DailyLimit      *decimal.Decimal
 }
 
 // UpdateAccountParams holds parameters for updating an account.
 type UpdateAccountParams struct {
-	ID          uuid.UUID
-	HouseholdID uuid.UUID
-	Name        *string
-	Type        *model.AccountType
-	Currency    *string
+	ID              uuid.UUID
+	HouseholdID     uuid.UUID
+	Name            *string
+	Type            *model.AccountType
+	Currency        *string
+	IncludeInTotals *bool
+	IsLiability     *bool
+	// SetDailyLimit replaces the daily limit with DailyLimit; nil removes it.
+	SetDailyLimit bool
+	DailyLimit    *decimal.Decimal
+	UpdatedBy     uuid.UUID
+	// ExpectedUpdatedAt, if set, turns the update into a no-op (pgx.ErrNoRows)
+	// when the account was modified after that time.
+	ExpectedUpdatedAt *time.Time
+}
+
+// AccountBalanceTotal is the summed balance of a household's accounts of one type
+// in one currency.
+type AccountBalanceTotal struct {
+	Type     model.AccountType
+	Currency string
+	Total    decimal.Decimal
+	Count    int64
 }
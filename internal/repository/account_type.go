@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AccountTypeRepository defines data access for a household's custom account types.
+type AccountTypeRepository interface {
+	Create(ctx context.Context, householdID uuid.UUID, name string) error
+	// ListByHousehold returns the custom type names ordered by name.
+	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]string, error)
+	// Exists reports whether the household has the type and, if so, keeps it
+	// from being deleted until the transaction ends.
+	Exists(ctx context.Context, householdID uuid.UUID, name string) (bool, error)
+	// Delete removes the type and reports whether it existed.
+	Delete(ctx context.Context, householdID uuid.UUID, name string) (bool, error)
+	// CountAccounts returns how many of the household's accounts have the type.
+	CountAccounts(ctx context.Context, householdID uuid.UUID, name string) (int64, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+)
+
+// AuditRepository defines data access for the audit log.
+type AuditRepository interface {
+	Create(ctx context.Context, params CreateAuditEntryParams) (model.AuditEntry, error)
+	// GetLastUndoable returns (and locks) the user's most recent entry in the household
+	// created at or after since that has not been undone yet.
+	GetLastUndoable(ctx context.Context, householdID, userID uuid.UUID, since time.Time) (model.AuditEntry, error)
+	MarkUndone(ctx context.Context, id uuid.UUID) error
+}
+
+// CreateAuditEntryParams holds parameters for recording an audit entry.
+type CreateAuditEntryParams struct {
+	HouseholdID uuid.UUID
+	UserID      uuid.UUID
+	Action      model.AuditAction
+	EntityType  string
+	EntityID    uuid.UUID
+	Before      []byte
+	After       []byte
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// BudgetRepository defines data access for category budgets and the alerts
+// they have fired.
+type BudgetRepository interface {
+	Create(ctx context.Context, params BudgetParams) (model.Budget, error)
+	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Budget, error)
+	// ListForCategories returns the household's budgets on any of categoryIDs.
+	ListForCategories(ctx context.Context, householdID uuid.UUID, categoryIDs []uuid.UUID) ([]model.Budget, error)
+	Update(ctx context.Context, params BudgetParams) (model.Budget, error)
+	// Delete reports false if no budget matched.
+	Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error)
+	// CategorySpend totals the category's posted expense splits transacted in [from, to).
+	CategorySpend(ctx context.Context, householdID, categoryID uuid.UUID, from, to time.Time) (decimal.Decimal, error)
+	// RecordAlert marks a threshold reached for the period starting on
+	// periodStart (a date); it reports false if that alert already fired.
+	RecordAlert(ctx context.Context, budgetID uuid.UUID, periodStart time.Time, threshold int32) (bool, error)
+}
+
+// BudgetParams holds the fields of a budget; ID is ignored by Create.
+type BudgetParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	CategoryID  uuid.UUID
+	Amount      decimal.Decimal
+	Thresholds  []int32
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+)
+
+// CategoryRepository defines data access for categories.
+type CategoryRepository interface {
+	Create(ctx context.Context, householdID uuid.UUID, name string) (model.Category, error)
+	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Category, error)
+	// CountInHousehold returns how many of ids are categories of the household.
+	CountInHousehold(ctx context.Context, householdID uuid.UUID, ids []uuid.UUID) (int64, error)
+}
@@ -9,12 +9,43 @@ import (
 
 // HouseholdRepository defines data access for households and members.
 type HouseholdRepository interface {
-	Create(ctx context.Context, name string, ownerID uuid.UUID) (model.Household, error)
+	Create(ctx context.Context, params CreateHouseholdParams) (model.Household, error)
 	GetByID(ctx context.Context, id uuid.UUID) (model.Household, error)
+	Update(ctx context.Context, params UpdateHouseholdParams) (model.Household, error)
 	ListByUser(ctx context.Context, userID uuid.UUID) ([]model.Household, error)
 	AddMember(ctx context.Context, householdID, userID uuid.UUID, role model.HouseholdRole) error
 	RemoveMember(ctx context.Context, householdID, userID uuid.UUID) error
 	GetMember(ctx context.Context, householdID, userID uuid.UUID) (model.HouseholdMember, error)
-	ListMembers(ctx context.Context, householdID uuid.UUID) ([]model.HouseholdMember, error)
+	ListMembers(ctx context.Context, params ListMembersParams) ([]model.HouseholdMember, error)
+	CountMembers(ctx context.Context, params ListMembersParams) (int64, error)
 	IsMember(ctx context.Context, householdID, userID uuid.UUID) (bool, error)
 }
+
+// CreateHouseholdParams holds parameters for creating a household.
+type CreateHouseholdParams struct {
+	Name            string
+	Timezone        string
+	DefaultCurrency string
+	OwnerID         uuid.UUID
+}
+
+// UpdateHouseholdParams changes the non-nil fields of a household.
+type UpdateHouseholdParams struct {
+	ID                  uuid.UUID
+	Name                *string
+	Timezone            *string
+	CaseSensitiveTags   *bool
+	DefaultCurrency     *string
+	FiscalMonthStartDay *int32
+}
+
+// ListMembersParams filters a household's members. Limit 0 returns all; Count
+// ignores Limit and Offset.
+type ListMembersParams struct {
+	HouseholdID uuid.UUID
+	Role        *model.HouseholdRole
+	Search      string
+	UserIDs     []uuid.UUID // empty matches every member
+	Limit       int32
+	Offset      int32
+}
@@ -12,6 +12,14 @@ import (
 type InvitationRepository interface {
 	Create(ctx context.Context, householdID, invitedBy uuid.UUID, email, token string, expiresAt time.Time) (model.Invitation, error)
 	GetByToken(ctx context.Context, token string) (model.Invitation, error)
-	Accept(ctx context.Context, id uuid.UUID) error
-	ListPendingByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Invitation, error)
+	// Accept marks a pending, unexpired invitation accepted; it reports false if
+	// the invitation was no longer pending.
+	Accept(ctx context.Context, id uuid.UUID) (bool, error)
+	// ListPendingByHousehold returns a page of pending invitations, newest first.
+	ListPendingByHousehold(ctx context.Context, householdID uuid.UUID, limit, offset int32) ([]model.Invitation, error)
+	CountPendingByHousehold(ctx context.Context, householdID uuid.UUID) (int64, error)
+	// DeletePending removes a pending invitation of the household; it reports false if none matched.
+	DeletePending(ctx context.Context, id, householdID uuid.UUID) (bool, error)
+	// ExpireStale flips pending invitations past their expiry to expired.
+	ExpireStale(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// NotificationRepository defines data access for in-app notifications and the
+// preferences that control them. The Notify methods fan one event out to its
+// recipients and return how many notifications were created.
+type NotificationRepository interface {
+	// NotifyLargeTransaction notifies the household's members other than actorID
+	// whose threshold amount reaches.
+	NotifyLargeTransaction(ctx context.Context, householdID, actorID uuid.UUID, amount decimal.Decimal, data []byte) (int64, error)
+	// NotifyMemberJoined notifies the household's members other than the new
+	// member and the inviter.
+	NotifyMemberJoined(ctx context.Context, householdID, memberID, inviterID uuid.UUID, data []byte) (int64, error)
+	// NotifyInvitationAccepted notifies the inviter, if still a member.
+	NotifyInvitationAccepted(ctx context.Context, householdID, inviterID uuid.UUID, data []byte) (int64, error)
+	// NotifyBudgetThreshold notifies every member of the household.
+	NotifyBudgetThreshold(ctx context.Context, householdID uuid.UUID, data []byte) (int64, error)
+
+	// List and Count only see notifications from households the user still belongs to.
+	List(ctx context.Context, userID uuid.UUID, q model.ListNotificationsQuery) ([]model.Notification, error)
+	Count(ctx context.Context, userID uuid.UUID, unreadOnly bool) (int64, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) (model.Notification, error)
+
+	// GetPreferences returns the defaults for users who never saved any.
+	GetPreferences(ctx context.Context, userID uuid.UUID) (model.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, userID uuid.UUID, prefs model.NotificationPreferences) (model.NotificationPreferences, error)
+}
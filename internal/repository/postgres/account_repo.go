@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	db "github.com/howallet/howallet/internal/db"
@@ -16,12 +17,15 @@ type accountRepo struct {
 
 func (r *accountRepo) Create(ctx context.Context, params repository.CreateAccountParams) (model.Account, error) {
 	a, err := r.queries.CreateAccount(ctx, db.CreateAccountParams{
-		HouseholdID: params.HouseholdID,
-		Name:        params.Name,
-		Type:        db.AccountType(params.Type),
-		Balance:     params.Balance,
-		Currency:    params.Currency,
-		CreatedBy:   params.CreatedBy,
+		HouseholdID:     params.HouseholdID,
+		Name:            params.Name,
+		Type:            db.AccountType(params.Type),
+		Balance:         params.Balance,
+		Currency:        params.Currency,
+		CreatedBy:       params.CreatedBy,
+		IncludeInTotals: params.IncludeInTotals,
+		IsLiability:     params.IsLiability,
+		DailyLimit:      toNullDecimal(params.DailyLimit),
 	})
 	if err != nil {
 		return model.Account{}, err
@@ -37,6 +41,14 @@ func (r *accountRepo) GetByID(ctx context.Context, id, householdID uuid.UUID) (m
 	return toAccountModel(a), nil
 }
 
+func (r *accountRepo) GetForUpdate(ctx context.Context, id, householdID uuid.UUID) (model.Account, error) {
+	a, err := r.queries.GetAccountForUpdate(ctx, db.GetAccountParams{ID: id, HouseholdID: householdID})
+	if err != nil {
+		return model.Account{}, err
+	}
+	return toAccountModel(a), nil
+}
+
 func (r *accountRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Account, error) {
 	rows, err := r.queries.ListAccountsByHousehold(ctx, householdID)
 	if err != nil {
@@ -49,10 +61,28 @@ func (r *accountRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID
 	return out, nil
 }
 
+func (r *accountRepo) NameExists(ctx context.Context, householdID uuid.UUID, name string) (bool, error) {
+	return r.queries.AccountNameExists(ctx, householdID, name)
+}
+
+func (r *accountRepo) Search(ctx context.Context, householdID uuid.UUID, query string, limit int32) ([]model.Account, error) {
+	rows, err := r.queries.SearchAccounts(ctx, db.SearchAccountsParams{HouseholdID: householdID, Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.Account, 0, len(rows))
+	for _, a := range rows {
+		out = append(out, toAccountModel(a))
+	}
+	return out, nil
+}
+
 func (r *accountRepo) Update(ctx context.Context, params repository.UpdateAccountParams) (model.Account, error) {
 	dbParams := db.UpdateAccountParams{
-		ID:          params.ID,
-		HouseholdID: params.HouseholdID,
+		ID:                params.ID,
+		HouseholdID:       params.HouseholdID,
+		UpdatedBy:         params.UpdatedBy,
+		ExpectedUpdatedAt: toPgTimestamptz(params.ExpectedUpdatedAt),
 	}
 	if params.Name != nil {
 		dbParams.Name = params.Name
@@ -64,6 +94,10 @@ func (r *accountRepo) Update(ctx context.Context, params repository.UpdateAccoun
 	if params.Currency != nil {
 		dbParams.Currency = params.Currency
 	}
+	dbParams.IncludeInTotals = params.IncludeInTotals
+	dbParams.IsLiability = params.IsLiability
+	dbParams.SetDailyLimit = params.SetDailyLimit
+	dbParams.DailyLimit = toNullDecimal(params.DailyLimit)
 	a, err := r.queries.UpdateAccount(ctx, dbParams)
 	if err != nil {
 		return model.Account{}, err
@@ -75,6 +109,10 @@ func (r *accountRepo) Delete(ctx context.Context, id, householdID uuid.UUID) err
 	return r.queries.DeleteAccount(ctx, db.DeleteAccountParams{ID: id, HouseholdID: householdID})
 }
 
+func (r *accountRepo) Reorder(ctx context.Context, householdID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	return r.queries.ReorderAccounts(ctx, db.ReorderAccountsParams{HouseholdID: householdID, AccountIDs: ids})
+}
+
 func (r *accountRepo) UpdateBalance(ctx context.Context, id uuid.UUID, delta decimal.Decimal) error {
 	return r.queries.UpdateAccountBalance(ctx, db.UpdateAccountBalanceParams{ID: id, Balance: delta})
 }
@@ -84,15 +122,49 @@ func (r *accountRepo) CountTransactions(ctx context.Context, accountID uuid.UUID
 }
 
 func toAccountModel(a db.Account) model.Account {
-	return model.Account{
-		ID:          a.ID,
-		HouseholdID: a.HouseholdID,
-		Name:        a.Name,
-		Type:        model.AccountType(a.Type),
-		Balance:     a.Balance,
-		Currency:    a.Currency,
-		CreatedBy:   a.CreatedBy,
-		CreatedAt:   a.CreatedAt.Time,
-		UpdatedAt:   a.UpdatedAt.Time,
+	acc := model.Account{
+		ID:              a.ID,
+		HouseholdID:     a.HouseholdID,
+		Name:            a.Name,
+		Type:            model.AccountType(a.Type),
+		Balance:         a.Balance,
+		OpeningBalance:  a.OpeningBalance,
+		Currency:        a.Currency,
+		IncludeInTotals: a.IncludeInTotals,
+		IsLiability:     a.IsLiability,
+		Position:        a.Position,
+		CreatedBy:       a.CreatedBy,
+		CreatedAt:       a.CreatedAt.Time,
+		UpdatedAt:       a.UpdatedAt.Time,
+		UpdatedBy:       nullUUIDToPtr(a.UpdatedBy),
 	}
+	if a.DailyLimit.Valid {
+		acc.DailyLimit = &a.DailyLimit.Decimal
+	}
+	return acc
+}
+
+func (r *accountRepo) BalanceAsOf(ctx context.Context, id, householdID uuid.UUID, before time.Time) (decimal.Decimal, error) {
+	return r.queries.GetAccountBalanceAsOf(ctx, db.GetAccountBalanceAsOfParams{
+		ID:          id,
+		HouseholdID: householdID,
+		Before:      before,
+	})
+}
+
+func (r *accountRepo) SumBalancesByType(ctx context.Context, householdID uuid.UUID) ([]repository.AccountBalanceTotal, error) {
+	rows, err := r.queries.SumBalancesByType(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repository.AccountBalanceTotal, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, repository.AccountBalanceTotal{
+			Type:     model.AccountType(row.Type),
+			Currency: row.Currency,
+			Total:    row.Total,
+			Count:    row.Count,
+		})
+	}
+	return out, nil
 }
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+func TestSumBalancesByTypeSkipsExcludedAccounts(t *testing.T) {
+	withTestRepos(t, func(ctx context.Context, repos *repository.Repos) {
+		user, err := repos.Users.Create(ctx, uuid.NewString()+"@example.com", "x", "Test")
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		hh, err := repos.Households.Create(ctx, repository.CreateHouseholdParams{
+			Name: "Home", Timezone: "UTC", DefaultCurrency: "USD", OwnerID: user.ID,
+		})
+		if err != nil {
+			t.Fatalf("create household: %v", err)
+		}
+
+		accounts := []struct {
+			name     string
+			accType  model.AccountType
+			balance  int64
+			included bool
+			owed     bool
+		}{
+			{"Wallet", model.AccountTypeCash, 100, true, false},
+			{"Mum's savings", model.AccountTypeCash, 1000, false, false},
+			{"Card", model.AccountTypeCard, 50, true, false},
+			{"Credit card", model.AccountTypeCard, 30, true, true},
+			{"Friend's card", model.AccountTypeCard, 500, false, false},
+		}
+		for _, a := range accounts {
+			_, err := repos.Accounts.Create(ctx, repository.CreateAccountParams{
+				HouseholdID: hh.ID, Name: a.name, Type: a.accType, Balance: decimal.NewFromInt(a.balance),
+				Currency: "USD", CreatedBy: user.ID, IncludeInTotals: a.included, IsLiability: a.owed,
+			})
+			if err != nil {
+				t.Fatalf("create account %q: %v", a.name, err)
+			}
+		}
+
+		totals, err := repos.Accounts.SumBalancesByType(ctx, hh.ID)
+		if err != nil {
+			t.Fatalf("SumBalancesByType: %v", err)
+		}
+		want := map[model.AccountType]struct {
+			total int64
+			count int64
+		}{
+			model.AccountTypeCash: {100, 1},
+			model.AccountTypeCard: {20, 2},
+		}
+		if len(totals) != len(want) {
+			t.Fatalf("totals = %+v, want %d rows", totals, len(want))
+		}
+		for _, row := range totals {
+			w := want[row.Type]
+			if !row.Total.Equal(decimal.NewFromInt(w.total)) || row.Count != w.count {
+				t.Errorf("%s: total %s over %d accounts, want %d over %d", row.Type, row.Total, row.Count, w.total, w.count)
+			}
+		}
+	})
+}
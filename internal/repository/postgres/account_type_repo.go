@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	db "github.com/howallet/howallet/internal/db"
+)
+
+type accountTypeRepo struct {
+	queries *db.Queries
+}
+
+func (r *accountTypeRepo) Create(ctx context.Context, householdID uuid.UUID, name string) error {
+	return r.queries.CreateAccountType(ctx, db.CreateAccountTypeParams{HouseholdID: householdID, Name: name})
+}
+
+func (r *accountTypeRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]string, error) {
+	return r.queries.ListAccountTypes(ctx, householdID)
+}
+
+func (r *accountTypeRepo) Exists(ctx context.Context, householdID uuid.UUID, name string) (bool, error) {
+	return r.queries.AccountTypeExists(ctx, db.AccountTypeExistsParams{HouseholdID: householdID, Name: name})
+}
+
+func (r *accountTypeRepo) Delete(ctx context.Context, householdID uuid.UUID, name string) (bool, error) {
+	n, err := r.queries.DeleteAccountType(ctx, db.DeleteAccountTypeParams{HouseholdID: householdID, Name: name})
+	return n > 0, err
+}
+
+func (r *accountTypeRepo) CountAccounts(ctx context.Context, householdID uuid.UUID, name string) (int64, error) {
+	return r.queries.CountAccountsOfType(ctx, db.CountAccountsOfTypeParams{HouseholdID: householdID, Type: name})
+}
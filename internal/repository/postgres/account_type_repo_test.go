@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/repository"
+)
+
+func TestAccountTypeLifecycle(t *testing.T) {
+	withTestRepos(t, func(ctx context.Context, repos *repository.Repos) {
+		user, err := repos.Users.Create(ctx, uuid.NewString()+"@example.com", "x", "Test")
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		hh, err := repos.Households.Create(ctx, repository.CreateHouseholdParams{
+			Name: "Home", Timezone: "UTC", DefaultCurrency: "USD", OwnerID: user.ID,
+		})
+		if err != nil {
+			t.Fatalf("create household: %v", err)
+		}
+		if err := repos.AccountTypes.Create(ctx, hh.ID, "loan"); err != nil {
+			t.Fatalf("create type: %v", err)
+		}
+
+		ok, err := repos.AccountTypes.Exists(ctx, hh.ID, "loan")
+		if err != nil || !ok {
+			t.Fatalf("Exists = %v, %v; want true", ok, err)
+		}
+		if _, err := repos.Accounts.Create(ctx, repository.CreateAccountParams{
+			HouseholdID: hh.ID, Name: "Mortgage", Type: "loan", Balance: decimal.Zero,
+			Currency: "USD", CreatedBy: user.ID, IncludeInTotals: true, IsLiability: true,
+		}); err != nil {
+			t.Fatalf("create account: %v", err)
+		}
+		if n, err := repos.AccountTypes.CountAccounts(ctx, hh.ID, "loan"); err != nil || n != 1 {
+			t.Fatalf("CountAccounts = %d, %v; want 1", n, err)
+		}
+
+		deleted, err := repos.AccountTypes.Delete(ctx, hh.ID, "loan")
+		if err != nil || !deleted {
+			t.Fatalf("Delete = %v, %v; want true", deleted, err)
+		}
+		if ok, err := repos.AccountTypes.Exists(ctx, hh.ID, "loan"); err != nil || ok {
+			t.Fatalf("Exists after delete = %v, %v; want false", ok, err)
+		}
+	})
+}
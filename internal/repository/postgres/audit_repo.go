@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+type auditRepo struct {
+	queries *db.Queries
+}
+
+func (r *auditRepo) Create(ctx context.Context, params repository.CreateAuditEntryParams) (model.AuditEntry, error) {
+	a, err := r.queries.CreateAuditEntry(ctx, db.CreateAuditEntryParams{
+		HouseholdID: params.HouseholdID,
+		UserID:      params.UserID,
+		Action:      string(params.Action),
+		EntityType:  params.EntityType,
+		EntityID:    params.EntityID,
+		Before:      params.Before,
+		After:       params.After,
+	})
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+	return toAuditEntryModel(a), nil
+}
+
+func (r *auditRepo) GetLastUndoable(ctx context.Context, householdID, userID uuid.UUID, since time.Time) (model.AuditEntry, error) {
+	a, err := r.queries.GetLastUndoableAuditEntry(ctx, db.GetLastUndoableAuditEntryParams{
+		HouseholdID: householdID,
+		UserID:      userID,
+		Since:       since,
+	})
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+	return toAuditEntryModel(a), nil
+}
+
+func (r *auditRepo) MarkUndone(ctx context.Context, id uuid.UUID) error {
+	return r.queries.MarkAuditEntryUndone(ctx, id)
+}
+
+func toAuditEntryModel(a db.AuditLog) model.AuditEntry {
+	e := model.AuditEntry{
+		ID:          a.ID,
+		HouseholdID: a.HouseholdID,
+		UserID:      a.UserID,
+		Action:      model.AuditAction(a.Action),
+		EntityType:  a.EntityType,
+		EntityID:    a.EntityID,
+		Before:      a.Before,
+		After:       a.After,
+		CreatedAt:   a.CreatedAt.Time,
+	}
+	if a.UndoneAt.Valid {
+		e.UndoneAt = &a.UndoneAt.Time
+	}
+	return e
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+type budgetRepo struct {
+	queries *db.Queries
+}
+
+func (r *budgetRepo) Create(ctx context.Context, params repository.BudgetParams) (model.Budget, error) {
+	b, err := r.queries.CreateBudget(ctx, db.CreateBudgetParams{
+		HouseholdID: params.HouseholdID,
+		CategoryID:  params.CategoryID,
+		Amount:      params.Amount,
+		Thresholds:  params.Thresholds,
+	})
+	if err != nil {
+		return model.Budget{}, err
+	}
+	return toBudgetModel(b), nil
+}
+
+func (r *budgetRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Budget, error) {
+	rows, err := r.queries.ListBudgets(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	return toBudgetModels(rows), nil
+}
+
+func (r *budgetRepo) ListForCategories(ctx context.Context, householdID uuid.UUID, categoryIDs []uuid.UUID) ([]model.Budget, error) {
+	rows, err := r.queries.ListBudgetsForCategories(ctx, db.ListBudgetsForCategoriesParams{
+		HouseholdID: householdID,
+		CategoryIds: categoryIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toBudgetModels(rows), nil
+}
+
+func (r *budgetRepo) Update(ctx context.Context, params repository.BudgetParams) (model.Budget, error) {
+	b, err := r.queries.UpdateBudget(ctx, db.UpdateBudgetParams{
+		ID:          params.ID,
+		HouseholdID: params.HouseholdID,
+		CategoryID:  params.CategoryID,
+		Amount:      params.Amount,
+		Thresholds:  params.Thresholds,
+	})
+	if err != nil {
+		return model.Budget{}, err
+	}
+	return toBudgetModel(b), nil
+}
+
+func (r *budgetRepo) Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeleteBudget(ctx, db.DeleteBudgetParams{ID: id, HouseholdID: householdID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *budgetRepo) CategorySpend(ctx context.Context, householdID, categoryID uuid.UUID, from, to time.Time) (decimal.Decimal, error) {
+	return r.queries.SumCategorySpend(ctx, db.SumCategorySpendParams{
+		HouseholdID: householdID,
+		CategoryID:  categoryID,
+		From:        from,
+		To:          to,
+	})
+}
+
+func (r *budgetRepo) RecordAlert(ctx context.Context, budgetID uuid.UUID, periodStart time.Time, threshold int32) (bool, error) {
+	n, err := r.queries.CreateBudgetAlert(ctx, db.CreateBudgetAlertParams{
+		BudgetID:    budgetID,
+		PeriodStart: periodStart,
+		Threshold:   threshold,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func toBudgetModel(b db.Budget) model.Budget {
+	return model.Budget{
+		ID:          b.ID,
+		HouseholdID: b.HouseholdID,
+		CategoryID:  b.CategoryID,
+		Amount:      b.Amount,
+		Thresholds:  b.Thresholds,
+		CreatedAt:   b.CreatedAt.Time,
+		UpdatedAt:   b.UpdatedAt.Time,
+	}
+}
+
+func toBudgetModels(rows []db.Budget) []model.Budget {
+	out := make([]model.Budget, 0, len(rows))
+	for _, b := range rows {
+		out = append(out, toBudgetModel(b))
+	}
+	return out
+}
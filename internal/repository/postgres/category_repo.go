@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+)
+
+type categoryRepo struct {
+	queries *db.Queries
+}
+
+func (r *categoryRepo) Create(ctx context.Context, householdID uuid.UUID, name string) (model.Category, error) {
+	c, err := r.queries.CreateCategory(ctx, db.CreateCategoryParams{HouseholdID: householdID, Name: name})
+	if err != nil {
+		return model.Category{}, err
+	}
+	return toCategoryModel(c), nil
+}
+
+func (r *categoryRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Category, error) {
+	rows, err := r.queries.ListCategories(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.Category, 0, len(rows))
+	for _, c := range rows {
+		out = append(out, toCategoryModel(c))
+	}
+	return out, nil
+}
+
+func (r *categoryRepo) CountInHousehold(ctx context.Context, householdID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	return r.queries.CountHouseholdCategories(ctx, db.CountHouseholdCategoriesParams{HouseholdID: householdID, IDs: ids})
+}
+
+func toCategoryModel(c db.Category) model.Category {
+	return model.Category{
+		ID:          c.ID,
+		HouseholdID: c.HouseholdID,
+		Name:        c.Name,
+		CreatedAt:   c.CreatedAt.Time,
+	}
+}
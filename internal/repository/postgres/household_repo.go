@@ -2,18 +2,27 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
 	db "github.com/howallet/howallet/internal/db"
 	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
 )
 
 type householdRepo struct {
 	queries *db.Queries
 }
 
-func (r *householdRepo) Create(ctx context.Context, name string, ownerID uuid.UUID) (model.Household, error) {
-	h, err := r.queries.CreateHousehold(ctx, db.CreateHouseholdParams{Name: name, OwnerID: ownerID})
+func (r *householdRepo) Create(ctx context.Context, params repository.CreateHouseholdParams) (model.Household, error) {
+	h, err := r.queries.CreateHousehold(ctx, db.CreateHouseholdParams{
+		Name:            params.Name,
+		OwnerID:         params.OwnerID,
+		Timezone:        params.Timezone,
+		DefaultCurrency: params.DefaultCurrency,
+	})
 	if err != nil {
 		return model.Household{}, err
 	}
@@ -28,6 +37,21 @@ func (r *householdRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Househ
 	return toHouseholdModel(h), nil
 }
 
+func (r *householdRepo) Update(ctx context.Context, params repository.UpdateHouseholdParams) (model.Household, error) {
+	h, err := r.queries.UpdateHousehold(ctx, db.UpdateHouseholdParams{
+		ID:                  params.ID,
+		Name:                params.Name,
+		Timezone:            params.Timezone,
+		CaseSensitiveTags:   params.CaseSensitiveTags,
+		DefaultCurrency:     params.DefaultCurrency,
+		FiscalMonthStartDay: params.FiscalMonthStartDay,
+	})
+	if err != nil {
+		return model.Household{}, err
+	}
+	return toHouseholdModel(h), nil
+}
+
 func (r *householdRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.Household, error) {
 	rows, err := r.queries.ListUserHouseholds(ctx, userID)
 	if err != nil {
@@ -71,8 +95,17 @@ func (r *householdRepo) GetMember(ctx context.Context, householdID, userID uuid.
 	}, nil
 }
 
-func (r *householdRepo) ListMembers(ctx context.Context, householdID uuid.UUID) ([]model.HouseholdMember, error) {
-	rows, err := r.queries.ListHouseholdMembers(ctx, householdID)
+func (r *householdRepo) ListMembers(ctx context.Context, params repository.ListMembersParams) ([]model.HouseholdMember, error) {
+	dbParams := db.ListHouseholdMembersParams{
+		HouseholdID: params.HouseholdID,
+		Offset:      params.Offset,
+		Column6:     params.UserIDs,
+	}
+	dbParams.Column2, dbParams.Column3 = memberFilters(params)
+	if params.Limit > 0 {
+		dbParams.Limit = pgtype.Int4{Int32: params.Limit, Valid: true}
+	}
+	rows, err := r.queries.ListHouseholdMembers(ctx, dbParams)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +123,23 @@ func (r *householdRepo) ListMembers(ctx context.Context, householdID uuid.UUID)
 	return out, nil
 }
 
+func (r *householdRepo) CountMembers(ctx context.Context, params repository.ListMembersParams) (int64, error) {
+	dbParams := db.CountHouseholdMembersParams{HouseholdID: params.HouseholdID, Column4: params.UserIDs}
+	dbParams.Column2, dbParams.Column3 = memberFilters(params)
+	return r.queries.CountHouseholdMembers(ctx, dbParams)
+}
+
+// memberFilters converts the optional role and search filters.
+func memberFilters(params repository.ListMembersParams) (role, search pgtype.Text) {
+	if params.Role != nil {
+		role = pgtype.Text{String: string(*params.Role), Valid: true}
+	}
+	if params.Search != "" {
+		search = pgtype.Text{String: params.Search, Valid: true}
+	}
+	return role, search
+}
+
 func (r *householdRepo) IsMember(ctx context.Context, householdID, userID uuid.UUID) (bool, error) {
 	return r.queries.IsHouseholdMember(ctx, db.IsHouseholdMemberParams{
 		HouseholdID: householdID,
@@ -97,11 +147,28 @@ func (r *householdRepo) IsMember(ctx context.Context, householdID, userID uuid.U
 	})
 }
 
+// householdSettings is the JSON stored in households.settings.
+type householdSettings struct {
+	FiscalMonthStartDay int `json:"fiscal_month_start_day"`
+}
+
 func toHouseholdModel(h db.Household) model.Household {
+	// Settings are only written by UpdateHousehold; anything unreadable or
+	// missing falls back to the defaults.
+	var settings householdSettings
+	_ = json.Unmarshal(h.Settings, &settings)
+	if settings.FiscalMonthStartDay < 1 || settings.FiscalMonthStartDay > model.MaxFiscalMonthStartDay {
+		settings.FiscalMonthStartDay = 1
+	}
+
 	return model.Household{
-		ID:        h.ID,
-		Name:      h.Name,
-		OwnerID:   h.OwnerID,
-		CreatedAt: h.CreatedAt.Time,
+		ID:                  h.ID,
+		Name:                h.Name,
+		OwnerID:             h.OwnerID,
+		CreatedAt:           h.CreatedAt.Time,
+		Timezone:            h.Timezone,
+		DefaultCurrency:     h.DefaultCurrency,
+		CaseSensitiveTags:   h.CaseSensitiveTags,
+		FiscalMonthStartDay: settings.FiscalMonthStartDay,
 	}
 }
@@ -35,12 +35,17 @@ func (r *invitationRepo) GetByToken(ctx context.Context, token string) (model.In
 	return toInvitationModel(inv), nil
 }
 
-func (r *invitationRepo) Accept(ctx context.Context, id uuid.UUID) error {
-	return r.queries.AcceptInvitation(ctx, id)
+func (r *invitationRepo) Accept(ctx context.Context, id uuid.UUID) (bool, error) {
+	n, err := r.queries.AcceptInvitation(ctx, id)
+	return n > 0, err
 }
 
-func (r *invitationRepo) ListPendingByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Invitation, error) {
-	rows, err := r.queries.ListPendingInvitations(ctx, householdID)
+func (r *invitationRepo) ListPendingByHousehold(ctx context.Context, householdID uuid.UUID, limit, offset int32) ([]model.Invitation, error) {
+	rows, err := r.queries.ListPendingInvitations(ctx, db.ListPendingInvitationsParams{
+		HouseholdID: householdID,
+		Limit:       limit,
+		Offset:      offset,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +56,22 @@ func (r *invitationRepo) ListPendingByHousehold(ctx context.Context, householdID
 	return out, nil
 }
 
+func (r *invitationRepo) CountPendingByHousehold(ctx context.Context, householdID uuid.UUID) (int64, error) {
+	return r.queries.CountPendingInvitations(ctx, householdID)
+}
+
+func (r *invitationRepo) DeletePending(ctx context.Context, id, householdID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeletePendingInvitation(ctx, db.DeletePendingInvitationParams{ID: id, HouseholdID: householdID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *invitationRepo) ExpireStale(ctx context.Context) (int64, error) {
+	return r.queries.ExpireStaleInvitations(ctx)
+}
+
 func toInvitationModel(i db.Invitation) model.Invitation {
 	return model.Invitation{
 		ID:          i.ID,
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+)
+
+type notificationRepo struct {
+	queries *db.Queries
+}
+
+func (r *notificationRepo) NotifyLargeTransaction(ctx context.Context, householdID, actorID uuid.UUID, amount decimal.Decimal, data []byte) (int64, error) {
+	return r.queries.CreateLargeTransactionNotifications(ctx, db.CreateLargeTransactionNotificationsParams{
+		HouseholdID: householdID,
+		ActorID:     actorID,
+		Amount:      amount,
+		Data:        data,
+	})
+}
+
+func (r *notificationRepo) NotifyMemberJoined(ctx context.Context, householdID, memberID, inviterID uuid.UUID, data []byte) (int64, error) {
+	return r.queries.CreateMemberJoinedNotifications(ctx, db.CreateMemberJoinedNotificationsParams{
+		HouseholdID: householdID,
+		MemberID:    memberID,
+		InviterID:   inviterID,
+		Data:        data,
+	})
+}
+
+func (r *notificationRepo) NotifyInvitationAccepted(ctx context.Context, householdID, inviterID uuid.UUID, data []byte) (int64, error) {
+	return r.queries.CreateInvitationAcceptedNotification(ctx, db.CreateInvitationAcceptedNotificationParams{
+		HouseholdID: householdID,
+		InviterID:   inviterID,
+		Data:        data,
+	})
+}
+
+func (r *notificationRepo) NotifyBudgetThreshold(ctx context.Context, householdID uuid.UUID, data []byte) (int64, error) {
+	return r.queries.CreateBudgetNotifications(ctx, db.CreateBudgetNotificationsParams{HouseholdID: householdID, Data: data})
+}
+
+func (r *notificationRepo) List(ctx context.Context, userID uuid.UUID, q model.ListNotificationsQuery) ([]model.Notification, error) {
+	rows, err := r.queries.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: q.UnreadOnly,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.Notification, 0, len(rows))
+	for _, n := range rows {
+		out = append(out, toNotificationModel(n))
+	}
+	return out, nil
+}
+
+func (r *notificationRepo) Count(ctx context.Context, userID uuid.UUID, unreadOnly bool) (int64, error) {
+	return r.queries.CountNotifications(ctx, db.CountNotificationsParams{UserID: userID, UnreadOnly: unreadOnly})
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, id, userID uuid.UUID) (model.Notification, error) {
+	n, err := r.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: id, UserID: userID})
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return toNotificationModel(n), nil
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (model.NotificationPreferences, error) {
+	p, err := r.queries.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Mirrors the column defaults.
+		return model.NotificationPreferences{MemberJoined: true, InvitationAccepted: true}, nil
+	}
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+	return toNotificationPreferences(p), nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, userID uuid.UUID, prefs model.NotificationPreferences) (model.NotificationPreferences, error) {
+	params := db.UpsertNotificationPreferencesParams{
+		UserID:             userID,
+		MemberJoined:       prefs.MemberJoined,
+		InvitationAccepted: prefs.InvitationAccepted,
+	}
+	if prefs.LargeTransactionThreshold != nil {
+		params.LargeTransactionThreshold = decimal.NewNullDecimal(*prefs.LargeTransactionThreshold)
+	}
+	p, err := r.queries.UpsertNotificationPreferences(ctx, params)
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+	return toNotificationPreferences(p), nil
+}
+
+func toNotificationModel(n db.Notification) model.Notification {
+	out := model.Notification{
+		ID:          n.ID,
+		HouseholdID: n.HouseholdID,
+		Kind:        model.NotificationKind(n.Kind),
+		Data:        n.Data,
+		CreatedAt:   n.CreatedAt.Time,
+	}
+	if n.ReadAt.Valid {
+		out.ReadAt = &n.ReadAt.Time
+	}
+	return out
+}
+
+func toNotificationPreferences(p db.NotificationPreference) model.NotificationPreferences {
+	out := model.NotificationPreferences{
+		MemberJoined:       p.MemberJoined,
+		InvitationAccepted: p.InvitationAccepted,
+	}
+	if p.LargeTransactionThreshold.Valid {
+		out.LargeTransactionThreshold = &p.LargeTransactionThreshold.Decimal
+	}
+	return out
+}
@@ -2,81 +2,72 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	db "github.com/howallet/howallet/internal/db"
 	"github.com/howallet/howallet/internal/repository"
 )
 
-// Repos groups all postgres repository implementations.
-type Repos struct {
+// store runs transactions on the pool and builds the repositories over it.
+type store struct {
 	pool    *pgxpool.Pool
 	queries *db.Queries
-
-	Users         repository.UserRepository
-	Accounts      repository.AccountRepository
-	Transactions  repository.TransactionRepository
-	Households    repository.HouseholdRepository
-	Invitations   repository.InvitationRepository
-	RefreshTokens repository.RefreshTokenRepository
 }
 
-// New creates all postgres repositories from a connection pool.
-func New(pool *pgxpool.Pool) *Repos {
-	queries := db.New(pool)
-	r := &Repos{pool: pool, queries: queries}
+var _ repository.UnitOfWork = (*store)(nil)
 
-	r.Users = &userRepo{queries: queries}
-	r.Accounts = &accountRepo{queries: queries}
-	r.Transactions = &transactionRepo{queries: queries}
-	r.Households = &householdRepo{queries: queries}
-	r.Invitations = &invitationRepo{queries: queries}
-	r.RefreshTokens = &refreshTokenRepo{queries: queries}
+// New creates all postgres repositories from a connection pool.
+func New(pool *pgxpool.Pool) *repository.Repos {
+	s := &store{pool: pool, queries: db.New(pool)}
+	return s.repos(s.queries)
+}
 
-	return r
+// repos creates every repository on top of queries, which may be bound to a tx.
+func (s *store) repos(queries *db.Queries) *repository.Repos {
+	return &repository.Repos{
+		UnitOfWork:    s,
+		Users:         &userRepo{queries: queries},
+		Accounts:      &accountRepo{queries: queries},
+		AccountTypes:  &accountTypeRepo{queries: queries},
+		Transactions:  &transactionRepo{queries: queries},
+		Households:    &householdRepo{queries: queries},
+		Invitations:   &invitationRepo{queries: queries},
+		RefreshTokens: &refreshTokenRepo{queries: queries},
+		Audit:         &auditRepo{queries: queries},
+		Categories:    &categoryRepo{queries: queries},
+		Webhooks:      &webhookRepo{queries: queries},
+		Templates:     &templateRepo{queries: queries},
+		Notifications: &notificationRepo{queries: queries},
+		Budgets:       &budgetRepo{queries: queries},
+	}
 }
 
 // RunInTx executes fn inside a database transaction.
-func (r *Repos) RunInTx(ctx context.Context, fn repository.TxFunc) error {
-	tx, err := r.pool.Begin(ctx)
+func (s *store) RunInTx(ctx context.Context, fn repository.TxFunc) error {
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Create a child Repos with transactional queries
-	qtx := r.queries.WithTx(tx)
-	txRepos := &Repos{pool: r.pool, queries: qtx}
-	txRepos.Users = &userRepo{queries: qtx}
-	txRepos.Accounts = &accountRepo{queries: qtx}
-	txRepos.Transactions = &transactionRepo{queries: qtx}
-	txRepos.Households = &householdRepo{queries: qtx}
-	txRepos.Invitations = &invitationRepo{queries: qtx}
-	txRepos.RefreshTokens = &refreshTokenRepo{queries: qtx}
-
 	// Store transactional repos in context so services can access them
-	ctx = WithTxRepos(ctx, txRepos)
+	ctx = repository.WithTxRepos(ctx, s.repos(s.queries.WithTx(tx)))
 	if err := fn(ctx); err != nil {
 		return err
 	}
-	return tx.Commit(ctx)
-}
-
-// --- context helpers for transactional repos ---
-
-type txReposKey struct{}
-
-// WithTxRepos stores transactional repos in context.
-func WithTxRepos(ctx context.Context, repos *Repos) context.Context {
-	return context.WithValue(ctx, txReposKey{}, repos)
-}
-
-// TxReposFromCtx returns the transactional repos from context, or nil.
-func TxReposFromCtx(ctx context.Context) *Repos {
-	if v, ok := ctx.Value(txReposKey{}).(*Repos); ok {
-		return v
+	if err := tx.Commit(ctx); err != nil {
+		// An error from the server means it rolled back. Anything else, such as
+		// a dropped connection, may have struck after the commit took effect.
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrTxCommitRollback) || errors.As(err, &pgErr) {
+			return fmt.Errorf("%w: %w", repository.ErrCommitFailed, err)
+		}
+		return fmt.Errorf("%w: %w", repository.ErrCommitUnknown, err)
 	}
 	return nil
 }
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/repository"
+	"github.com/howallet/howallet/migrations"
+)
+
+// errRollback ends the transaction withTestRepos wraps a test in.
+var errRollback = errors.New("rollback")
+
+// withTestRepos migrates the database at TEST_DATABASE_URL and runs fn in a
+// transaction that is rolled back afterwards, so tests leave nothing behind.
+// Without TEST_DATABASE_URL the test is skipped.
+func withTestRepos(t *testing.T, fn func(ctx context.Context, repos *repository.Repos)) {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := db.Migrate(ctx, pool, migrations.FS); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	err = New(pool).RunInTx(ctx, func(txCtx context.Context) error {
+		fn(txCtx, repository.TxReposFromCtx(txCtx))
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("run in tx: %v", err)
+	}
+}
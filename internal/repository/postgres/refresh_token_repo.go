@@ -7,17 +7,21 @@ import (
 	"github.com/google/uuid"
 	db "github.com/howallet/howallet/internal/db"
 	"github.com/howallet/howallet/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type refreshTokenRepo struct {
 	queries *db.Queries
 }
 
-func (r *refreshTokenRepo) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+func (r *refreshTokenRepo) Create(ctx context.Context, params repository.CreateRefreshTokenParams) (uuid.UUID, error) {
 	return r.queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
-		UserID:    userID,
-		TokenHash: tokenHash,
-		ExpiresAt: expiresAt,
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		ExpiresAt: params.ExpiresAt,
+		UserAgent: toOptionalPgText(params.UserAgent),
+		IPAddress: toOptionalPgText(params.IPAddress),
+		FamilyID:  params.FamilyID,
 	})
 }
 
@@ -26,13 +30,47 @@ func (r *refreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (rep
 	if err != nil {
 		return repository.RefreshTokenRow{}, err
 	}
-	return repository.RefreshTokenRow{
-		ID:        rt.ID,
-		UserID:    rt.UserID,
-		TokenHash: rt.TokenHash,
-		ExpiresAt: rt.ExpiresAt.Time,
-		CreatedAt: rt.CreatedAt.Time,
-	}, nil
+	return toRefreshTokenRow(rt), nil
+}
+
+func (r *refreshTokenRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]repository.RefreshTokenRow, error) {
+	rows, err := r.queries.ListRefreshTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repository.RefreshTokenRow, 0, len(rows))
+	for _, rt := range rows {
+		out = append(out, toRefreshTokenRow(rt))
+	}
+	return out, nil
+}
+
+func (r *refreshTokenRepo) MarkUsed(ctx context.Context, id, replacedBy uuid.UUID) (bool, error) {
+	n, err := r.queries.MarkRefreshTokenUsed(ctx, db.MarkRefreshTokenUsedParams{ID: id, ReplacedBy: replacedBy})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *refreshTokenRepo) DeleteFamily(ctx context.Context, familyID uuid.UUID) error {
+	return r.queries.DeleteRefreshTokenFamily(ctx, familyID)
+}
+
+func (r *refreshTokenRepo) DeleteByID(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeleteUserRefreshToken(ctx, db.DeleteUserRefreshTokenParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *refreshTokenRepo) DeleteByHash(ctx context.Context, tokenHash string, userID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeleteUserRefreshTokenByHash(ctx, db.DeleteUserRefreshTokenByHashParams{TokenHash: tokenHash, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 func (r *refreshTokenRepo) Delete(ctx context.Context, tokenHash string) error {
@@ -43,6 +81,34 @@ func (r *refreshTokenRepo) DeleteByUser(ctx context.Context, userID uuid.UUID) e
 	return r.queries.DeleteUserRefreshTokens(ctx, userID)
 }
 
-func (r *refreshTokenRepo) DeleteExpired(ctx context.Context) error {
+func (r *refreshTokenRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	return r.queries.DeleteExpiredRefreshTokens(ctx)
 }
+
+func (r *refreshTokenRepo) DeleteIdle(ctx context.Context, idleBefore time.Time) (int64, error) {
+	return r.queries.DeleteIdleRefreshTokens(ctx, idleBefore)
+}
+
+func toRefreshTokenRow(rt db.RefreshToken) repository.RefreshTokenRow {
+	row := repository.RefreshTokenRow{
+		ID:         rt.ID,
+		UserID:     rt.UserID,
+		TokenHash:  rt.TokenHash,
+		ExpiresAt:  rt.ExpiresAt.Time,
+		CreatedAt:  rt.CreatedAt.Time,
+		LastUsedAt: rt.LastUsedAt.Time,
+		UserAgent:  rt.UserAgent.String,
+		IPAddress:  rt.IPAddress.String,
+		FamilyID:   rt.FamilyID,
+		ReplacedBy: nullUUIDToPtr(rt.ReplacedBy),
+	}
+	if rt.UsedAt.Valid {
+		row.UsedAt = &rt.UsedAt.Time
+	}
+	return row
+}
+
+// toOptionalPgText stores empty strings as NULL.
+func toOptionalPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+type templateRepo struct {
+	queries *db.Queries
+}
+
+func (r *templateRepo) Create(ctx context.Context, p repository.TemplateParams) (model.TransactionTemplate, error) {
+	t, err := r.queries.CreateTransactionTemplate(ctx, db.CreateTransactionTemplateParams{
+		HouseholdID:          p.HouseholdID,
+		Name:                 p.Name,
+		Type:                 db.TransactionType(p.Type),
+		Description:          p.Description,
+		Amount:               toNullDecimal(p.Amount),
+		AccountID:            toNullUUID(p.AccountID),
+		DestinationAccountID: toNullUUID(p.DestinationAccountID),
+		Tags:                 p.Tags,
+		Note:                 toPgText(p.Note),
+	})
+	if err != nil {
+		return model.TransactionTemplate{}, err
+	}
+	return toTemplateModel(t), nil
+}
+
+func (r *templateRepo) GetByID(ctx context.Context, id, householdID uuid.UUID) (model.TransactionTemplate, error) {
+	t, err := r.queries.GetTransactionTemplate(ctx, db.GetTransactionTemplateParams{ID: id, HouseholdID: householdID})
+	if err != nil {
+		return model.TransactionTemplate{}, err
+	}
+	return toTemplateModel(t), nil
+}
+
+func (r *templateRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.TransactionTemplate, error) {
+	rows, err := r.queries.ListTransactionTemplates(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.TransactionTemplate, 0, len(rows))
+	for _, t := range rows {
+		out = append(out, toTemplateModel(t))
+	}
+	return out, nil
+}
+
+func (r *templateRepo) Update(ctx context.Context, p repository.TemplateParams) (model.TransactionTemplate, error) {
+	t, err := r.queries.UpdateTransactionTemplate(ctx, db.UpdateTransactionTemplateParams{
+		ID:                   p.ID,
+		HouseholdID:          p.HouseholdID,
+		Name:                 p.Name,
+		Type:                 db.TransactionType(p.Type),
+		Description:          p.Description,
+		Amount:               toNullDecimal(p.Amount),
+		AccountID:            toNullUUID(p.AccountID),
+		DestinationAccountID: toNullUUID(p.DestinationAccountID),
+		Tags:                 p.Tags,
+		Note:                 toPgText(p.Note),
+	})
+	if err != nil {
+		return model.TransactionTemplate{}, err
+	}
+	return toTemplateModel(t), nil
+}
+
+func (r *templateRepo) Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeleteTransactionTemplate(ctx, db.DeleteTransactionTemplateParams{ID: id, HouseholdID: householdID})
+	return n > 0, err
+}
+
+func toTemplateModel(t db.TransactionTemplate) model.TransactionTemplate {
+	tpl := model.TransactionTemplate{
+		ID:                   t.ID,
+		HouseholdID:          t.HouseholdID,
+		Name:                 t.Name,
+		Type:                 model.TransactionType(t.Type),
+		Description:          t.Description,
+		AccountID:            nullUUIDToPtr(t.AccountID),
+		DestinationAccountID: nullUUIDToPtr(t.DestinationAccountID),
+		Tags:                 t.Tags,
+		CreatedAt:            t.CreatedAt.Time,
+		UpdatedAt:            t.UpdatedAt.Time,
+	}
+	if t.Amount.Valid {
+		tpl.Amount = &t.Amount.Decimal
+	}
+	if t.Note.Valid {
+		tpl.Note = &t.Note.String
+	}
+	return tpl
+}
+
+func toNullDecimal(d *decimal.Decimal) decimal.NullDecimal {
+	if d == nil {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NullDecimal{Decimal: *d, Valid: true}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/howallet/howallet/internal/model"
 	"github.com/howallet/howallet/internal/repository"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
 type transactionRepo struct {
@@ -29,6 +30,7 @@ func (r *transactionRepo) Create(ctx context.Context, params repository.CreateTr
 			Valid: true,
 		},
 		CreatedBy: params.CreatedBy,
+		Posted:    params.Posted,
 	}
 	if params.DestinationAccountID != nil {
 		dbParams.DestinationAccountID = toNullUUID(params.DestinationAccountID)
@@ -53,14 +55,21 @@ func (r *transactionRepo) List(ctx context.Context, params repository.ListTransa
 		HouseholdID: params.HouseholdID,
 		Column2:     toPgTimestamptz(params.From),
 		Column3:     toPgTimestamptz(params.To),
+		Column7:     toNullUUID(params.CreatedBy),
 		Limit:       params.Limit,
 		Offset:      params.Offset,
 	}
 	if params.Type != nil {
 		dbParams.Column4 = pgtype.Text{String: string(*params.Type), Valid: true}
 	}
-	if params.AccountID != nil {
-		dbParams.Column5 = toNullUUID(params.AccountID)
+	if len(params.AccountIDs) > 0 {
+		dbParams.Column5 = params.AccountIDs
+	}
+	if params.Flagged != nil {
+		dbParams.Column6 = pgtype.Bool{Bool: *params.Flagged, Valid: true}
+	}
+	if params.Posted != nil {
+		dbParams.Column8 = pgtype.Bool{Bool: *params.Posted, Valid: true}
 	}
 	rows, err := r.queries.ListTransactions(ctx, dbParams)
 	if err != nil {
@@ -78,16 +87,152 @@ func (r *transactionRepo) Count(ctx context.Context, params repository.CountTran
 		HouseholdID: params.HouseholdID,
 		Column2:     toPgTimestamptz(params.From),
 		Column3:     toPgTimestamptz(params.To),
+		Column7:     toNullUUID(params.CreatedBy),
 	}
 	if params.Type != nil {
 		dbParams.Column4 = pgtype.Text{String: string(*params.Type), Valid: true}
 	}
-	if params.AccountID != nil {
-		dbParams.Column5 = toNullUUID(params.AccountID)
+	if len(params.AccountIDs) > 0 {
+		dbParams.Column5 = params.AccountIDs
+	}
+	if params.Flagged != nil {
+		dbParams.Column6 = pgtype.Bool{Bool: *params.Flagged, Valid: true}
+	}
+	if params.Posted != nil {
+		dbParams.Column8 = pgtype.Bool{Bool: *params.Posted, Valid: true}
 	}
 	return r.queries.CountTransactions(ctx, dbParams)
 }
 
+func (r *transactionRepo) Summarize(ctx context.Context, params repository.CountTransactionsParams) ([]model.TransactionSummary, error) {
+	dbParams := db.SummarizeTransactionsParams{
+		HouseholdID: params.HouseholdID,
+		Column2:     toPgTimestamptz(params.From),
+		Column3:     toPgTimestamptz(params.To),
+		Column7:     toNullUUID(params.CreatedBy),
+	}
+	if params.Type != nil {
+		dbParams.Column4 = pgtype.Text{String: string(*params.Type), Valid: true}
+	}
+	if len(params.AccountIDs) > 0 {
+		dbParams.Column5 = params.AccountIDs
+	}
+	if params.Flagged != nil {
+		dbParams.Column6 = pgtype.Bool{Bool: *params.Flagged, Valid: true}
+	}
+	if params.Posted != nil {
+		dbParams.Column8 = pgtype.Bool{Bool: *params.Posted, Valid: true}
+	}
+	rows, err := r.queries.SummarizeTransactions(ctx, dbParams)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.TransactionSummary, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, model.TransactionSummary{
+			Currency:     row.Currency,
+			IncomeTotal:  row.IncomeTotal,
+			ExpenseTotal: row.ExpenseTotal,
+			Net:          row.Net,
+			Count:        row.Count,
+		})
+	}
+	return out, nil
+}
+
+func (r *transactionRepo) SumByMember(ctx context.Context, householdID uuid.UUID, from, to *time.Time) ([]model.MemberContribution, error) {
+	rows, err := r.queries.SumTransactionsByMember(ctx, db.SumTransactionsByMemberParams{
+		HouseholdID: householdID,
+		Column2:     toPgTimestamptz(from),
+		Column3:     toPgTimestamptz(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.MemberContribution, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, model.MemberContribution{
+			UserID:       row.UserID,
+			Name:         row.UserName,
+			Email:        row.UserEmail,
+			AccountID:    row.AccountID,
+			AccountName:  row.AccountName,
+			Currency:     row.Currency,
+			IncomeTotal:  row.IncomeTotal,
+			ExpenseTotal: row.ExpenseTotal,
+			Count:        row.Count,
+		})
+	}
+	return out, nil
+}
+
+func (r *transactionRepo) SumByDay(ctx context.Context, params repository.SumByDayParams) ([]model.DailyTotal, error) {
+	rows, err := r.queries.SumTransactionsByDay(ctx, db.SumTransactionsByDayParams{
+		HouseholdID: params.HouseholdID,
+		Timezone:    params.Timezone,
+		Currency:    params.Currency,
+		From:        params.From,
+		To:          params.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.DailyTotal, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, model.DailyTotal{
+			Date:    row.Day.Format(time.DateOnly),
+			Income:  row.IncomeTotal,
+			Expense: row.ExpenseTotal,
+		})
+	}
+	return out, nil
+}
+
+func (r *transactionRepo) ListTags(ctx context.Context, householdID uuid.UUID, prefix string) ([]string, error) {
+	params := db.ListDistinctTagsParams{HouseholdID: householdID}
+	if prefix != "" {
+		params.Column2 = pgtype.Text{String: prefix, Valid: true}
+	}
+	return r.queries.ListDistinctTags(ctx, params)
+}
+
+func (r *transactionRepo) RenameTag(ctx context.Context, householdID uuid.UUID, from, to string) (int64, error) {
+	return r.queries.RenameTag(ctx, db.RenameTagParams{HouseholdID: householdID, From: from, To: to})
+}
+
+func (r *transactionRepo) AccountNetEffect(ctx context.Context, householdID, accountID uuid.UUID) (decimal.Decimal, error) {
+	return r.queries.AccountNetEffect(ctx, db.AccountTransactionsParams{HouseholdID: householdID, AccountID: accountID})
+}
+
+func (r *transactionRepo) Search(ctx context.Context, householdID uuid.UUID, query string, limit int32) ([]model.Transaction, error) {
+	rows, err := r.queries.SearchTransactions(ctx, db.SearchTransactionsParams{HouseholdID: householdID, Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.Transaction, 0, len(rows))
+	for _, t := range rows {
+		out = append(out, toTransactionModel(t))
+	}
+	return out, nil
+}
+
+func (r *transactionRepo) SumOutflow(ctx context.Context, householdID, accountID uuid.UUID, from, to time.Time) (decimal.Decimal, error) {
+	return r.queries.SumAccountOutflow(ctx, db.SumAccountOutflowParams{
+		HouseholdID: householdID,
+		AccountID:   accountID,
+		From:        pgtype.Timestamptz{Time: from, Valid: true},
+		To:          pgtype.Timestamptz{Time: to, Valid: true},
+	})
+}
+
+func (r *transactionRepo) CountTransfersBetween(ctx context.Context, householdID, a, b uuid.UUID) (int64, error) {
+	return r.queries.CountTransfersBetween(ctx, db.ReassignTransactionsParams{HouseholdID: householdID, FromAccount: a, ToAccount: b})
+}
+
+func (r *transactionRepo) Reassign(ctx context.Context, householdID, from, to uuid.UUID) (int64, error) {
+	return r.queries.ReassignTransactions(ctx, db.ReassignTransactionsParams{HouseholdID: householdID, FromAccount: from, ToAccount: to})
+}
+
 func (r *transactionRepo) Update(ctx context.Context, params repository.UpdateTransactionParams) (model.Transaction, error) {
 	dbParams := db.UpdateTransactionParams{
 		ID:          params.ID,
@@ -101,7 +246,10 @@ func (r *transactionRepo) Update(ctx context.Context, params repository.UpdateTr
 			Time:  params.TransactedAt,
 			Valid: true,
 		},
-		Type: db.TransactionType(params.Type),
+		Type:              db.TransactionType(params.Type),
+		ExpectedUpdatedAt: toPgTimestamptz(params.ExpectedUpdatedAt),
+		Posted:            params.Posted,
+		WasPosted:         params.WasPosted,
 	}
 	if params.DestinationAccountID != nil {
 		dbParams.DestinationAccountID = toNullUUID(params.DestinationAccountID)
@@ -113,6 +261,19 @@ func (r *transactionRepo) Update(ctx context.Context, params repository.UpdateTr
 	return toTransactionModel(t), nil
 }
 
+func (r *transactionRepo) SetFlag(ctx context.Context, id, householdID uuid.UUID, flagged bool, reason *string) (model.Transaction, error) {
+	t, err := r.queries.SetTransactionFlag(ctx, db.SetTransactionFlagParams{
+		ID:          id,
+		HouseholdID: householdID,
+		Flagged:     flagged,
+		FlagReason:  toPgText(reason),
+	})
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return toTransactionModel(t), nil
+}
+
 func (r *transactionRepo) Delete(ctx context.Context, id, householdID uuid.UUID) (model.Transaction, error) {
 	t, err := r.queries.DeleteTransaction(ctx, db.DeleteTransactionParams{ID: id, HouseholdID: householdID})
 	if err != nil {
@@ -121,7 +282,43 @@ func (r *transactionRepo) Delete(ctx context.Context, id, householdID uuid.UUID)
 	return toTransactionModel(t), nil
 }
 
-func (r *transactionRepo) ListForExport(ctx context.Context, householdID uuid.UUID, from, to *time.Time) ([]repository.ExportRow, error) {
+func (r *transactionRepo) Restore(ctx context.Context, txn model.Transaction) (model.Transaction, error) {
+	t, err := r.queries.RestoreTransaction(ctx, db.RestoreTransactionParams{
+		ID:                   txn.ID,
+		HouseholdID:          txn.HouseholdID,
+		Type:                 db.TransactionType(txn.Type),
+		Description:          txn.Description,
+		Amount:               txn.Amount,
+		AccountID:            txn.AccountID,
+		DestinationAccountID: toNullUUID(txn.DestinationAccountID),
+		Tags:                 txn.Tags,
+		Note:                 toPgText(txn.Note),
+		TransactedAt:         pgtype.Timestamptz{Time: txn.TransactedAt, Valid: true},
+		CreatedBy:            txn.CreatedBy,
+		CreatedAt:            pgtype.Timestamptz{Time: txn.CreatedAt, Valid: true},
+		Flagged:              txn.Flagged,
+		FlagReason:           toPgText(txn.FlagReason),
+		Posted:               txn.Posted,
+	})
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return toTransactionModel(t), nil
+}
+
+func (r *transactionRepo) ListDue(ctx context.Context, now time.Time, limit int32) ([]uuid.UUID, error) {
+	return r.queries.ListDueTransactions(ctx, pgtype.Timestamptz{Time: now, Valid: true}, limit)
+}
+
+func (r *transactionRepo) Post(ctx context.Context, id uuid.UUID, now time.Time) (model.Transaction, error) {
+	t, err := r.queries.PostTransaction(ctx, db.PostTransactionParams{ID: id, Now: pgtype.Timestamptz{Time: now, Valid: true}})
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return toTransactionModel(t), nil
+}
+
+func (r *transactionRepo) StreamForExport(ctx context.Context, householdID uuid.UUID, from, to *time.Time, fn func(repository.ExportRow) error) error {
 	params := db.ListTransactionsForExportParams{
 		HouseholdID: householdID,
 	}
@@ -131,32 +328,65 @@ func (r *transactionRepo) ListForExport(ctx context.Context, householdID uuid.UU
 	if to != nil {
 		params.Column3 = pgtype.Timestamptz{Time: *to, Valid: true}
 	}
-	rows, err := r.queries.ListTransactionsForExport(ctx, params)
-	if err != nil {
-		return nil, err
-	}
-	out := make([]repository.ExportRow, 0, len(rows))
-	for _, row := range rows {
+	return r.queries.ListTransactionsForExport(ctx, params, func(row db.ListTransactionsForExportRow) error {
 		er := repository.ExportRow{
+			ID:                     row.ID,
 			TransactedAt:           row.TransactedAt.Time,
 			Description:            row.Description,
 			Amount:                 row.Amount,
 			Type:                   model.TransactionType(row.Type),
 			Tags:                   row.Tags,
+			AccountID:              row.AccountID,
 			AccountName:            row.AccountName,
 			AccountCurrency:        row.AccountCurrency,
+			DestinationAccountID:   nullUUIDToPtr(row.DestinationAccountID),
 			DestinationAccountName: row.DestinationAccountName,
 		}
 		if row.Note.Valid {
 			er.Note = &row.Note.String
 		}
-		out = append(out, er)
+		return fn(er)
+	})
+}
+
+func (r *transactionRepo) CreateSplit(ctx context.Context, transactionID uuid.UUID, params repository.CreateSplitParams) (model.TransactionSplit, error) {
+	sp, err := r.queries.CreateTransactionSplit(ctx, db.CreateTransactionSplitParams{
+		TransactionID: transactionID,
+		CategoryID:    params.CategoryID,
+		Amount:        params.Amount,
+	})
+	if err != nil {
+		return model.TransactionSplit{}, err
+	}
+	return toTransactionSplitModel(sp), nil
+}
+
+func (r *transactionRepo) ListSplits(ctx context.Context, transactionID uuid.UUID) ([]model.TransactionSplit, error) {
+	rows, err := r.queries.ListTransactionSplits(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.TransactionSplit, 0, len(rows))
+	for _, sp := range rows {
+		out = append(out, toTransactionSplitModel(sp))
 	}
 	return out, nil
 }
 
+func (r *transactionRepo) DeleteSplits(ctx context.Context, transactionID uuid.UUID) error {
+	return r.queries.DeleteTransactionSplits(ctx, transactionID)
+}
+
 // --- pgtype conversion helpers ---
 
+func toTransactionSplitModel(sp db.TransactionSplit) model.TransactionSplit {
+	return model.TransactionSplit{
+		ID:         sp.ID,
+		CategoryID: sp.CategoryID,
+		Amount:     sp.Amount,
+	}
+}
+
 func toTransactionModel(t db.Transaction) model.Transaction {
 	txn := model.Transaction{
 		ID:           t.ID,
@@ -167,6 +397,8 @@ func toTransactionModel(t db.Transaction) model.Transaction {
 		AccountID:    t.AccountID,
 		Tags:         t.Tags,
 		TransactedAt: t.TransactedAt.Time,
+		Flagged:      t.Flagged,
+		Posted:       t.Posted,
 		CreatedBy:    t.CreatedBy,
 		CreatedAt:    t.CreatedAt.Time,
 		UpdatedAt:    t.UpdatedAt.Time,
@@ -174,6 +406,9 @@ func toTransactionModel(t db.Transaction) model.Transaction {
 	if t.Note.Valid {
 		txn.Note = &t.Note.String
 	}
+	if t.FlagReason.Valid {
+		txn.FlagReason = &t.FlagReason.String
+	}
 	txn.DestinationAccountID = nullUUIDToPtr(t.DestinationAccountID)
 	return txn
 }
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+func TestSumByDayGroupsByHouseholdDay(t *testing.T) {
+	withTestRepos(t, func(ctx context.Context, repos *repository.Repos) {
+		user, err := repos.Users.Create(ctx, uuid.NewString()+"@example.com", "x", "Test")
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		hh, err := repos.Households.Create(ctx, repository.CreateHouseholdParams{
+			Name: "Home", Timezone: "Europe/Kyiv", DefaultCurrency: "UAH", OwnerID: user.ID,
+		})
+		if err != nil {
+			t.Fatalf("create household: %v", err)
+		}
+		acc, err := repos.Accounts.Create(ctx, repository.CreateAccountParams{
+			HouseholdID: hh.ID, Name: "Wallet", Type: model.AccountTypeCash, Balance: decimal.Zero,
+			Currency: "UAH", CreatedBy: user.ID, IncludeInTotals: true,
+		})
+		if err != nil {
+			t.Fatalf("create account: %v", err)
+		}
+
+		// Kyiv is UTC+2 in winter, so local midnight on 1 February is 22:00 UTC.
+		for _, at := range []time.Time{
+			time.Date(2024, 1, 31, 21, 59, 0, 0, time.UTC),
+			time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC),
+			time.Date(2024, 2, 1, 21, 0, 0, 0, time.UTC),
+		} {
+			_, err := repos.Transactions.Create(ctx, repository.CreateTransactionParams{
+				HouseholdID: hh.ID, Type: model.TransactionTypeExpense, Description: "Кава",
+				Amount: decimal.NewFromInt(10), AccountID: acc.ID, TransactedAt: at, CreatedBy: user.ID, Posted: true,
+			})
+			if err != nil {
+				t.Fatalf("create transaction at %s: %v", at, err)
+			}
+		}
+
+		loc, err := time.LoadLocation("Europe/Kyiv")
+		if err != nil {
+			t.Fatalf("load timezone: %v", err)
+		}
+		totals, err := repos.Transactions.SumByDay(ctx, repository.SumByDayParams{
+			HouseholdID: hh.ID, Timezone: hh.Timezone, Currency: "UAH",
+			From: time.Date(2024, 1, 31, 0, 0, 0, 0, loc),
+			To:   time.Date(2024, 2, 2, 0, 0, 0, 0, loc),
+		})
+		if err != nil {
+			t.Fatalf("SumByDay: %v", err)
+		}
+		want := map[string]int64{"2024-01-31": 10, "2024-02-01": 20}
+		if len(totals) != len(want) {
+			t.Fatalf("totals = %+v, want %d days", totals, len(want))
+		}
+		for _, d := range totals {
+			if !d.Expense.Equal(decimal.NewFromInt(want[d.Date])) {
+				t.Errorf("%s: expense %s, want %d", d.Date, d.Expense, want[d.Date])
+			}
+		}
+	})
+}
@@ -40,6 +40,18 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (model.User, er
 	return toUserModel(u), nil
 }
 
+func (r *userRepo) Update(ctx context.Context, id uuid.UUID, name, email *string) (model.User, error) {
+	u, err := r.queries.UpdateUser(ctx, db.UpdateUserParams{ID: id, Name: name, Email: email})
+	if err != nil {
+		return model.User{}, err
+	}
+	return toUserModel(u), nil
+}
+
+func (r *userRepo) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	return r.queries.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: id, PasswordHash: passwordHash})
+}
+
 func toUserModel(u db.User) model.User {
 	return model.User{
 		ID:           u.ID,
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// Emails are unique and looked up regardless of case, even for rows written
+// without normalizing.
+func TestUserEmailCaseInsensitive(t *testing.T) {
+	withTestRepos(t, func(ctx context.Context, repos *repository.Repos) {
+		email := "Case." + uuid.NewString() + "@Example.com"
+		user, err := repos.Users.Create(ctx, email, "x", "Test")
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+
+		found, err := repos.Users.GetByEmail(ctx, strings.ToUpper(email))
+		if err != nil || found.ID != user.ID {
+			t.Fatalf("GetByEmail(upper) = %v, %v; want the user", found.ID, err)
+		}
+
+		// Last, since the failure aborts the test's transaction.
+		_, err = repos.Users.Create(ctx, strings.ToLower(email), "x", "Dup")
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+			t.Fatalf("creating a mixed-case duplicate = %v, want a unique violation", err)
+		}
+	})
+}
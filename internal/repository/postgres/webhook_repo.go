@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/howallet/howallet/internal/db"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+type webhookRepo struct {
+	queries *db.Queries
+}
+
+func (r *webhookRepo) Create(ctx context.Context, householdID uuid.UUID, url, secret string, events []model.WebhookEvent) (model.Webhook, error) {
+	wh, err := r.queries.CreateWebhook(ctx, db.CreateWebhookParams{
+		HouseholdID: householdID,
+		Url:         url,
+		Secret:      secret,
+		Events:      fromWebhookEvents(events),
+	})
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return toWebhookModel(wh), nil
+}
+
+func (r *webhookRepo) GetByID(ctx context.Context, id, householdID uuid.UUID) (model.Webhook, error) {
+	wh, err := r.queries.GetWebhook(ctx, db.GetWebhookParams{ID: id, HouseholdID: householdID})
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return toWebhookModel(wh), nil
+}
+
+func (r *webhookRepo) ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Webhook, error) {
+	rows, err := r.queries.ListWebhooks(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookModels(rows), nil
+}
+
+func (r *webhookRepo) ListForEvent(ctx context.Context, householdID uuid.UUID, event model.WebhookEvent) ([]model.Webhook, error) {
+	rows, err := r.queries.ListWebhooksForEvent(ctx, db.ListWebhooksForEventParams{
+		HouseholdID: householdID,
+		Event:       string(event),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookModels(rows), nil
+}
+
+func (r *webhookRepo) Update(ctx context.Context, id, householdID uuid.UUID, url *string, events []model.WebhookEvent) (model.Webhook, error) {
+	wh, err := r.queries.UpdateWebhook(ctx, db.UpdateWebhookParams{
+		ID:          id,
+		HouseholdID: householdID,
+		Url:         toPgText(url),
+		Events:      fromWebhookEvents(events),
+	})
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return toWebhookModel(wh), nil
+}
+
+func (r *webhookRepo) Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error) {
+	n, err := r.queries.DeleteWebhook(ctx, db.DeleteWebhookParams{ID: id, HouseholdID: householdID})
+	return n > 0, err
+}
+
+func (r *webhookRepo) CreateDelivery(ctx context.Context, params repository.CreateWebhookDeliveryParams) error {
+	arg := db.CreateWebhookDeliveryParams{
+		WebhookID: params.WebhookID,
+		Event:     string(params.Event),
+		Payload:   params.Payload,
+		Attempt:   params.Attempt,
+		Error:     toPgText(params.Error),
+	}
+	if params.StatusCode != nil {
+		arg.StatusCode = pgtype.Int4{Int32: *params.StatusCode, Valid: true}
+	}
+	return r.queries.CreateWebhookDelivery(ctx, arg)
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int32) ([]model.WebhookDelivery, error) {
+	rows, err := r.queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{WebhookID: webhookID, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.WebhookDelivery, 0, len(rows))
+	for _, d := range rows {
+		m := model.WebhookDelivery{
+			ID:        d.ID,
+			WebhookID: d.WebhookID,
+			Event:     model.WebhookEvent(d.Event),
+			Payload:   d.Payload,
+			Attempt:   d.Attempt,
+			CreatedAt: d.CreatedAt.Time,
+		}
+		if d.StatusCode.Valid {
+			m.StatusCode = &d.StatusCode.Int32
+		}
+		if d.Error.Valid {
+			m.Error = &d.Error.String
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// fromWebhookEvents returns nil for nil so COALESCE keeps the stored events.
+func fromWebhookEvents(events []model.WebhookEvent) []string {
+	if events == nil {
+		return nil
+	}
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = string(e)
+	}
+	return out
+}
+
+func toWebhookModels(rows []db.Webhook) []model.Webhook {
+	out := make([]model.Webhook, 0, len(rows))
+	for _, wh := range rows {
+		out = append(out, toWebhookModel(wh))
+	}
+	return out
+}
+
+func toWebhookModel(wh db.Webhook) model.Webhook {
+	events := make([]model.WebhookEvent, len(wh.Events))
+	for i, e := range wh.Events {
+		events[i] = model.WebhookEvent(e)
+	}
+	return model.Webhook{
+		ID:          wh.ID,
+		HouseholdID: wh.HouseholdID,
+		URL:         wh.Url,
+		Secret:      wh.Secret,
+		Events:      events,
+		CreatedAt:   wh.CreatedAt.Time,
+	}
+}
@@ -9,18 +9,50 @@ import (
 
 // RefreshTokenRepository defines data access for refresh tokens.
 type RefreshTokenRepository interface {
-	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// Create stores a token and returns its ID.
+	Create(ctx context.Context, params CreateRefreshTokenParams) (uuid.UUID, error)
 	GetByHash(ctx context.Context, tokenHash string) (RefreshTokenRow, error)
+	// ListByUser returns the user's unexpired, unrotated tokens, most recently used first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]RefreshTokenRow, error)
+	// MarkUsed records that a token was rotated into replacedBy; it reports
+	// false if the token had already been used.
+	MarkUsed(ctx context.Context, id, replacedBy uuid.UUID) (bool, error)
+	// Delete removes the token with the given hash and the rest of its family.
 	Delete(ctx context.Context, tokenHash string) error
+	DeleteFamily(ctx context.Context, familyID uuid.UUID) error
+	// DeleteByID removes one of the user's tokens and the rest of its family;
+	// it reports false if none matched.
+	DeleteByID(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	// DeleteByHash removes one of the user's tokens by hash and the rest of its
+	// family; it reports false if none matched.
+	DeleteByHash(ctx context.Context, tokenHash string, userID uuid.UUID) (bool, error)
 	DeleteByUser(ctx context.Context, userID uuid.UUID) error
-	DeleteExpired(ctx context.Context) error
+	DeleteExpired(ctx context.Context) (int64, error)
+	DeleteIdle(ctx context.Context, idleBefore time.Time) (int64, error)
 }
 
-// RefreshTokenRow holds the data returned when querying a refresh token.
-type RefreshTokenRow struct {
-	ID        uuid.UUID
+// CreateRefreshTokenParams holds parameters for storing a refresh token.
+type CreateRefreshTokenParams struct {
 	UserID    uuid.UUID
 	TokenHash string
 	ExpiresAt time.Time
-	CreatedAt time.Time
+	UserAgent string
+	IPAddress string
+	// FamilyID groups the tokens rotated from a single login.
+	FamilyID uuid.UUID
+}
+
+// RefreshTokenRow holds the data returned when querying a refresh token.
+type RefreshTokenRow struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	UserAgent  string
+	IPAddress  string
+	FamilyID   uuid.UUID
+	UsedAt     *time.Time
+	ReplacedBy *uuid.UUID
 }
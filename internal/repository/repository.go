@@ -1,6 +1,20 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrCommitFailed is returned by RunInTx when fn succeeded but the database
+	// refused the commit. The transaction was rolled back, so none of fn's
+	// writes persist.
+	ErrCommitFailed = errors.New("commit failed")
+	// ErrCommitUnknown is returned by RunInTx when the commit got no answer, e.g.
+	// because the connection dropped. The database may have committed before
+	// the failure, so fn's writes may or may not persist.
+	ErrCommitUnknown = errors.New("commit outcome unknown")
+)
 
 // TxFunc is a function executed within a transaction.
 type TxFunc func(ctx context.Context) error
@@ -9,5 +23,43 @@ type TxFunc func(ctx context.Context) error
 type UnitOfWork interface {
 	// RunInTx executes fn inside a database transaction.
 	// If fn returns an error the transaction is rolled back, otherwise committed.
+	// A commit the database refused is reported as ErrCommitFailed, one whose
+	// outcome is unknown as ErrCommitUnknown.
 	RunInTx(ctx context.Context, fn TxFunc) error
 }
+
+// Repos groups the repositories services work with and the UnitOfWork that runs
+// transactions over them. Inside RunInTx, TxReposFromCtx returns the same set
+// bound to the transaction. Tests can fill it with mocks.
+type Repos struct {
+	UnitOfWork
+
+	Users         UserRepository
+	Accounts      AccountRepository
+	AccountTypes  AccountTypeRepository
+	Transactions  TransactionRepository
+	Households    HouseholdRepository
+	Invitations   InvitationRepository
+	RefreshTokens RefreshTokenRepository
+	Audit         AuditRepository
+	Categories    CategoryRepository
+	Webhooks      WebhookRepository
+	Templates     TemplateRepository
+	Notifications NotificationRepository
+	Budgets       BudgetRepository
+}
+
+type txReposKey struct{}
+
+// WithTxRepos stores transactional repos in context.
+func WithTxRepos(ctx context.Context, repos *Repos) context.Context {
+	return context.WithValue(ctx, txReposKey{}, repos)
+}
+
+// TxReposFromCtx returns the transactional repos from context, or nil.
+func TxReposFromCtx(ctx context.Context) *Repos {
+	if v, ok := ctx.Value(txReposKey{}).(*Repos); ok {
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// TemplateRepository defines data access for transaction templates.
+type TemplateRepository interface {
+	Create(ctx context.Context, params TemplateParams) (model.TransactionTemplate, error)
+	GetByID(ctx context.Context, id, householdID uuid.UUID) (model.TransactionTemplate, error)
+	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.TransactionTemplate, error)
+	// Update replaces the template identified by params.ID.
+	Update(ctx context.Context, params TemplateParams) (model.TransactionTemplate, error)
+	Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error)
+}
+
+// TemplateParams holds the fields of a template; ID is ignored by Create.
+type TemplateParams struct {
+	ID                   uuid.UUID
+	HouseholdID          uuid.UUID
+	Name                 string
+	Type                 model.TransactionType
+	Description          string
+	Amount               *decimal.Decimal
+	AccountID            *uuid.UUID
+	DestinationAccountID *uuid.UUID
+	Tags                 []string
+	Note                 *string
+}
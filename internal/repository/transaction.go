@@ -15,9 +15,58 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id, householdID uuid.UUID) (model.Transaction, error)
 	List(ctx context.Context, params ListTransactionsParams) ([]model.Transaction, error)
 	Count(ctx context.Context, params CountTransactionsParams) (int64, error)
+	// Summarize totals the transactions matching the filters, per currency.
+	Summarize(ctx context.Context, params CountTransactionsParams) ([]model.TransactionSummary, error)
+	// SumByMember totals income and expense per creator and account, leaving
+	// out transfers. Nil bounds are open.
+	SumByMember(ctx context.Context, householdID uuid.UUID, from, to *time.Time) ([]model.MemberContribution, error)
+	// SumByDay totals income and expense per local calendar day, leaving out
+	// transfers. Days without transactions are absent.
+	SumByDay(ctx context.Context, params SumByDayParams) ([]model.DailyTotal, error)
+	// Search returns up to limit of the newest transactions whose description,
+	// note or a tag contains query, ignoring case.
+	Search(ctx context.Context, householdID uuid.UUID, query string, limit int32) ([]model.Transaction, error)
+	// ListTags returns the household's distinct tags, sorted; an empty prefix matches all.
+	ListTags(ctx context.Context, householdID uuid.UUID, prefix string) ([]string, error)
+	// RenameTag replaces (or merges) a tag across the household and returns the rows changed.
+	RenameTag(ctx context.Context, householdID uuid.UUID, from, to string) (int64, error)
+	// AccountNetEffect returns the sum of the transactions' effects on an account's balance.
+	AccountNetEffect(ctx context.Context, householdID, accountID uuid.UUID) (decimal.Decimal, error)
+	// SumOutflow totals the expenses and outgoing transfers from an account
+	// transacted in [from, to).
+	SumOutflow(ctx context.Context, householdID, accountID uuid.UUID, from, to time.Time) (decimal.Decimal, error)
+	// CountTransfersBetween counts transfers in either direction between two accounts.
+	CountTransfersBetween(ctx context.Context, householdID, a, b uuid.UUID) (int64, error)
+	// Reassign points every transaction referencing from (either side) at to and
+	// returns the rows changed. It does not touch balances.
+	Reassign(ctx context.Context, householdID, from, to uuid.UUID) (int64, error)
 	Update(ctx context.Context, params UpdateTransactionParams) (model.Transaction, error)
+	SetFlag(ctx context.Context, id, householdID uuid.UUID, flagged bool, reason *string) (model.Transaction, error)
 	Delete(ctx context.Context, id, householdID uuid.UUID) (model.Transaction, error)
-	ListForExport(ctx context.Context, householdID uuid.UUID, from, to *time.Time) ([]ExportRow, error)
+	// Restore re-inserts a deleted transaction, keeping its original ID and authorship.
+	Restore(ctx context.Context, txn model.Transaction) (model.Transaction, error)
+	// ListDue returns up to limit scheduled transactions dated at or before now,
+	// oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int32) ([]uuid.UUID, error)
+	// Post marks a due scheduled transaction posted. It returns pgx.ErrNoRows if
+	// the transaction is no longer due: posted, deleted or rescheduled.
+	Post(ctx context.Context, id uuid.UUID, now time.Time) (model.Transaction, error)
+	// StreamForExport calls fn for each export row, newest first, without loading them all.
+	StreamForExport(ctx context.Context, householdID uuid.UUID, from, to *time.Time, fn func(ExportRow) error) error
+
+	CreateSplit(ctx context.Context, transactionID uuid.UUID, params CreateSplitParams) (model.TransactionSplit, error)
+	ListSplits(ctx context.Context, transactionID uuid.UUID) ([]model.TransactionSplit, error)
+	DeleteSplits(ctx context.Context, transactionID uuid.UUID) error
+}
+
+// SumByDayParams selects the transactions for a daily report: those on
+// accounts in Currency, transacted in [From, To). Days are cut in Timezone.
+type SumByDayParams struct {
+	HouseholdID uuid.UUID
+	Timezone    string
+	Currency    string
+	From        time.Time
+	To          time.Time
 }
 
 // CreateTransactionParams holds parameters for creating a transaction.
@@ -32,6 +81,17 @@ type CreateTransactionParams struct {
 	Note                 *string
 	TransactedAt         time.Time
 	CreatedBy            uuid.UUID
+	// Posted is false for a scheduled transaction, which leaves balances alone
+	// until its date.
+	Posted bool
+	// Splits are not written by Create; the service stores them with CreateSplit.
+	Splits []CreateSplitParams
+}
+
+// CreateSplitParams holds one category share of a transaction.
+type CreateSplitParams struct {
+	CategoryID uuid.UUID
+	Amount     decimal.Decimal
 }
 
 // ListTransactionsParams holds parameters for listing transactions.
@@ -40,7 +100,10 @@ type ListTransactionsParams struct {
 	From        *time.Time
 	To          *time.Time
 	Type        *model.TransactionType
-	AccountID   *uuid.UUID
+	AccountIDs  []uuid.UUID
+	Flagged     *bool
+	CreatedBy   *uuid.UUID
+	Posted      *bool
 	Limit       int32
 	Offset      int32
 }
@@ -51,7 +114,10 @@ type CountTransactionsParams struct {
 	From        *time.Time
 	To          *time.Time
 	Type        *model.TransactionType
-	AccountID   *uuid.UUID
+	AccountIDs  []uuid.UUID
+	Flagged     *bool
+	CreatedBy   *uuid.UUID
+	Posted      *bool
 }
 
 // UpdateTransactionParams holds parameters for updating a transaction.
@@ -66,17 +132,27 @@ type UpdateTransactionParams struct {
 	Tags                 []string
 	Note                 *string
 	TransactedAt         time.Time
+	// ExpectedUpdatedAt, if set, turns the update into a no-op (pgx.ErrNoRows)
+	// when the transaction was modified after that time.
+	ExpectedUpdatedAt *time.Time
+	Posted            bool
+	// WasPosted is the posted flag the caller read; the update is a no-op
+	// (pgx.ErrNoRows) if the poster changed it since.
+	WasPosted bool
 }
 
-// ExportRow represents a transaction row for CSV export.
+// ExportRow represents a transaction row for export.
 type ExportRow struct {
+	ID                     uuid.UUID
 	TransactedAt           time.Time
 	Description            string
 	Amount                 decimal.Decimal
 	Type                   model.TransactionType
 	Tags                   []string
 	Note                   *string
+	AccountID              uuid.UUID
 	AccountName            string
 	AccountCurrency        string
+	DestinationAccountID   *uuid.UUID
 	DestinationAccountName *string
 }
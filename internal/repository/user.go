@@ -12,4 +12,7 @@ type UserRepository interface {
 	Create(ctx context.Context, email, passwordHash, name string) (model.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (model.User, error)
 	GetByEmail(ctx context.Context, email string) (model.User, error)
+	// Update changes the non-nil fields.
+	Update(ctx context.Context, id uuid.UUID, name, email *string) (model.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
 }
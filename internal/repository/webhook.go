@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/howallet/howallet/internal/model"
+)
+
+// WebhookRepository defines data access for webhooks and their delivery log.
+type WebhookRepository interface {
+	Create(ctx context.Context, householdID uuid.UUID, url, secret string, events []model.WebhookEvent) (model.Webhook, error)
+	GetByID(ctx context.Context, id, householdID uuid.UUID) (model.Webhook, error)
+	ListByHousehold(ctx context.Context, householdID uuid.UUID) ([]model.Webhook, error)
+	// ListForEvent returns the household's webhooks subscribed to event, secrets included.
+	ListForEvent(ctx context.Context, householdID uuid.UUID, event model.WebhookEvent) ([]model.Webhook, error)
+	// Update changes the URL and/or events; nil leaves a field unchanged.
+	Update(ctx context.Context, id, householdID uuid.UUID, url *string, events []model.WebhookEvent) (model.Webhook, error)
+	Delete(ctx context.Context, id, householdID uuid.UUID) (bool, error)
+
+	CreateDelivery(ctx context.Context, params CreateWebhookDeliveryParams) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int32) ([]model.WebhookDelivery, error)
+}
+
+// CreateWebhookDeliveryParams records one delivery attempt. StatusCode is nil when
+// no response was received.
+type CreateWebhookDeliveryParams struct {
+	WebhookID  uuid.UUID
+	Event      model.WebhookEvent
+	Payload    []byte
+	Attempt    int32
+	StatusCode *int32
+	Error      *string
+}
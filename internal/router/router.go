@@ -1,8 +1,10 @@
 package router
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
@@ -13,6 +15,9 @@ import (
 	mw "github.com/howallet/howallet/internal/middleware"
 )
 
+// notReadyRetryAfter is the Retry-After sent while the readiness check fails.
+const notReadyRetryAfter = 5 * time.Second
+
 // New creates and configures the chi router with all routes.
 func New(
 	cfg *config.Config,
@@ -21,29 +26,62 @@ func New(
 	hhH *handler.HouseholdHandler,
 	accH *handler.AccountHandler,
 	txnH *handler.TransactionHandler,
+	catH *handler.CategoryHandler,
 	expH *handler.ExportHandler,
+	auditH *handler.AuditHandler,
+	onboardingH *handler.OnboardingHandler,
+	metaH *handler.MetaHandler,
+	webhookH *handler.WebhookHandler,
+	templateH *handler.TemplateHandler,
+	reportH *handler.ReportHandler,
+	notificationH *handler.NotificationHandler,
+	budgetH *handler.BudgetHandler,
+	searchH *handler.SearchHandler,
 	checkMembership mw.MembershipChecker,
+	ready func(context.Context) error,
+	maintenance *mw.Maintenance,
+	metrics *mw.Metrics,
 ) http.Handler {
 	r := chi.NewRouter()
 
 	// Global middleware
 	r.Use(chimw.RequestID)
+	r.Use(mw.RequestIDResponse)
 	r.Use(chimw.RealIP)
+	if cfg.Tracing.Enabled() {
+		r.Use(mw.Tracing)
+	}
+	if metrics != nil {
+		r.Use(metrics.Handler)
+	}
 	r.Use(mw.Logger(logger))
 	r.Use(chimw.Recoverer)
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{cfg.Frontend.URL},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Household-ID"},
-		ExposedHeaders:   []string{"Content-Disposition"},
+		AllowedOrigins:   cfg.Frontend.URLs,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-None-Match", "X-Household-ID", mw.RequestIDHeader},
+		ExposedHeaders:   []string{"Content-Disposition", "ETag", "Location", mw.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	r.Use(maintenance.Handler)
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		handler.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	})
+	// Readiness: the database is reachable and migrated
+	r.Get("/health/ready", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(r.Context()); err != nil {
+			logger.Warn("not ready", slog.String("error", err.Error()))
+			mw.RetryAfterJSON(w, http.StatusServiceUnavailable, "not_ready", "service is not ready", notReadyRetryAfter)
+			return
+		}
+		handler.JSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	})
+
+	// Server capabilities (public, so clients can adapt before login)
+	r.Get("/api/meta", metaH.Get)
 
 	// Public auth routes
 	r.Route("/auth", func(r chi.Router) {
@@ -56,8 +94,14 @@ func New(
 	r.Group(func(r chi.Router) {
 		r.Use(mw.JWTAuth(&cfg.JWT))
 
-		// Auth (logout needs JWT)
+		// Auth (needs JWT)
+		r.Get("/auth/me", authH.Me)
+		r.Patch("/auth/me", authH.UpdateMe)
+		r.Post("/auth/password", authH.ChangePassword)
 		r.Post("/auth/logout", authH.Logout)
+		r.Post("/auth/logout-all", authH.LogoutAll)
+		r.Get("/auth/sessions", authH.ListSessions)
+		r.Delete("/auth/sessions/{id}", authH.RevokeSession)
 
 		// Households (no X-Household-ID needed)
 		r.Route("/api/households", func(r chi.Router) {
@@ -65,13 +109,30 @@ func New(
 			r.Get("/", hhH.List)
 
 			r.Route("/{id}", func(r chi.Router) {
+				r.Use(mw.HouseholdParamCtx(checkMembership, "id"))
+
+				r.Get("/", hhH.Get)
+				r.Patch("/", hhH.Update)
+				r.Get("/settings", hhH.GetSettings)
+				r.Patch("/settings", hhH.UpdateSettings)
 				r.Get("/members", hhH.ListMembers)
 				r.Get("/invitations", hhH.ListPendingInvitations)
+				r.Delete("/invitations/{invitationId}", hhH.RevokeInvitation)
 				r.Post("/invite", hhH.Invite)
+				r.Post("/accounts/import", accH.Import)
 				r.Delete("/members/{userId}", hhH.RemoveMember)
+				r.Post("/undo", auditH.Undo)
 			})
 		})
 
+		// Notifications (across all of the caller's households)
+		r.Route("/api/notifications", func(r chi.Router) {
+			r.Get("/", notificationH.List)
+			r.Get("/preferences", notificationH.Preferences)
+			r.Put("/preferences", notificationH.UpdatePreferences)
+			r.Post("/{id}/read", notificationH.MarkRead)
+		})
+
 		// Accept invitation
 		r.Post("/api/invitations/{token}/accept", hhH.AcceptInvitation)
 
@@ -83,24 +144,110 @@ func New(
 			r.Route("/api/accounts", func(r chi.Router) {
 				r.Post("/", accH.Create)
 				r.Get("/", accH.List)
+				r.Get("/summary", accH.Summary)
+				r.Get("/net-worth", accH.NetWorth)
+				r.Put("/reorder", accH.Reorder)
 				r.Get("/{id}", accH.Get)
+				r.Get("/{id}/balance", accH.BalanceAsOf)
 				r.Put("/{id}", accH.Update)
 				r.Delete("/{id}", accH.Delete)
+				r.Post("/{id}/reassign-transactions", accH.ReassignTransactions)
+			})
+
+			// Account types
+			r.Route("/api/account-types", func(r chi.Router) {
+				r.Get("/", accH.ListTypes)
+				r.Post("/", accH.CreateType)
+				r.Delete("/{name}", accH.DeleteType)
 			})
 
 			// Transactions
 			r.Route("/api/transactions", func(r chi.Router) {
 				r.Post("/", txnH.Create)
 				r.Get("/", txnH.List)
+				r.Get("/summary", txnH.Summary)
+				r.Get("/tags", txnH.Tags)
+				r.Post("/tags/rename", txnH.RenameTag)
+				r.Post("/batch-delete", txnH.BatchDelete)
 				r.Get("/{id}", txnH.Get)
 				r.Put("/{id}", txnH.Update)
+				r.Patch("/{id}", txnH.Patch)
 				r.Delete("/{id}", txnH.Delete)
+				r.Post("/{id}/flag", txnH.Flag)
+				r.Post("/{id}/unflag", txnH.Unflag)
+				r.Post("/{id}/duplicate", txnH.Duplicate)
+			})
+
+			// Categories
+			r.Route("/api/categories", func(r chi.Router) {
+				r.Post("/", catH.Create)
+				r.Get("/", catH.List)
 			})
 
+			// Budgets
+			r.Route("/api/budgets", func(r chi.Router) {
+				r.Post("/", budgetH.Create)
+				r.Get("/", budgetH.List)
+				r.Put("/{id}", budgetH.Update)
+				r.Delete("/{id}", budgetH.Delete)
+			})
+
+			// Transaction templates
+			r.Route("/api/templates", func(r chi.Router) {
+				r.Post("/", templateH.Create)
+				r.Get("/", templateH.List)
+				r.Get("/{id}", templateH.Get)
+				r.Put("/{id}", templateH.Update)
+				r.Delete("/{id}", templateH.Delete)
+				r.Post("/{id}/apply", templateH.Apply)
+			})
+
+			// Reports
+			r.Route("/api/reports", func(r chi.Router) {
+				r.Get("/by-member", reportH.ByMember)
+				r.Get("/daily", reportH.Daily)
+				r.Get("/monthly", reportH.Monthly)
+				r.Get("/budgets", reportH.BudgetStatus)
+			})
+
+			// Search
+			r.Get("/api/search", searchH.Search)
+
 			// Export
+			r.Get("/api/export", expH.Export)
 			r.Get("/api/export/csv", expH.ExportCSV)
+
+			// Onboarding (first account + opening transactions)
+			r.Post("/api/onboarding", onboardingH.Onboard)
+
+			// Webhooks (owner only)
+			r.Route("/api/webhooks", func(r chi.Router) {
+				r.Post("/", webhookH.Create)
+				r.Get("/", webhookH.List)
+				r.Patch("/{id}", webhookH.Update)
+				r.Delete("/{id}", webhookH.Delete)
+				r.Get("/{id}/deliveries", webhookH.Deliveries)
+			})
 		})
 	})
 
+	// JSON errors for unknown routes too. Set last so every subrouter gets them.
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		handler.ErrorJSON(w, http.StatusNotFound, "not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+		for _, method := range routeMethods {
+			if r.Match(chi.NewRouteContext(), method, req.URL.Path) {
+				w.Header().Add("Allow", method)
+			}
+		}
+		handler.ErrorJSON(w, http.StatusMethodNotAllowed, "method not allowed")
+	})
+
 	return r
 }
+
+// routeMethods are the methods checked when listing a path's Allow header.
+var routeMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/howallet/howallet/internal/config"
+	mw "github.com/howallet/howallet/internal/middleware"
+)
+
+// newTestRouter builds the router without handlers, for requests the
+// middleware answers on its own.
+func newTestRouter(cfg *config.Config, ready func(context.Context) error) http.Handler {
+	return New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		ready, mw.NewMaintenance(config.MaintenanceOff, 0), nil)
+}
+
+func TestReadinessNotReady(t *testing.T) {
+	notReady := func(context.Context) error { return errors.New("schema is behind") }
+	h := newTestRouter(&config.Config{}, notReady)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After = %q, want 5", got)
+	}
+	var body mw.RetryAfterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Code != "not_ready" || body.RetryAfterSeconds != 5 {
+		t.Errorf("body = %+v, want code not_ready retrying after 5s", body)
+	}
+	if body.RequestID == "" || body.RequestID != rec.Header().Get(mw.RequestIDHeader) {
+		t.Errorf("request_id = %q, want the %s header %q", body.RequestID, mw.RequestIDHeader, rec.Header().Get(mw.RequestIDHeader))
+	}
+}
+
+func TestMiddlewareErrorsCarryRequestID(t *testing.T) {
+	h := newTestRouter(&config.Config{}, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/accounts", nil)
+	r.Header.Set(mw.RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["error"] == "" || body["request_id"] != "req-42" {
+		t.Errorf("body = %v, want an error with request_id req-42", body)
+	}
+}
+
+func TestCORSRequestIDHeader(t *testing.T) {
+	const origin = "https://app.example.com"
+	cfg := &config.Config{}
+	cfg.Frontend.URLs = []string{origin}
+	h := newTestRouter(cfg, nil)
+
+	r := httptest.NewRequest(http.MethodOptions, "/api/accounts", nil)
+	r.Header.Set("Origin", origin)
+	r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	r.Header.Set("Access-Control-Request-Headers", mw.RequestIDHeader)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.EqualFold(got, mw.RequestIDHeader) {
+		t.Errorf("preflight Access-Control-Allow-Headers = %q, want %s", got, mw.RequestIDHeader)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/health", nil)
+	r.Header.Set("Origin", origin)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(strings.ToLower(got), strings.ToLower(mw.RequestIDHeader)) {
+		t.Errorf("Access-Control-Expose-Headers = %q, want it to include %s", got, mw.RequestIDHeader)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 
 	"github.com/howallet/howallet/internal/model"
@@ -15,74 +19,285 @@ import (
 var (
 	ErrAccountNotFound        = errors.New("account not found")
 	ErrAccountHasTransactions = errors.New("account has transactions, cannot delete")
+	ErrInvalidAccountOrder    = errors.New("account_ids must list every account of the household exactly once")
+	ErrInvalidReassign        = errors.New("target account must be a different account with the same currency")
+	ErrReassignTransfers      = errors.New("accounts have transfers between them, cannot reassign")
+	ErrInvalidDailyLimit      = errors.New("daily_limit must be a positive amount")
+	ErrInvalidBalance         = errors.New("invalid balance")
+	ErrAccountNameExists      = errors.New("an account with this name already exists")
 )
 
 type AccountService struct {
-	accounts repository.AccountRepository
+	repos *repository.Repos
+	// maxAmount bounds opening balances and daily limits.
+	maxAmount decimal.Decimal
 }
 
-func NewAccountService(accounts repository.AccountRepository) *AccountService {
-	return &AccountService{accounts: accounts}
+func NewAccountService(repos *repository.Repos, maxAmount decimal.Decimal) *AccountService {
+	return &AccountService{repos: repos, maxAmount: maxAmount}
 }
 
-func (s *AccountService) Create(ctx context.Context, householdID, userID uuid.UUID, req model.CreateAccountRequest) (*model.Account, error) {
-	balance, err := decimal.NewFromString(req.Balance)
+// Create adds an account. Unless allowDuplicate is set, it fails with
+// ErrAccountNameExists if the household already has an account of the same name,
+// ignoring case.
+func (s *AccountService) Create(ctx context.Context, householdID, userID uuid.UUID, req model.CreateAccountRequest, allowDuplicate bool) (*model.Account, error) {
+	currency, err := accountCurrency(ctx, s.repos.Households, householdID, req.Currency)
 	if err != nil {
-		return nil, fmt.Errorf("invalid balance: %w", err)
+		return nil, err
 	}
-
-	currency := req.Currency
-	if currency == "" {
-		currency = "USD"
+	params, err := newCreateAccountParams(householdID, userID, req, currency, s.maxAmount)
+	if err != nil {
+		return nil, err
 	}
 
-	acc, err := s.accounts.Create(ctx, repository.CreateAccountParams{
-		HouseholdID: householdID,
-		Name:        req.Name,
-		Type:        req.Type,
-		Balance:     balance,
-		Currency:    currency,
-		CreatedBy:   userID,
+	var acc model.Account
+	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		var txErr error
+		if params.Type, txErr = checkAccountType(txCtx, txRepos.AccountTypes, householdID, params.Type); txErr != nil {
+			return txErr
+		}
+		if !allowDuplicate {
+			exists, txErr := txRepos.Accounts.NameExists(txCtx, householdID, params.Name)
+			if txErr != nil {
+				return fmt.Errorf("check account name: %w", txErr)
+			}
+			if exists {
+				return ErrAccountNameExists
+			}
+		}
+
+		acc, txErr = txRepos.Accounts.Create(txCtx, params)
+		if txErr != nil {
+			if mapped := constraintError(txErr, nil, ErrHouseholdNotFound); mapped != nil {
+				return mapped
+			}
+			return fmt.Errorf("create account: %w", txErr)
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("create account: %w", err)
+		return nil, err
 	}
-
 	return &acc, nil
 }
 
+// accountCurrency returns the requested currency, or the household's default
+// currency when none was requested.
+func accountCurrency(ctx context.Context, households repository.HouseholdRepository, householdID uuid.UUID, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+	hh, err := households.GetByID(ctx, householdID)
+	if err != nil {
+		return "", notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	return hh.DefaultCurrency, nil
+}
+
+// newCreateAccountParams validates a create request and fills in defaults;
+// currency is the one resolved by accountCurrency.
+func newCreateAccountParams(householdID, userID uuid.UUID, req model.CreateAccountRequest, currency string, maxAmount decimal.Decimal) (repository.CreateAccountParams, error) {
+	balance, err := parseAmount(req.Balance, maxAmount)
+	if err != nil {
+		return repository.CreateAccountParams{}, fmt.Errorf("%w: %v", ErrInvalidBalance, err)
+	}
+
+	includeInTotals := true
+	if req.IncludeInTotals != nil {
+		includeInTotals = *req.IncludeInTotals
+	}
+	accType := req.Type
+	if accType == "" {
+		accType = model.AccountTypeCard
+	}
+	var dailyLimit *decimal.Decimal
+	if req.DailyLimit != nil {
+		if dailyLimit, err = parseDailyLimit(*req.DailyLimit, maxAmount); err != nil {
+			return repository.CreateAccountParams{}, err
+		}
+	}
+
+	return repository.CreateAccountParams{
+		HouseholdID:     householdID,
+		Name:            req.Name,
+		Type:            accType,
+		Balance:         balance,
+		Currency:        currency,
+		CreatedBy:       userID,
+		IncludeInTotals: includeInTotals,
+		IsLiability:     req.IsLiability,
+		DailyLimit:      dailyLimit,
+	}, nil
+}
+
+// parseDailyLimit parses a daily limit; an empty one means no limit.
+func parseDailyLimit(v string, maxAmount decimal.Decimal) (*decimal.Decimal, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	limit, err := parseAmount(v, maxAmount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDailyLimit, err)
+	}
+	if !limit.IsPositive() {
+		return nil, ErrInvalidDailyLimit
+	}
+	return &limit, nil
+}
+
 func (s *AccountService) List(ctx context.Context, householdID uuid.UUID) ([]model.Account, error) {
-	accounts, err := s.accounts.ListByHousehold(ctx, householdID)
+	accounts, err := s.repos.Accounts.ListByHousehold(ctx, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("list accounts: %w", err)
 	}
 	return accounts, nil
 }
 
+// Summary totals the household's balances per account type and currency. Every
+// type of the household is listed, built-ins first, with no totals when the
+// household has none of it; accounts excluded from totals are skipped.
+func (s *AccountService) Summary(ctx context.Context, householdID uuid.UUID) ([]model.AccountTypeSummary, error) {
+	types, err := s.ListTypes(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repos.Accounts.SumBalancesByType(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("sum balances: %w", err)
+	}
+
+	byType := make(map[model.AccountType]*model.AccountTypeSummary, len(types))
+	out := make([]model.AccountTypeSummary, len(types))
+	for i, t := range types {
+		out[i] = model.AccountTypeSummary{Type: t.Name, Totals: []model.CurrencyBalance{}}
+		byType[t.Name] = &out[i]
+	}
+	for _, row := range rows {
+		summary, ok := byType[row.Type]
+		if !ok {
+			continue
+		}
+		summary.AccountCount += row.Count
+		summary.Totals = append(summary.Totals, model.CurrencyBalance{Currency: row.Currency, Balance: row.Total})
+	}
+	return out, nil
+}
+
+// NetWorth totals the household's balances per currency: assets minus
+// liabilities, skipping accounts excluded from totals.
+func (s *AccountService) NetWorth(ctx context.Context, householdID uuid.UUID) ([]model.CurrencyBalance, error) {
+	rows, err := s.repos.Accounts.SumBalancesByType(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("sum balances: %w", err)
+	}
+
+	out := []model.CurrencyBalance{}
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Currency]
+		if !ok {
+			i = len(out)
+			index[row.Currency] = i
+			out = append(out, model.CurrencyBalance{Currency: row.Currency, Balance: decimal.Zero})
+		}
+		out[i].Balance = out[i].Balance.Add(row.Total)
+	}
+	slices.SortFunc(out, func(a, b model.CurrencyBalance) int { return strings.Compare(a.Currency, b.Currency) })
+	return out, nil
+}
+
 func (s *AccountService) Get(ctx context.Context, id, householdID uuid.UUID) (*model.Account, error) {
-	acc, err := s.accounts.GetByID(ctx, id, householdID)
+	acc, err := s.repos.Accounts.GetByID(ctx, id, householdID)
 	if err != nil {
-		return nil, ErrAccountNotFound
+		return nil, notFoundOr(err, ErrAccountNotFound, "get account")
 	}
 	return &acc, nil
 }
 
-func (s *AccountService) Update(ctx context.Context, id, householdID uuid.UUID, req model.UpdateAccountRequest) (*model.Account, error) {
-	acc, err := s.accounts.Update(ctx, repository.UpdateAccountParams{
-		ID:          id,
-		HouseholdID: householdID,
-		Name:        req.Name,
-		Type:        req.Type,
-		Currency:    req.Currency,
+// BalanceAsOf recomputes an account's balance at the end of the day asOf in the
+// household's timezone, for reconciling against a statement: the opening
+// balance plus every posted transaction up to and including that day.
+func (s *AccountService) BalanceAsOf(ctx context.Context, id, householdID uuid.UUID, asOf time.Time) (*model.AccountBalanceAsOf, error) {
+	acc, err := s.repos.Accounts.GetByID(ctx, id, householdID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrAccountNotFound, "get account")
+	}
+	loc, err := householdLocation(ctx, s.repos.Households, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := time.Date(asOf.Year(), asOf.Month(), asOf.Day()+1, 0, 0, 0, 0, loc)
+	balance, err := s.repos.Accounts.BalanceAsOf(ctx, id, householdID, before)
+	if err != nil {
+		return nil, notFoundOr(err, ErrAccountNotFound, "compute balance")
+	}
+	return &model.AccountBalanceAsOf{
+		AccountBalance: model.AccountBalance{AccountID: acc.ID, Balance: balance, Currency: acc.Currency},
+		AsOf:           asOf.Format(time.DateOnly),
+	}, nil
+}
+
+// Update changes an account's settings and records userID as the last editor.
+func (s *AccountService) Update(ctx context.Context, id, householdID, userID uuid.UUID, req model.UpdateAccountRequest) (*model.Account, error) {
+	var dailyLimit *decimal.Decimal
+	if req.DailyLimit != nil {
+		var err error
+		if dailyLimit, err = parseDailyLimit(*req.DailyLimit, s.maxAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	var acc model.Account
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		accType := req.Type
+		if accType != nil {
+			t, txErr := checkAccountType(txCtx, txRepos.AccountTypes, householdID, *accType)
+			if txErr != nil {
+				return txErr
+			}
+			accType = &t
+		}
+
+		var txErr error
+		acc, txErr = txRepos.Accounts.Update(txCtx, repository.UpdateAccountParams{
+			ID:                id,
+			HouseholdID:       householdID,
+			Name:              req.Name,
+			Type:              accType,
+			Currency:          req.Currency,
+			IncludeInTotals:   req.IncludeInTotals,
+			IsLiability:       req.IsLiability,
+			SetDailyLimit:     req.DailyLimit != nil,
+			DailyLimit:        dailyLimit,
+			UpdatedBy:         userID,
+			ExpectedUpdatedAt: req.ExpectedUpdatedAt,
+		})
+		if errors.Is(txErr, pgx.ErrNoRows) && req.ExpectedUpdatedAt != nil {
+			// Nothing matched: either the account is gone or it changed since the
+			// client read it.
+			if _, getErr := txRepos.Accounts.GetByID(txCtx, id, householdID); getErr == nil {
+				return ErrConflict
+			}
+		}
+		if txErr != nil {
+			return notFoundOr(txErr, ErrAccountNotFound, "update account")
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, ErrAccountNotFound
+		return nil, err
 	}
 	return &acc, nil
 }
 
 func (s *AccountService) Delete(ctx context.Context, id, householdID uuid.UUID) error {
-	count, err := s.accounts.CountTransactions(ctx, id)
+	count, err := s.repos.Accounts.CountTransactions(ctx, id)
 	if err != nil {
 		return fmt.Errorf("count transactions: %w", err)
 	}
@@ -90,5 +305,110 @@ func (s *AccountService) Delete(ctx context.Context, id, householdID uuid.UUID)
 		return ErrAccountHasTransactions
 	}
 
-	return s.accounts.Delete(ctx, id, householdID)
+	return s.repos.Accounts.Delete(ctx, id, householdID)
+}
+
+// Reorder sets the display order of the household's accounts. ids must contain
+// every account of the household exactly once, first to last.
+func (s *AccountService) Reorder(ctx context.Context, householdID uuid.UUID, ids []uuid.UUID) ([]model.Account, error) {
+	var accounts []model.Account
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		current, err := txRepos.Accounts.ListByHousehold(txCtx, householdID)
+		if err != nil {
+			return fmt.Errorf("list accounts: %w", err)
+		}
+		if !sameAccountSet(current, ids) {
+			return ErrInvalidAccountOrder
+		}
+
+		if _, err := txRepos.Accounts.Reorder(txCtx, householdID, ids); err != nil {
+			return fmt.Errorf("reorder accounts: %w", err)
+		}
+
+		accounts, err = txRepos.Accounts.ListByHousehold(txCtx, householdID)
+		if err != nil {
+			return fmt.Errorf("list accounts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// sameAccountSet reports whether ids names each account exactly once.
+func sameAccountSet(accounts []model.Account, ids []uuid.UUID) bool {
+	if len(accounts) != len(ids) {
+		return false
+	}
+	remaining := make(map[uuid.UUID]struct{}, len(accounts))
+	for _, acc := range accounts {
+		remaining[acc.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := remaining[id]; !ok {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// ReassignTransactions moves every transaction of the source account, including
+// transfers into it, to the target account and shifts their balance effect with
+// them. Both accounts must belong to the household and share a currency.
+func (s *AccountService) ReassignTransactions(ctx context.Context, householdID, sourceID, targetID uuid.UUID) (int64, error) {
+	if sourceID == targetID {
+		return 0, ErrInvalidReassign
+	}
+
+	var moved int64
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		source, err := txRepos.Accounts.GetByID(txCtx, sourceID, householdID)
+		if err != nil {
+			return notFoundOr(err, ErrAccountNotFound, "get source account")
+		}
+		target, err := txRepos.Accounts.GetByID(txCtx, targetID, householdID)
+		if err != nil {
+			return notFoundOr(err, ErrAccountNotFound, "get target account")
+		}
+		if source.Currency != target.Currency {
+			return ErrInvalidReassign
+		}
+
+		// A transfer between the two would become a transfer to itself.
+		transfers, err := txRepos.Transactions.CountTransfersBetween(txCtx, householdID, sourceID, targetID)
+		if err != nil {
+			return fmt.Errorf("count transfers: %w", err)
+		}
+		if transfers > 0 {
+			return ErrReassignTransfers
+		}
+
+		net, err := txRepos.Transactions.AccountNetEffect(txCtx, householdID, sourceID)
+		if err != nil {
+			return fmt.Errorf("account net effect: %w", err)
+		}
+		moved, err = txRepos.Transactions.Reassign(txCtx, householdID, sourceID, targetID)
+		if err != nil {
+			return fmt.Errorf("reassign transactions: %w", err)
+		}
+
+		if err := txRepos.Accounts.UpdateBalance(txCtx, sourceID, net.Neg()); err != nil {
+			return fmt.Errorf("update source balance: %w", err)
+		}
+		if err := txRepos.Accounts.UpdateBalance(txCtx, targetID, net); err != nil {
+			return fmt.Errorf("update target balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
 }
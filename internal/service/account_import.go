@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var ErrInvalidAccountImport = errors.New("invalid account import")
+
+// MaxImportAccounts caps the accounts created by one import.
+const MaxImportAccounts = 50
+
+// Import creates several accounts in the household all-or-nothing. Each is
+// validated as by Create, and nothing is written if any is invalid.
+func (s *AccountService) Import(ctx context.Context, householdID, userID uuid.UUID, reqs []model.CreateAccountRequest) ([]model.Account, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: accounts must not be empty", ErrInvalidAccountImport)
+	}
+
+	var accounts []model.Account
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+		hh, txErr := txRepos.Households.GetByID(txCtx, householdID)
+		if txErr != nil {
+			return notFoundOr(txErr, ErrHouseholdNotFound, "get household")
+		}
+		accounts, txErr = importAccounts(txCtx, txRepos, hh, userID, reqs, s.maxAmount)
+		return txErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// importAccounts validates every request, then creates the accounts in order.
+// An account without a currency gets the household's default. repos must be
+// the transactional repos of the surrounding RunInTx.
+func importAccounts(ctx context.Context, repos *repository.Repos, hh model.Household, userID uuid.UUID, reqs []model.CreateAccountRequest, maxAmount decimal.Decimal) ([]model.Account, error) {
+	if len(reqs) > MaxImportAccounts {
+		return nil, fmt.Errorf("%w: at most %d accounts", ErrInvalidAccountImport, MaxImportAccounts)
+	}
+
+	params := make([]repository.CreateAccountParams, 0, len(reqs))
+	for i, req := range reqs {
+		if req.Name == "" {
+			return nil, fmt.Errorf("%w: account %d: name is required", ErrInvalidAccountImport, i)
+		}
+		currency := hh.DefaultCurrency
+		if req.Currency != "" {
+			var err error
+			if currency, err = normalizeCurrency(req.Currency); err != nil {
+				return nil, fmt.Errorf("%w: account %d: %v", ErrInvalidAccountImport, i, err)
+			}
+		}
+		p, err := newCreateAccountParams(hh.ID, userID, req, currency, maxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: account %d: %v", ErrInvalidAccountImport, i, err)
+		}
+		if p.Type, err = checkAccountType(ctx, repos.AccountTypes, hh.ID, p.Type); err != nil {
+			if errors.Is(err, ErrInvalidAccountType) {
+				return nil, fmt.Errorf("%w: account %d: %v", ErrInvalidAccountImport, i, err)
+			}
+			return nil, err
+		}
+		params = append(params, p)
+	}
+
+	accounts := make([]model.Account, 0, len(params))
+	for _, p := range params {
+		acc, err := repos.Accounts.Create(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("create %s account: %w", p.Name, err)
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
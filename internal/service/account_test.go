@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestAccountServiceCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            model.CreateAccountRequest
+		allowDuplicate bool
+		wantErr        error
+		wantCurrency   string
+		wantType       model.AccountType
+	}{
+		{
+			name:         "household default currency and card type",
+			req:          model.CreateAccountRequest{Name: "Wallet", Balance: "10"},
+			wantCurrency: "UAH", wantType: model.AccountTypeCard,
+		},
+		{
+			name:         "explicit currency",
+			req:          model.CreateAccountRequest{Name: "Savings", Balance: "0", Currency: "EUR", Type: model.AccountTypeCash},
+			wantCurrency: "EUR", wantType: model.AccountTypeCash,
+		},
+		{
+			name:         "custom type",
+			req:          model.CreateAccountRequest{Name: "Broker", Balance: "0", Type: "brokerage"},
+			wantCurrency: "UAH", wantType: "brokerage",
+		},
+		{
+			name:         "custom type in another case",
+			req:          model.CreateAccountRequest{Name: "Broker", Balance: "0", Type: " Brokerage"},
+			wantCurrency: "UAH", wantType: "brokerage",
+		},
+		{
+			name:         "built-in type in another case",
+			req:          model.CreateAccountRequest{Name: "Savings", Balance: "0", Type: "CASH"},
+			wantCurrency: "UAH", wantType: model.AccountTypeCash,
+		},
+		{
+			name:    "unknown custom type",
+			req:     model.CreateAccountRequest{Name: "Crypto", Balance: "0", Type: "crypto"},
+			wantErr: ErrInvalidAccountType,
+		},
+		{
+			name:    "bad balance",
+			req:     model.CreateAccountRequest{Name: "Wallet 2", Balance: "1.00001"},
+			wantErr: ErrInvalidBalance,
+		},
+		{
+			name:    "duplicate name",
+			req:     model.CreateAccountRequest{Name: "Card", Balance: "0"},
+			wantErr: ErrAccountNameExists,
+		},
+		{
+			name:           "duplicate name allowed",
+			req:            model.CreateAccountRequest{Name: "Card", Balance: "0"},
+			allowDuplicate: true,
+			wantCurrency:   "UAH", wantType: model.AccountTypeCard,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.households.byID[hh] = model.Household{ID: hh, DefaultCurrency: "UAH"}
+			f.accountTypes.names[hh] = []string{"brokerage"}
+			existing := f.addAccount(hh, "UAH", 0)
+			existing.Name = "Card"
+
+			svc := NewAccountService(f.repos, decimal.New(1, 12))
+			acc, err := svc.Create(context.Background(), hh, uuid.New(), tt.req, tt.allowDuplicate)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create error = %v, want %v", err, tt.wantErr)
+				}
+				if len(f.accounts.byID) != 1 {
+					t.Errorf("a rejected account was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if acc.Currency != tt.wantCurrency || acc.Type != tt.wantType {
+				t.Errorf("account = %s %s, want %s %s", acc.Currency, acc.Type, tt.wantCurrency, tt.wantType)
+			}
+			if !acc.IncludeInTotals {
+				t.Errorf("include_in_totals = false, want the default true")
+			}
+		})
+	}
+}
+
+func TestDeleteType(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		wantErr error
+	}{
+		{"unused", "loan", nil},
+		{"unused in another case", "Loan", nil},
+		{"in use", "brokerage", ErrAccountTypeInUse},
+		{"in use in another case", "BROKERAGE", ErrAccountTypeInUse},
+		{"unknown", "crypto", ErrAccountTypeNotFound},
+		{"built-in", "Card", ErrInvalidAccountType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.accountTypes.names[hh] = []string{"brokerage", "loan"}
+			f.addAccount(hh, "UAH", 0).Type = "brokerage"
+
+			svc := NewAccountService(f.repos, decimal.New(1, 12))
+			err := svc.DeleteType(context.Background(), hh, tt.typ)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteType = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && slices.Contains(f.accountTypes.names[hh], "loan") {
+				t.Errorf("type still listed after delete")
+			}
+		})
+	}
+}
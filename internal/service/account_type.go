@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrInvalidAccountType  = errors.New("unknown account type")
+	ErrAccountTypeNotFound = errors.New("account type not found")
+	ErrAccountTypeExists   = errors.New("account type already exists")
+	ErrAccountTypeInUse    = errors.New("account type is used by accounts, cannot delete")
+	ErrInvalidTypeName     = errors.New("account type name must be 1-64 characters")
+)
+
+// maxAccountTypeLen matches accounts.type and account_types.name.
+const maxAccountTypeLen = 64
+
+// ListTypes returns the built-in account types followed by the household's
+// custom ones.
+func (s *AccountService) ListTypes(ctx context.Context, householdID uuid.UUID) ([]model.AccountTypeInfo, error) {
+	custom, err := s.repos.AccountTypes.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list account types: %w", err)
+	}
+	out := make([]model.AccountTypeInfo, 0, len(model.BuiltinAccountTypes)+len(custom))
+	for _, t := range model.BuiltinAccountTypes {
+		out = append(out, model.AccountTypeInfo{Name: t, BuiltIn: true})
+	}
+	for _, name := range custom {
+		out = append(out, model.AccountTypeInfo{Name: model.AccountType(name)})
+	}
+	return out, nil
+}
+
+// CreateType adds a custom account type. Names are trimmed and lowercased so
+// "Savings" and "savings " are the same type.
+func (s *AccountService) CreateType(ctx context.Context, householdID uuid.UUID, name string) (*model.AccountTypeInfo, error) {
+	t := normalizeAccountType(model.AccountType(name))
+	if t == "" || len(t) > maxAccountTypeLen {
+		return nil, ErrInvalidTypeName
+	}
+	if t.Builtin() {
+		return nil, ErrAccountTypeExists
+	}
+
+	if err := s.repos.AccountTypes.Create(ctx, householdID, string(t)); err != nil {
+		if mapped := constraintError(err, ErrAccountTypeExists, ErrHouseholdNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, fmt.Errorf("create account type: %w", err)
+	}
+	return &model.AccountTypeInfo{Name: t}, nil
+}
+
+// DeleteType removes a custom account type no account uses anymore.
+func (s *AccountService) DeleteType(ctx context.Context, householdID uuid.UUID, name string) error {
+	t := normalizeAccountType(model.AccountType(name))
+	if t.Builtin() {
+		return ErrInvalidAccountType
+	}
+	return s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		// Deleting first locks the type row: an account write still holding it
+		// (see checkAccountType) commits before the count below, and later ones
+		// no longer find the type. A type in use is put back by the rollback.
+		deleted, err := txRepos.AccountTypes.Delete(txCtx, householdID, string(t))
+		if err != nil {
+			return fmt.Errorf("delete account type: %w", err)
+		}
+		if !deleted {
+			return ErrAccountTypeNotFound
+		}
+
+		count, err := txRepos.AccountTypes.CountAccounts(txCtx, householdID, string(t))
+		if err != nil {
+			return fmt.Errorf("count accounts: %w", err)
+		}
+		if count > 0 {
+			return ErrAccountTypeInUse
+		}
+		return nil
+	})
+}
+
+// normalizeAccountType trims and lower-cases a type name, as custom types are
+// stored.
+func normalizeAccountType(t model.AccountType) model.AccountType {
+	return model.AccountType(strings.ToLower(strings.TrimSpace(string(t))))
+}
+
+// checkAccountType normalizes t and returns it, or ErrInvalidAccountType unless
+// it is a built-in type or one of the household's custom types. A custom type
+// stays locked until the surrounding transaction ends, so call it from the
+// transaction that writes the account.
+func checkAccountType(ctx context.Context, types repository.AccountTypeRepository, householdID uuid.UUID, t model.AccountType) (model.AccountType, error) {
+	t = normalizeAccountType(t)
+	if t.Builtin() {
+		return t, nil
+	}
+	ok, err := types.Exists(ctx, householdID, string(t))
+	if err != nil {
+		return "", fmt.Errorf("check account type: %w", err)
+	}
+	if !ok {
+		return "", ErrInvalidAccountType
+	}
+	return t, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// AlertService warns a household when a category's spending for the month
+// reaches one of its budget's thresholds. Each threshold fires at most once per
+// budget and month.
+type AlertService struct {
+	repos    *repository.Repos
+	webhooks *WebhookDispatcher
+	logger   *slog.Logger
+}
+
+func NewAlertService(repos *repository.Repos, webhooks *WebhookDispatcher, logger *slog.Logger) *AlertService {
+	return &AlertService{repos: repos, webhooks: webhooks, logger: logger}
+}
+
+// TransactionCreated checks the budgets of the categories an expense was split
+// into. It runs after the transaction has committed and costs nothing for
+// transactions without categories; failures are logged, not returned. When
+// one transaction crosses several thresholds, members get a single alert for
+// the highest. A scheduled expense is skipped: its money isn't spent until
+// TransactionPosted. A nil service ignores transactions.
+func (s *AlertService) TransactionCreated(ctx context.Context, txn model.Transaction) {
+	if s == nil || !txn.Posted || txn.Type != model.TransactionTypeExpense || len(txn.Splits) == 0 {
+		return
+	}
+	// Detached from the request so a client hanging up doesn't drop the alert.
+	s.check(context.WithoutCancel(ctx), txn)
+}
+
+// TransactionPosted checks budgets for a scheduled expense once it has been
+// posted, like TransactionCreated does for other expenses. A nil service
+// ignores transactions.
+func (s *AlertService) TransactionPosted(ctx context.Context, txn model.Transaction) {
+	if s == nil || txn.Type != model.TransactionTypeExpense {
+		return
+	}
+	splits, err := s.repos.Transactions.ListSplits(ctx, txn.ID)
+	if err != nil {
+		s.logError(txn.HouseholdID, "list splits", err)
+		return
+	}
+	if len(splits) == 0 {
+		return
+	}
+	txn.Splits = splits
+	s.check(ctx, txn)
+}
+
+// check alerts on the thresholds the expense's categories have reached.
+func (s *AlertService) check(ctx context.Context, txn model.Transaction) {
+	categoryIDs := make([]uuid.UUID, 0, len(txn.Splits))
+	for _, sp := range txn.Splits {
+		if !slices.Contains(categoryIDs, sp.CategoryID) {
+			categoryIDs = append(categoryIDs, sp.CategoryID)
+		}
+	}
+	budgets, err := s.repos.Budgets.ListForCategories(ctx, txn.HouseholdID, categoryIDs)
+	if err != nil {
+		s.logError(txn.HouseholdID, "list budgets", err)
+		return
+	}
+	if len(budgets) == 0 {
+		return
+	}
+
+	hh, err := s.repos.Households.GetByID(ctx, txn.HouseholdID)
+	if err != nil {
+		s.logError(txn.HouseholdID, "get household", err)
+		return
+	}
+	loc, err := time.LoadLocation(hh.Timezone)
+	if err != nil {
+		s.logError(txn.HouseholdID, "load timezone", err)
+		return
+	}
+	from, to := fiscalMonth(txn.TransactedAt, loc, hh.FiscalMonthStartDay)
+	period := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, b := range budgets {
+		spent, err := s.repos.Budgets.CategorySpend(ctx, txn.HouseholdID, b.CategoryID, from, to)
+		if err != nil {
+			s.logError(txn.HouseholdID, "sum category spend", err)
+			continue
+		}
+
+		var highest int32
+		for _, threshold := range reachedThresholds(spent, b.Amount, b.Thresholds) {
+			fired, err := s.repos.Budgets.RecordAlert(ctx, b.ID, period, threshold)
+			if err != nil {
+				s.logError(txn.HouseholdID, "record budget alert", err)
+				continue
+			}
+			if fired {
+				highest = max(highest, threshold)
+			}
+		}
+		if highest == 0 {
+			continue
+		}
+
+		s.alert(ctx, txn.HouseholdID, model.BudgetAlert{
+			BudgetID:    b.ID,
+			CategoryID:  b.CategoryID,
+			PeriodStart: period.Format(time.DateOnly),
+			Threshold:   highest,
+			Amount:      b.Amount,
+			Spent:       spent,
+		})
+	}
+}
+
+// alert notifies the household's members and its webhooks.
+func (s *AlertService) alert(ctx context.Context, householdID uuid.UUID, a model.BudgetAlert) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		s.logError(householdID, "encode budget alert", err)
+		return
+	}
+	if _, err := s.repos.Notifications.NotifyBudgetThreshold(ctx, householdID, data); err != nil {
+		s.logError(householdID, "notify budget threshold", err)
+	}
+	s.webhooks.Publish(householdID, model.WebhookEventBudgetThreshold, a)
+}
+
+func (s *AlertService) logError(householdID uuid.UUID, op string, err error) {
+	s.logger.Error("budget alert: "+op,
+		slog.String("household_id", householdID.String()),
+		slog.String("error", err.Error()),
+	)
+}
+
+// reachedThresholds returns the thresholds (percent of amount) that spent has
+// reached. A threshold is reached exactly at its boundary: 80 of 100 reaches 80.
+func reachedThresholds(spent, amount decimal.Decimal, thresholds []int32) []int32 {
+	var out []int32
+	for _, t := range thresholds {
+		limit := amount.Mul(decimal.NewFromInt32(t)).Div(decimal.NewFromInt(100))
+		if spent.GreaterThanOrEqual(limit) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
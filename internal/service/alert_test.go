@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestReachedThresholds(t *testing.T) {
+	thresholds := []int32{50, 80, 100}
+	tests := []struct {
+		spent, amount string
+		want          []int32
+	}{
+		{"0", "100", nil},
+		{"49.9999", "100", nil},
+		{"50", "100", []int32{50}},
+		{"79.9999", "100", []int32{50}},
+		{"80", "100", []int32{50, 80}},
+		{"99.99", "100", []int32{50, 80}},
+		{"100", "100", []int32{50, 80, 100}},
+		{"250", "100", []int32{50, 80, 100}},
+		// 80% of 0.03 is 0.024, which no four-place amount below it reaches.
+		{"0.0239", "0.03", []int32{50}},
+		{"0.024", "0.03", []int32{50, 80}},
+		// Refunds can leave spend negative.
+		{"-10", "100", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spent+"/"+tt.amount, func(t *testing.T) {
+			got := reachedThresholds(decimal.RequireFromString(tt.spent), decimal.RequireFromString(tt.amount), thresholds)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("reachedThresholds(%s, %s) = %v, want %v", tt.spent, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+// A scheduled expense must not touch budgets when it is created; the fakes
+// have no budget repository, so reaching it would panic.
+func TestTransactionCreatedSkipsScheduled(t *testing.T) {
+	f := newFakes()
+	svc := NewAlertService(f.repos, nil, nil)
+	svc.TransactionCreated(context.Background(), model.Transaction{
+		ID: uuid.New(), HouseholdID: uuid.New(), Type: model.TransactionTypeExpense, Posted: false,
+		Amount: decimal.NewFromInt(10),
+		Splits: []model.TransactionSplit{{CategoryID: uuid.New(), Amount: decimal.NewFromInt(10)}},
+	})
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// maxAmountScale is the number of decimal places amounts are stored with.
+	maxAmountScale = 4
+	// maxAmountDigits is the number of integer digits DECIMAL(19, 4) holds.
+	maxAmountDigits = 15
+)
+
+// parseAmount parses a decimal amount and checks that its magnitude is at most
+// limit and that it has no more decimal places than are stored. The error
+// explains the problem; callers wrap it in their own invalid-input error.
+//
+// decimal accepts exponents, so "1e1000000000" parses into a few bytes. Digit
+// counts are checked before anything that would expand such a value.
+func parseAmount(s string, limit decimal.Decimal) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%q is not a number", s)
+	}
+	if d.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	exp := int(d.Exponent())
+	if d.NumDigits()+exp > maxAmountDigits {
+		return decimal.Zero, fmt.Errorf("must not exceed %s in magnitude", limit)
+	}
+	// Trailing zeros are fine ("1.50000"); further significant places are not.
+	// A value with fewer digits than places to drop can't be all zeros there.
+	if exp < -maxAmountScale && (-exp-maxAmountScale >= d.NumDigits() || !d.Equal(d.Round(maxAmountScale))) {
+		return decimal.Zero, fmt.Errorf("must have at most %d decimal places", maxAmountScale)
+	}
+	if d.Abs().GreaterThan(limit) {
+		return decimal.Zero, fmt.Errorf("must not exceed %s in magnitude", limit)
+	}
+	return d, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseAmount(t *testing.T) {
+	limit := decimal.New(1, 12)
+	tests := []struct {
+		in   string
+		want string // empty means an error is expected
+	}{
+		{"1000000000000", "1000000000000"},
+		{"-1000000000000", "-1000000000000"},
+		{"1000000000000.0001", ""},
+		{"-1000000000000.0001", ""},
+		{"1e12", "1000000000000"},
+		{"1e1000000000", ""},
+		{"1e-1000000000", ""},
+		{"1.50000", "1.5"},
+		{"0.0001", "0.0001"},
+		{"0.00001", ""},
+		{"-12.3400", "-12.34"},
+		{"0", "0"},
+		{"0e1000000000", "0"},
+		{"NaN", ""},
+		{"Inf", ""},
+		{"-Infinity", ""},
+		{"", ""},
+		{"abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAmount(tt.in, limit)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("parseAmount(%q) = %s, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAmount(%q) error: %v", tt.in, err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Fatalf("parseAmount(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrUndoConflict  = errors.New("entity has changed since this action, cannot undo")
+	// ErrUndoReferenceGone means an account or category the old version of the
+	// transaction used has been deleted since.
+	ErrUndoReferenceGone = errors.New("an account or category this action used no longer exists, cannot undo")
+)
+
+const auditEntityTransaction = "transaction"
+
+// AuditService reverts recorded mutations using their audit snapshots.
+type AuditService struct {
+	repos      *repository.Repos
+	undoWindow time.Duration
+}
+
+func NewAuditService(repos *repository.Repos, undoWindow time.Duration) *AuditService {
+	return &AuditService{repos: repos, undoWindow: undoWindow}
+}
+
+// Undo reverts the user's most recent action in the household, provided it happened
+// within the undo window and the affected entity has not been modified since.
+func (s *AuditService) Undo(ctx context.Context, householdID, userID uuid.UUID) (*model.AuditEntry, error) {
+	var entry model.AuditEntry
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		var txErr error
+		entry, txErr = txRepos.Audit.GetLastUndoable(txCtx, householdID, userID, time.Now().Add(-s.undoWindow))
+		if txErr != nil {
+			if errors.Is(txErr, pgx.ErrNoRows) {
+				return ErrNothingToUndo
+			}
+			return fmt.Errorf("get audit entry: %w", txErr)
+		}
+
+		switch entry.Action {
+		case model.AuditActionTransactionCreated:
+			txErr = undoTransactionCreate(txCtx, txRepos, entry)
+		case model.AuditActionTransactionUpdated:
+			txErr = undoTransactionUpdate(txCtx, txRepos, entry)
+		case model.AuditActionTransactionDeleted:
+			txErr = undoTransactionDelete(txCtx, txRepos, entry)
+		default:
+			return ErrNothingToUndo
+		}
+		if txErr != nil {
+			return txErr
+		}
+
+		return txRepos.Audit.MarkUndone(txCtx, entry.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry.UndoneAt = &now
+	return &entry, nil
+}
+
+func undoTransactionCreate(ctx context.Context, repos *repository.Repos, entry model.AuditEntry) error {
+	var after model.Transaction
+	if err := json.Unmarshal(entry.After, &after); err != nil {
+		return fmt.Errorf("decode audit snapshot: %w", err)
+	}
+
+	if _, err := currentTransaction(ctx, repos, entry, after); err != nil {
+		return err
+	}
+
+	deleted, err := repos.Transactions.Delete(ctx, entry.EntityID, entry.HouseholdID)
+	if err != nil {
+		return fmt.Errorf("delete transaction: %w", err)
+	}
+	if !deleted.Posted {
+		return nil
+	}
+	return reverseBalanceChange(ctx, repos.Accounts, deleted.Type, deleted.Amount, deleted.AccountID, deleted.DestinationAccountID)
+}
+
+func undoTransactionUpdate(ctx context.Context, repos *repository.Repos, entry model.AuditEntry) error {
+	var before, after model.Transaction
+	if err := json.Unmarshal(entry.Before, &before); err != nil {
+		return fmt.Errorf("decode audit snapshot: %w", err)
+	}
+	if err := json.Unmarshal(entry.After, &after); err != nil {
+		return fmt.Errorf("decode audit snapshot: %w", err)
+	}
+
+	current, err := currentTransaction(ctx, repos, entry, after)
+	if err != nil {
+		return err
+	}
+	if err := checkUndoReferences(ctx, repos, before); err != nil {
+		return err
+	}
+
+	if current.Posted {
+		if err := reverseBalanceChange(ctx, repos.Accounts, current.Type, current.Amount, current.AccountID, current.DestinationAccountID); err != nil {
+			return err
+		}
+	}
+
+	posted := isDue(before.TransactedAt)
+	_, err = repos.Transactions.Update(ctx, repository.UpdateTransactionParams{
+		ID:                   before.ID,
+		HouseholdID:          before.HouseholdID,
+		Type:                 before.Type,
+		Description:          before.Description,
+		Amount:               before.Amount,
+		AccountID:            before.AccountID,
+		DestinationAccountID: before.DestinationAccountID,
+		Tags:                 before.Tags,
+		Note:                 before.Note,
+		TransactedAt:         before.TransactedAt,
+		Posted:               posted,
+		WasPosted:            current.Posted,
+	})
+	if err != nil {
+		return fmt.Errorf("update transaction: %w", err)
+	}
+	if _, err := replaceSplits(ctx, repos, before.HouseholdID, before.ID, splitParams(before.Splits)); err != nil {
+		return err
+	}
+
+	if !posted {
+		return nil
+	}
+	return applyBalanceChange(ctx, repos.Accounts, before.Type, before.Amount, before.AccountID, before.DestinationAccountID)
+}
+
+func undoTransactionDelete(ctx context.Context, repos *repository.Repos, entry model.AuditEntry) error {
+	var before model.Transaction
+	if err := json.Unmarshal(entry.Before, &before); err != nil {
+		return fmt.Errorf("decode audit snapshot: %w", err)
+	}
+
+	if err := checkUndoReferences(ctx, repos, before); err != nil {
+		return err
+	}
+
+	// The snapshot may predate its posting; what counts is the date now.
+	before.Posted = isDue(before.TransactedAt)
+	restored, err := repos.Transactions.Restore(ctx, before)
+	if err != nil {
+		if mapped := constraintError(err, nil, ErrUndoReferenceGone); mapped != nil {
+			return mapped
+		}
+		return fmt.Errorf("restore transaction: %w", err)
+	}
+	if _, err := storeSplits(ctx, repos, restored.HouseholdID, restored.ID, splitParams(before.Splits)); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return ErrUndoReferenceGone
+		}
+		return err
+	}
+	if !restored.Posted {
+		return nil
+	}
+	return applyBalanceChange(ctx, repos.Accounts, restored.Type, restored.Amount, restored.AccountID, restored.DestinationAccountID)
+}
+
+// checkUndoReferences makes sure the accounts and categories of a snapshot still
+// exist, locking the accounts so they can't be deleted before the undo commits.
+func checkUndoReferences(ctx context.Context, repos *repository.Repos, txn model.Transaction) error {
+	accountIDs := []uuid.UUID{txn.AccountID}
+	if txn.DestinationAccountID != nil {
+		accountIDs = append(accountIDs, *txn.DestinationAccountID)
+	}
+	for _, id := range accountIDs {
+		if _, err := repos.Accounts.GetForUpdate(ctx, id, txn.HouseholdID); err != nil {
+			return notFoundOr(err, ErrUndoReferenceGone, "lock account")
+		}
+	}
+
+	if len(txn.Splits) == 0 {
+		return nil
+	}
+	seen := make(map[uuid.UUID]struct{}, len(txn.Splits))
+	ids := make([]uuid.UUID, 0, len(txn.Splits))
+	for _, sp := range txn.Splits {
+		if _, ok := seen[sp.CategoryID]; !ok {
+			seen[sp.CategoryID] = struct{}{}
+			ids = append(ids, sp.CategoryID)
+		}
+	}
+	n, err := repos.Categories.CountInHousehold(ctx, txn.HouseholdID, ids)
+	if err != nil {
+		return fmt.Errorf("check categories: %w", err)
+	}
+	if n != int64(len(ids)) {
+		return ErrUndoReferenceGone
+	}
+	return nil
+}
+
+// currentTransaction loads the transaction an entry refers to and makes sure it
+// still matches the entry's "after" snapshot.
+func currentTransaction(ctx context.Context, repos *repository.Repos, entry model.AuditEntry, after model.Transaction) (model.Transaction, error) {
+	current, err := repos.Transactions.GetByID(ctx, entry.EntityID, entry.HouseholdID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Transaction{}, ErrUndoConflict
+		}
+		return model.Transaction{}, fmt.Errorf("get transaction: %w", err)
+	}
+	if !current.UpdatedAt.Equal(after.UpdatedAt) {
+		return model.Transaction{}, ErrUndoConflict
+	}
+	return current, nil
+}
+
+// recordAudit stores a before/after snapshot of a mutation. Pass nil for a missing side.
+func recordAudit(ctx context.Context, audit repository.AuditRepository, householdID, userID uuid.UUID, action model.AuditAction, entityType string, entityID uuid.UUID, before, after interface{}) error {
+	params := repository.CreateAuditEntryParams{
+		HouseholdID: householdID,
+		UserID:      userID,
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+	}
+
+	var err error
+	if before != nil {
+		if params.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("encode audit snapshot: %w", err)
+		}
+	}
+	if after != nil {
+		if params.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("encode audit snapshot: %w", err)
+		}
+	}
+
+	if _, err := audit.Create(ctx, params); err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestUndoDeleteChecksReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		remove  func(f *fakes, txn model.Transaction)
+		wantErr error
+	}{
+		{"everything still there", func(*fakes, model.Transaction) {}, nil},
+		{"account deleted", func(f *fakes, txn model.Transaction) {
+			delete(f.accounts.byID, txn.AccountID)
+		}, ErrUndoReferenceGone},
+		{"destination deleted", func(f *fakes, txn model.Transaction) {
+			delete(f.accounts.byID, *txn.DestinationAccountID)
+		}, ErrUndoReferenceGone},
+		{"category deleted", func(f *fakes, txn model.Transaction) {
+			f.categories.byHousehold[txn.HouseholdID] = nil
+		}, ErrUndoReferenceGone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh, user := uuid.New(), uuid.New()
+			src := f.addAccount(hh, "UAH", 100)
+			dst := f.addAccount(hh, "UAH", 0)
+			category := uuid.New()
+			f.categories.byHousehold[hh] = []uuid.UUID{category}
+
+			deleted := model.Transaction{
+				ID: uuid.New(), HouseholdID: hh, Type: model.TransactionTypeTransfer, Description: "Savings",
+				Amount: decimal.NewFromInt(10), AccountID: src.ID, DestinationAccountID: &dst.ID,
+				TransactedAt: time.Now().Add(-time.Hour), Posted: true, CreatedBy: user,
+				Splits: []model.TransactionSplit{{ID: uuid.New(), CategoryID: category, Amount: decimal.NewFromInt(10)}},
+			}
+			before, err := json.Marshal(deleted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.audit.undoable = &model.AuditEntry{
+				ID: uuid.New(), HouseholdID: hh, UserID: user, Action: model.AuditActionTransactionDeleted,
+				EntityType: auditEntityTransaction, EntityID: deleted.ID, Before: before,
+			}
+			tt.remove(f, deleted)
+
+			_, err = NewAuditService(f.repos, time.Hour).Undo(context.Background(), hh, user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Undo = %v, want %v", err, tt.wantErr)
+			}
+			_, restored := f.transactions.byID[deleted.ID]
+			if restored != (tt.wantErr == nil) {
+				t.Errorf("transaction restored = %v, want %v", restored, tt.wantErr == nil)
+			}
+			if tt.wantErr != nil {
+				if len(f.audit.undone) != 0 {
+					t.Errorf("entry marked undone after a failed undo")
+				}
+				return
+			}
+			if !src.Balance.Equal(decimal.NewFromInt(90)) || !dst.Balance.Equal(decimal.NewFromInt(10)) {
+				t.Errorf("balances %s and %s, want 90 and 10", src.Balance, dst.Balance)
+			}
+		})
+	}
+}
@@ -7,37 +7,86 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/shopspring/decimal"
 
 	"github.com/howallet/howallet/internal/config"
 	"github.com/howallet/howallet/internal/model"
-	"github.com/howallet/howallet/internal/repository/postgres"
+	"github.com/howallet/howallet/internal/repository"
 )
 
 var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrEmailTaken         = errors.New("email already registered")
 	ErrInvalidToken       = errors.New("invalid or expired token")
+	// ErrTokenReused wraps ErrInvalidToken, so callers that only check for the
+	// latter still reject the request.
+	ErrTokenReused     = fmt.Errorf("%w: refresh token already used", ErrInvalidToken)
+	ErrSessionNotFound = errors.New("session not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrInvalidEmail    = errors.New("invalid email address")
+	ErrInvalidProfile  = errors.New("name must not be empty")
+	// ErrEmailChangeUnsupported is returned for a new email: without verifying
+	// it, anyone could claim an address and with it, say, an invitation sent there.
+	ErrEmailChangeUnsupported = errors.New("changing your email is not supported yet")
+	ErrPasswordUnchanged      = errors.New("new password must differ from the current one")
 )
 
 type AuthService struct {
-	repos *postgres.Repos
-	jwt   *config.JWTConfig
+	repos    *repository.Repos
+	jwt      *config.JWTConfig
+	password config.PasswordConfig
+	hasher   PasswordHasher
+	// dummyHash is verified against when a login names an unknown email, so
+	// that it takes as long as a wrong password and doesn't reveal which
+	// addresses have accounts.
+	dummyHash func() (string, error)
+	// household configures the household created at registration.
+	household config.HouseholdConfig
 }
 
-func NewAuthService(repos *postgres.Repos, jwtCfg *config.JWTConfig) *AuthService {
-	return &AuthService{repos: repos, jwt: jwtCfg}
+func NewAuthService(repos *repository.Repos, jwtCfg *config.JWTConfig, password config.PasswordConfig, household config.HouseholdConfig) *AuthService {
+	hasher := NewPasswordHasher(password)
+	return &AuthService{
+		repos:     repos,
+		jwt:       jwtCfg,
+		password:  password,
+		hasher:    hasher,
+		dummyHash: sync.OnceValues(func() (string, error) { return hasher.Hash("howallet-dummy-password") }),
+		household: household,
+	}
+}
+
+// starterAccounts are created, empty, in a new user's household when
+// SeedDefaultAccounts is on.
+var starterAccounts = []struct {
+	name    string
+	accType model.AccountType
+}{
+	{"Cash", model.AccountTypeCash},
+	{"Card", model.AccountTypeCard},
 }
 
 // Register creates a new user, a default household, and returns tokens.
-func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest) (*model.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	email, err := validateEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = email
+	if err := checkPassword(s.password, req.Password); err != nil {
+		return nil, err
+	}
+
 	// Check if email is taken
-	_, err := s.repos.Users.GetByEmail(ctx, req.Email)
+	_, err = s.repos.Users.GetByEmail(ctx, req.Email)
 	if err == nil {
 		return nil, ErrEmailTaken
 	}
@@ -46,22 +95,27 @@ func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest) (
 	}
 
 	// Hash password
-	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
+	hash, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("hash password: %w", err)
 	}
 
 	var user model.User
 	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+		txRepos := repository.TxReposFromCtx(txCtx)
 
 		var txErr error
-		user, txErr = txRepos.Users.Create(txCtx, req.Email, string(hash), req.Name)
+		user, txErr = txRepos.Users.Create(txCtx, req.Email, hash, req.Name)
 		if txErr != nil {
 			return fmt.Errorf("create user: %w", txErr)
 		}
 
-		hh, txErr := txRepos.Households.Create(txCtx, req.Name+"'s Wallet", user.ID)
+		hh, txErr := txRepos.Households.Create(txCtx, repository.CreateHouseholdParams{
+			Name:            req.Name + "'s Wallet",
+			Timezone:        defaultTimezone,
+			DefaultCurrency: s.household.DefaultCurrency,
+			OwnerID:         user.ID,
+		})
 		if txErr != nil {
 			return fmt.Errorf("create household: %w", txErr)
 		}
@@ -71,9 +125,30 @@ func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest) (
 			return fmt.Errorf("add household member: %w", txErr)
 		}
 
+		if s.household.SeedDefaultAccounts {
+			for _, starter := range starterAccounts {
+				_, txErr = txRepos.Accounts.Create(txCtx, repository.CreateAccountParams{
+					HouseholdID:     hh.ID,
+					Name:            starter.name,
+					Type:            starter.accType,
+					Balance:         decimal.Zero,
+					Currency:        hh.DefaultCurrency,
+					CreatedBy:       user.ID,
+					IncludeInTotals: true,
+				})
+				if txErr != nil {
+					return fmt.Errorf("create %s account: %w", starter.name, txErr)
+				}
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
+		// Someone registered the same address (in any case) concurrently.
+		if mapped := constraintError(err, ErrEmailTaken, nil); mapped != nil {
+			return nil, mapped
+		}
 		return nil, err
 	}
 
@@ -83,7 +158,7 @@ func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest) (
 		return nil, err
 	}
 
-	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID)
+	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID, client)
 	if err != nil {
 		return nil, err
 	}
@@ -96,25 +171,40 @@ func (s *AuthService) Register(ctx context.Context, req model.RegisterRequest) (
 }
 
 // Login authenticates a user and returns tokens.
-func (s *AuthService) Login(ctx context.Context, req model.LoginRequest) (*model.AuthResponse, error) {
-	user, err := s.repos.Users.GetByEmail(ctx, req.Email)
+func (s *AuthService) Login(ctx context.Context, req model.LoginRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	user, err := s.repos.Users.GetByEmail(ctx, normalizeEmail(req.Email))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			if hash, hashErr := s.dummyHash(); hashErr == nil {
+				_, _, _ = s.hasher.Verify(hash, req.Password)
+			}
 			return nil, ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("get user: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+	ok, rehash, err := s.hasher.Verify(user.PasswordHash, req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("verify password: %w", err)
+	}
+	if !ok {
 		return nil, ErrInvalidCredentials
 	}
+	if rehash {
+		// Upgrade to the configured algorithm and cost while the plain password
+		// is at hand. Best effort: a failure leaves the old hash, which still
+		// works, and the next login tries again.
+		if hash, err := s.hasher.Hash(req.Password); err == nil {
+			_ = s.repos.Users.UpdatePassword(ctx, user.ID, hash)
+		}
+	}
 
 	accessToken, err := s.generateAccessToken(user.ID, user.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID)
+	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID, client)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +217,9 @@ func (s *AuthService) Login(ctx context.Context, req model.LoginRequest) (*model
 }
 
 // Refresh validates a refresh token and issues a new access + refresh pair.
-func (s *AuthService) Refresh(ctx context.Context, rawToken string) (*model.AuthResponse, error) {
+// The presented token is marked used rather than deleted; presenting it again
+// revokes every token in its family and forces the user to log in again.
+func (s *AuthService) Refresh(ctx context.Context, rawToken string, client model.ClientInfo) (*model.AuthResponse, error) {
 	h := hashToken(rawToken)
 
 	rt, err := s.repos.RefreshTokens.GetByHash(ctx, h)
@@ -135,14 +227,18 @@ func (s *AuthService) Refresh(ctx context.Context, rawToken string) (*model.Auth
 		return nil, ErrInvalidToken
 	}
 
-	if rt.ExpiresAt.Before(time.Now()) {
+	if rt.UsedAt != nil {
+		// A rotated token has come back, so it was copied; either holder may
+		// now have its successor, so end the whole chain.
+		_ = s.repos.RefreshTokens.DeleteFamily(ctx, rt.FamilyID)
+		return nil, ErrTokenReused
+	}
+
+	if rt.ExpiresAt.Before(time.Now()) || s.isIdle(rt) {
 		_ = s.repos.RefreshTokens.Delete(ctx, h)
 		return nil, ErrInvalidToken
 	}
 
-	// Delete the old refresh token (rotation)
-	_ = s.repos.RefreshTokens.Delete(ctx, h)
-
 	user, err := s.repos.Users.GetByID(ctx, rt.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("get user: %w", err)
@@ -153,7 +249,31 @@ func (s *AuthService) Refresh(ctx context.Context, rawToken string) (*model.Auth
 		return nil, err
 	}
 
-	newRefresh, err := s.generateAndStoreRefreshToken(ctx, user.ID)
+	// Rotate: the replacement joins the same family and starts with a fresh
+	// last_used_at, so the idle window restarts with every refresh. Marking the
+	// old token used only succeeds once, which catches concurrent replays.
+	var newRefresh string
+	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		raw, id, txErr := s.storeRefreshToken(txCtx, txRepos, user.ID, rt.FamilyID, client)
+		if txErr != nil {
+			return txErr
+		}
+		marked, txErr := txRepos.RefreshTokens.MarkUsed(txCtx, rt.ID, id)
+		if txErr != nil {
+			return fmt.Errorf("mark refresh token used: %w", txErr)
+		}
+		if !marked {
+			return ErrTokenReused
+		}
+		newRefresh = raw
+		return nil
+	})
+	if errors.Is(err, ErrTokenReused) {
+		_ = s.repos.RefreshTokens.DeleteFamily(ctx, rt.FamilyID)
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -165,35 +285,195 @@ func (s *AuthService) Refresh(ctx context.Context, rawToken string) (*model.Auth
 	}, nil
 }
 
-// Logout deletes all refresh tokens for the user.
+// Logout deletes all refresh tokens for the user, ending every session.
 func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID) error {
 	return s.repos.RefreshTokens.DeleteByUser(ctx, userID)
 }
 
+// LogoutSession deletes a single session's refresh tokens, leaving the user's
+// other sessions alive. Only the caller's own tokens are touched. A token that
+// is already gone (a retried logout, or one after rotation) is not an error.
+func (s *AuthService) LogoutSession(ctx context.Context, userID uuid.UUID, rawToken string) error {
+	if _, err := s.repos.RefreshTokens.DeleteByHash(ctx, hashToken(rawToken), userID); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// Me returns the authenticated user.
+func (s *AuthService) Me(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	user, err := s.repos.Users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return &user, nil
+}
+
+// UpdateProfile changes the user's name. Emails can't be changed until they can
+// be verified: an email other than the current one fails with
+// ErrEmailChangeUnsupported, while the current one is accepted and ignored.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req model.UpdateProfileRequest) (*model.User, error) {
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, ErrInvalidProfile
+	}
+	if req.Email != nil {
+		email, err := validateEmail(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		current, err := s.repos.Users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, notFoundOr(err, ErrUserNotFound, "get user")
+		}
+		if !strings.EqualFold(current.Email, email) {
+			return nil, ErrEmailChangeUnsupported
+		}
+		req.Email = nil
+	}
+
+	user, err := s.repos.Users.Update(ctx, userID, req.Name, nil)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound, "update user")
+	}
+	return &user, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+// The new password must satisfy the policy and differ from the current one.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, req model.ChangePasswordRequest) error {
+	user, err := s.repos.Users.GetByID(ctx, userID)
+	if err != nil {
+		return notFoundOr(err, ErrUserNotFound, "get user")
+	}
+	ok, _, err := s.hasher.Verify(user.PasswordHash, req.CurrentPassword)
+	if err != nil {
+		return fmt.Errorf("verify password: %w", err)
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+
+	if err := checkPassword(s.password, req.NewPassword); err != nil {
+		return err
+	}
+	if same, _, _ := s.hasher.Verify(user.PasswordHash, req.NewPassword); same {
+		return ErrPasswordUnchanged
+	}
+
+	hash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.repos.Users.UpdatePassword(ctx, userID, hash); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	return nil
+}
+
+// ListSessions returns the user's active sessions (unexpired refresh tokens).
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]model.Session, error) {
+	rows, err := s.repos.RefreshTokens.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	sessions := make([]model.Session, 0, len(rows))
+	for _, rt := range rows {
+		if s.isIdle(rt) {
+			continue
+		}
+		sessions = append(sessions, model.Session{
+			ID:         rt.ID,
+			UserAgent:  rt.UserAgent,
+			IPAddress:  rt.IPAddress,
+			CreatedAt:  rt.CreatedAt,
+			LastUsedAt: rt.LastUsedAt,
+			ExpiresAt:  rt.ExpiresAt,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions by ID.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	deleted, err := s.repos.RefreshTokens.DeleteByID(ctx, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	if !deleted {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// --- validation helpers ---
+
+// normalizeEmail lowercases an address so lookups are case-insensitive.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateEmail normalizes an address and rejects anything that isn't a bare
+// addr-spec (no display names or angle brackets). Plus-addressing is allowed.
+func validateEmail(email string) (string, error) {
+	email = normalizeEmail(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
 // --- token helpers ---
 
+// isIdle reports whether a refresh token has been unused longer than the idle TTL.
+func (s *AuthService) isIdle(rt repository.RefreshTokenRow) bool {
+	return s.jwt.RefreshIdleTTL > 0 && rt.LastUsedAt.Add(s.jwt.RefreshIdleTTL).Before(time.Now())
+}
+
 func (s *AuthService) generateAccessToken(userID uuid.UUID, email string) (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"sub":   userID.String(),
 		"email": email,
+		"iss":   s.jwt.Issuer,
+		"aud":   s.jwt.Audience,
 		"iat":   now.Unix(),
 		"exp":   now.Add(s.jwt.AccessTTL).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwt.Secret))
+	key, kid := s.jwt.SigningKey()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(string(s.jwt.Algorithm)), claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
 }
 
-func (s *AuthService) generateAndStoreRefreshToken(ctx context.Context, userID uuid.UUID) (string, error) {
+// generateAndStoreRefreshToken issues the first refresh token of a new family.
+func (s *AuthService) generateAndStoreRefreshToken(ctx context.Context, userID uuid.UUID, client model.ClientInfo) (string, error) {
+	raw, _, err := s.storeRefreshToken(ctx, s.repos, userID, uuid.New(), client)
+	return raw, err
+}
+
+// storeRefreshToken creates a refresh token in the given family and returns the
+// raw token along with its ID.
+func (s *AuthService) storeRefreshToken(ctx context.Context, repos *repository.Repos, userID, familyID uuid.UUID, client model.ClientInfo) (string, uuid.UUID, error) {
 	raw := generateRandomToken(32)
-	h := hashToken(raw)
 
-	err := s.repos.RefreshTokens.Create(ctx, userID, h, time.Now().Add(s.jwt.RefreshTTL))
+	id, err := repos.RefreshTokens.Create(ctx, repository.CreateRefreshTokenParams{
+		UserID:    userID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.jwt.RefreshTTL),
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		FamilyID:  familyID,
+	})
 	if err != nil {
-		return "", fmt.Errorf("store refresh token: %w", err)
+		return "", uuid.Nil, fmt.Errorf("store refresh token: %w", err)
 	}
 
-	return raw, nil
+	return raw, id, nil
 }
 
 func generateRandomToken(n int) string {
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+)
+
+func newTestAuthService(f *fakes) *AuthService {
+	jwtCfg := &config.JWTConfig{
+		Algorithm:  config.JWTAlgorithmHS256,
+		Secret:     "test-secret-test-secret-test-secret",
+		Issuer:     "howallet",
+		Audience:   "howallet",
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	}
+	password := config.PasswordConfig{Hash: config.PasswordHashBcrypt, BcryptCost: 4, MinLength: 8}
+	return NewAuthService(f.repos, jwtCfg, password, config.HouseholdConfig{})
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newFakes()
+	svc := newTestAuthService(f)
+	ctx := context.Background()
+	user := model.User{ID: uuid.New(), Email: "a@example.com"}
+	f.users.byID[user.ID] = user
+
+	first, err := svc.generateAndStoreRefreshToken(ctx, user.ID, model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("store refresh token: %v", err)
+	}
+	rotated, err := svc.Refresh(ctx, first, model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// Replaying the rotated-out token means it was copied.
+	if _, err := svc.Refresh(ctx, first, model.ClientInfo{}); !errors.Is(err, ErrTokenReused) {
+		t.Fatalf("replayed Refresh error = %v, want ErrTokenReused", err)
+	}
+	if len(f.tokens.byHash) != 0 {
+		t.Fatalf("%d tokens left, want the whole family revoked", len(f.tokens.byHash))
+	}
+	// The successor the legitimate holder got is gone with the rest.
+	if _, err := svc.Refresh(ctx, rotated.RefreshToken, model.ClientInfo{}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Refresh with successor error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestRefreshTokenReuseSparesOtherFamilies(t *testing.T) {
+	f := newFakes()
+	svc := newTestAuthService(f)
+	ctx := context.Background()
+	user := model.User{ID: uuid.New(), Email: "a@example.com"}
+	f.users.byID[user.ID] = user
+
+	stolen, _ := svc.generateAndStoreRefreshToken(ctx, user.ID, model.ClientInfo{})
+	other, _ := svc.generateAndStoreRefreshToken(ctx, user.ID, model.ClientInfo{})
+	if _, err := svc.Refresh(ctx, stolen, model.ClientInfo{}); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := svc.Refresh(ctx, stolen, model.ClientInfo{}); !errors.Is(err, ErrTokenReused) {
+		t.Fatalf("replayed Refresh error = %v, want ErrTokenReused", err)
+	}
+	if _, err := svc.Refresh(ctx, other, model.ClientInfo{}); err != nil {
+		t.Fatalf("Refresh in another session: %v", err)
+	}
+}
+
+// countingHasher records the hashes Verify was asked to check.
+type countingHasher struct {
+	PasswordHasher
+	verified []string
+}
+
+func (h *countingHasher) Verify(hash, password string) (bool, bool, error) {
+	h.verified = append(h.verified, hash)
+	return h.PasswordHasher.Verify(hash, password)
+}
+
+// newLoginFixture returns a service whose hasher is counted, with one user
+// whose password is "correct horse".
+func newLoginFixture(tb testing.TB) (*AuthService, *countingHasher, model.User) {
+	f := newFakes()
+	svc := newTestAuthService(f)
+	hash, err := svc.hasher.Hash("correct horse")
+	if err != nil {
+		tb.Fatalf("hash password: %v", err)
+	}
+	user := model.User{ID: uuid.New(), Email: "known@example.com", PasswordHash: hash}
+	f.users.byID[user.ID] = user
+	counting := &countingHasher{PasswordHasher: svc.hasher}
+	svc.hasher = counting
+	return svc, counting, user
+}
+
+// An unknown email must cost the same password check as a wrong password for a
+// known one, or response times reveal which addresses have accounts.
+func TestLoginUnknownEmailChecksDummyHash(t *testing.T) {
+	svc, counting, user := newLoginFixture(t)
+	ctx := context.Background()
+
+	_, err := svc.Login(ctx, model.LoginRequest{Email: user.Email, Password: "wrong"}, model.ClientInfo{})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login with wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+	_, err = svc.Login(ctx, model.LoginRequest{Email: "unknown@example.com", Password: "wrong"}, model.ClientInfo{})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login with unknown email error = %v, want ErrInvalidCredentials", err)
+	}
+
+	if len(counting.verified) != 2 {
+		t.Fatalf("Verify called %d times, want once per login", len(counting.verified))
+	}
+	known, dummy := counting.verified[0], counting.verified[1]
+	if dummy == known {
+		t.Fatal("unknown email was checked against the user's hash")
+	}
+	// Same algorithm and cost, so the same amount of work: "$2a$04$" for bcrypt.
+	if known[:7] != dummy[:7] || !strings.HasPrefix(dummy, "$2") {
+		t.Errorf("dummy hash %q differs in algorithm or cost from %q", dummy[:7], known[:7])
+	}
+}
+
+// BenchmarkLogin compares failed logins for a known and an unknown email;
+// their ns/op should match.
+func BenchmarkLogin(b *testing.B) {
+	for _, email := range []string{"known@example.com", "unknown@example.com"} {
+		b.Run(strings.Split(email, "@")[0], func(b *testing.B) {
+			svc, _, _ := newLoginFixture(b)
+			req := model.LoginRequest{Email: email, Password: "wrong"}
+			for b.Loop() {
+				_, _ = svc.Login(context.Background(), req, model.ClientInfo{})
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // empty means ErrInvalidEmail
+	}{
+		{"user@example.com", "user@example.com"},
+		{"  User@Example.COM\t", "user@example.com"},
+		{"user+tag@example.com", "user+tag@example.com"},
+		{"User+Tag@Example.com", "user+tag@example.com"},
+		{"first.last+a+b@sub.example.co.uk", "first.last+a+b@sub.example.co.uk"},
+		{"", ""},
+		{"notanemail", ""},
+		{"user@", ""},
+		{"@example.com", ""},
+		{"user@@example.com", ""},
+		{"User <user@example.com>", ""},
+		{"<user@example.com>", ""},
+		{"a@example.com, b@example.com", ""},
+		{"user name@example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := validateEmail(tt.in)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidEmail) {
+					t.Fatalf("validateEmail(%q) = %q, %v; want ErrInvalidEmail", tt.in, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("validateEmail(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterMixedCaseDuplicate(t *testing.T) {
+	f := newFakes()
+	svc := newTestAuthService(f)
+	ctx := context.Background()
+
+	first, err := svc.Register(ctx, model.RegisterRequest{Email: "Alice@Example.com", Password: "long enough", Name: "Alice"}, model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if first.User.Email != "alice@example.com" {
+		t.Errorf("stored email = %q, want it lower-cased", first.User.Email)
+	}
+
+	for _, email := range []string{"alice@example.com", "ALICE@EXAMPLE.COM", " aLiCe@example.Com "} {
+		_, err := svc.Register(ctx, model.RegisterRequest{Email: email, Password: "long enough", Name: "Mallory"}, model.ClientInfo{})
+		if !errors.Is(err, ErrEmailTaken) {
+			t.Errorf("Register(%q) error = %v, want ErrEmailTaken", email, err)
+		}
+	}
+	if len(f.users.byID) != 1 {
+		t.Errorf("%d users stored, want 1", len(f.users.byID))
+	}
+
+	// Logging in with any casing finds the same account.
+	resp, err := svc.Login(ctx, model.LoginRequest{Email: "ALICE@example.COM", Password: "long enough"}, model.ClientInfo{})
+	if err != nil || resp.User.ID != first.User.ID {
+		t.Fatalf("Login with different case = %v, %v; want the registered user", resp, err)
+	}
+}
+
+func TestLogoutSession(t *testing.T) {
+	f := newFakes()
+	svc := newTestAuthService(f)
+	ctx := context.Background()
+	owner, other := uuid.New(), uuid.New()
+
+	token, err := svc.generateAndStoreRefreshToken(ctx, owner, model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("store refresh token: %v", err)
+	}
+
+	// Someone else's token is left alone, without telling them it exists.
+	if err := svc.LogoutSession(ctx, other, token); err != nil {
+		t.Fatalf("LogoutSession by another user: %v", err)
+	}
+	if len(f.tokens.byHash) != 1 {
+		t.Fatalf("another user's logout removed the session")
+	}
+
+	if err := svc.LogoutSession(ctx, owner, token); err != nil {
+		t.Fatalf("LogoutSession: %v", err)
+	}
+	if len(f.tokens.byHash) != 0 {
+		t.Fatalf("session still stored after logout")
+	}
+	// A retried logout succeeds too.
+	if err := svc.LogoutSession(ctx, owner, token); err != nil {
+		t.Fatalf("repeated LogoutSession: %v", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	name := "Olena K."
+	sameEmail := "Olena@Example.com"
+	otherEmail := "olena.k@example.com"
+	tests := []struct {
+		name      string
+		req       model.UpdateProfileRequest
+		wantErr   error
+		wantName  string
+		wantEmail string
+	}{
+		{"name only", model.UpdateProfileRequest{Name: &name}, nil, name, "olena@example.com"},
+		{"current email is a no-op", model.UpdateProfileRequest{Name: &name, Email: &sameEmail}, nil, name, "olena@example.com"},
+		{"new email", model.UpdateProfileRequest{Name: &name, Email: &otherEmail}, ErrEmailChangeUnsupported, "Olena", "olena@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			user := model.User{ID: uuid.New(), Email: "olena@example.com", Name: "Olena"}
+			f.users.byID[user.ID] = user
+			svc := newTestAuthService(f)
+
+			_, err := svc.UpdateProfile(context.Background(), user.ID, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfile = %v, want %v", err, tt.wantErr)
+			}
+			got := f.users.byID[user.ID]
+			if got.Name != tt.wantName || got.Email != tt.wantEmail {
+				t.Errorf("user = %q <%s>, want %q <%s>", got.Name, got.Email, tt.wantName, tt.wantEmail)
+			}
+		})
+	}
+}
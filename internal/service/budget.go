@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+	ErrBudgetExists   = errors.New("category already has a budget")
+	ErrInvalidBudget  = errors.New("invalid budget")
+)
+
+// maxBudgetThreshold caps alert thresholds, in percent of the budget.
+const maxBudgetThreshold = 1000
+
+// defaultBudgetThresholds warn as spending approaches and then reaches the limit.
+var defaultBudgetThresholds = []int32{80, 100}
+
+// BudgetService manages monthly category budgets.
+type BudgetService struct {
+	repos     *repository.Repos
+	maxAmount decimal.Decimal
+}
+
+func NewBudgetService(repos *repository.Repos, maxAmount decimal.Decimal) *BudgetService {
+	return &BudgetService{repos: repos, maxAmount: maxAmount}
+}
+
+func (s *BudgetService) Create(ctx context.Context, householdID uuid.UUID, req model.BudgetRequest) (*model.Budget, error) {
+	params, err := s.budgetParams(ctx, householdID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.repos.Budgets.Create(ctx, params)
+	if err != nil {
+		if mapped := constraintError(err, ErrBudgetExists, ErrCategoryNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, fmt.Errorf("create budget: %w", err)
+	}
+	return &b, nil
+}
+
+func (s *BudgetService) List(ctx context.Context, householdID uuid.UUID) ([]model.Budget, error) {
+	budgets, err := s.repos.Budgets.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+// Update replaces every field of a budget.
+func (s *BudgetService) Update(ctx context.Context, id, householdID uuid.UUID, req model.BudgetRequest) (*model.Budget, error) {
+	params, err := s.budgetParams(ctx, householdID, req)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = id
+
+	b, err := s.repos.Budgets.Update(ctx, params)
+	if err != nil {
+		if mapped := constraintError(err, ErrBudgetExists, ErrCategoryNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, notFoundOr(err, ErrBudgetNotFound, "update budget")
+	}
+	return &b, nil
+}
+
+func (s *BudgetService) Delete(ctx context.Context, id, householdID uuid.UUID) error {
+	deleted, err := s.repos.Budgets.Delete(ctx, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete budget: %w", err)
+	}
+	if !deleted {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// budgetParams validates a budget request. The category must belong to the
+// household; thresholds are deduplicated and sorted.
+func (s *BudgetService) budgetParams(ctx context.Context, householdID uuid.UUID, req model.BudgetRequest) (repository.BudgetParams, error) {
+	amount, err := parseAmount(strings.TrimSpace(req.Amount), s.maxAmount)
+	if err != nil {
+		return repository.BudgetParams{}, fmt.Errorf("%w: amount %v", ErrInvalidBudget, err)
+	}
+	if !amount.IsPositive() {
+		return repository.BudgetParams{}, fmt.Errorf("%w: amount must be positive", ErrInvalidBudget)
+	}
+
+	thresholds := defaultBudgetThresholds
+	if len(req.Thresholds) > 0 {
+		thresholds = slices.Clone(req.Thresholds)
+		slices.Sort(thresholds)
+		thresholds = slices.Compact(thresholds)
+		if thresholds[0] < 1 || thresholds[len(thresholds)-1] > maxBudgetThreshold {
+			return repository.BudgetParams{}, fmt.Errorf("%w: thresholds must be between 1 and %d percent", ErrInvalidBudget, maxBudgetThreshold)
+		}
+	}
+
+	n, err := s.repos.Categories.CountInHousehold(ctx, householdID, []uuid.UUID{req.CategoryID})
+	if err != nil {
+		return repository.BudgetParams{}, fmt.Errorf("check category: %w", err)
+	}
+	if n == 0 {
+		return repository.BudgetParams{}, ErrCategoryNotFound
+	}
+
+	return repository.BudgetParams{
+		HouseholdID: householdID,
+		CategoryID:  req.CategoryID,
+		Amount:      amount,
+		Thresholds:  thresholds,
+	}, nil
+}
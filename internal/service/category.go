@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var ErrCategoryExists = errors.New("category already exists")
+
+type CategoryService struct {
+	categories repository.CategoryRepository
+}
+
+func NewCategoryService(categories repository.CategoryRepository) *CategoryService {
+	return &CategoryService{categories: categories}
+}
+
+func (s *CategoryService) Create(ctx context.Context, householdID uuid.UUID, req model.CreateCategoryRequest) (*model.Category, error) {
+	c, err := s.categories.Create(ctx, householdID, req.Name)
+	if err != nil {
+		if mapped := constraintError(err, ErrCategoryExists, ErrHouseholdNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, fmt.Errorf("create category: %w", err)
+	}
+	return &c, nil
+}
+
+func (s *CategoryService) List(ctx context.Context, householdID uuid.UUID) ([]model.Category, error) {
+	return s.categories.ListByHousehold(ctx, householdID)
+}
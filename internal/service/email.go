@@ -1,25 +1,54 @@
 package service
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/smtp"
 	"strings"
+	"time"
 
 	"github.com/howallet/howallet/internal/config"
 )
 
-// EmailService sends transactional emails via SMTP.
-type EmailService struct {
+// ErrEmailDisabled is returned by mailers when SMTP is not configured. Callers should
+// treat it as "not sent" and carry on rather than fail the request.
+var ErrEmailDisabled = errors.New("email is disabled: SMTP is not configured")
+
+// Mailer sends transactional emails.
+type Mailer interface {
+	// SendInvitation sends a household invitation email with a link to accept,
+	// giving up when ctx is done.
+	SendInvitation(ctx context.Context, toEmail, householdName, inviterName, token, frontendURL string, expiresAt time.Time) error
+}
+
+// smtpTimeout bounds a whole SMTP exchange when ctx sets no earlier deadline.
+const smtpTimeout = 30 * time.Second
+
+// NewMailer returns an SMTP mailer, or a logging mailer when SMTP_HOST is empty
+// so local development and self-hosting work without an SMTP server.
+func NewMailer(cfg *config.SMTPConfig, logger *slog.Logger) Mailer {
+	if !cfg.Enabled() {
+		return &LogMailer{logger: logger}
+	}
+	return NewSMTPMailer(cfg)
+}
+
+// SMTPMailer sends transactional emails via SMTP.
+type SMTPMailer struct {
 	cfg *config.SMTPConfig
 }
 
-func NewEmailService(cfg *config.SMTPConfig) *EmailService {
-	return &EmailService{cfg: cfg}
+func NewSMTPMailer(cfg *config.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
 }
 
 // SendInvitation sends a household invitation email with a link to accept.
-func (s *EmailService) SendInvitation(toEmail, householdName, inviterName, token, frontendURL string) error {
-	acceptURL := fmt.Sprintf("%s/invite/%s", strings.TrimRight(frontendURL, "/"), token)
+func (s *SMTPMailer) SendInvitation(ctx context.Context, toEmail, householdName, inviterName, token, frontendURL string, expiresAt time.Time) error {
+	acceptURL := invitationURL(frontendURL, token)
 
 	subject := fmt.Sprintf("You've been invited to join \"%s\" on hoWallet", householdName)
 	body := fmt.Sprintf(`Hello!
@@ -29,18 +58,95 @@ func (s *EmailService) SendInvitation(toEmail, householdName, inviterName, token
 Click the link below to accept the invitation:
 %s
 
-This invitation will expire in 7 days.
+This invitation will expire on %s.
 
 If you don't have a hoWallet account yet, please register first and then use the link above.
 
 — hoWallet Team
-`, inviterName, householdName, acceptURL)
+`, inviterName, householdName, acceptURL, expiresAt.UTC().Format("January 2, 2006 at 15:04 MST"))
 
 	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
 		s.cfg.From, toEmail, subject, body)
 
-	auth := smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)
+	return s.send(ctx, toEmail, []byte(msg))
+}
+
+// send does what smtp.SendMail does, but never waits on the server past ctx's
+// deadline (or smtpTimeout): smtp.SendMail has no timeouts at all.
+func (s *SMTPMailer) send(ctx context.Context, to string, msg []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("set smtp deadline: %w", err)
+	}
+	// Cancelling ctx unblocks the exchange as well as its deadline does.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp hello: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.cfg.User != "" {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)); err != nil {
+				return fmt.Errorf("smtp auth: %w", err)
+			}
+		}
+	}
+	if err := c.Mail(s.cfg.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return c.Quit()
+}
+
+// LogMailer writes emails to the log instead of sending them and reports
+// ErrEmailDisabled, so callers know nothing was delivered.
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func (m *LogMailer) SendInvitation(_ context.Context, toEmail, householdName, inviterName, token, frontendURL string, expiresAt time.Time) error {
+	m.logger.Info("email (not sent, SMTP not configured)",
+		slog.String("kind", "invitation"),
+		slog.String("to", toEmail),
+		slog.String("household", householdName),
+		slog.String("inviter", inviterName),
+		slog.String("url", invitationURL(frontendURL, token)),
+		slog.Time("expires_at", expiresAt),
+	)
+	return ErrEmailDisabled
+}
 
-	addr := fmt.Sprintf("%s:%s", s.cfg.Host, s.cfg.Port)
-	return smtp.SendMail(addr, auth, s.cfg.From, []string{toEmail}, []byte(msg))
+func invitationURL(frontendURL, token string) string {
+	return fmt.Sprintf("%s/invite/%s", strings.TrimRight(frontendURL, "/"), token)
 }
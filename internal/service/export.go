@@ -9,91 +9,207 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
 
 	"github.com/howallet/howallet/internal/model"
 	"github.com/howallet/howallet/internal/repository"
 )
 
-// ExportService handles CSV export in Buxfer-compatible format.
+// utf8BOM makes Excel detect UTF-8 instead of the system code page.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Export encodings. Windows-1251 is for legacy Excel installs that ignore the BOM;
+// characters it cannot represent are replaced.
+const (
+	ExportEncodingUTF8        = "utf-8"
+	ExportEncodingWindows1251 = "windows-1251"
+)
+
+// ExportDateFormats maps the date formats clients may ask for to layouts.
+var ExportDateFormats = map[string]string{
+	"iso": time.DateOnly,
+	"us":  "01/02/2006",
+	"eu":  "02.01.2006",
+}
+
+// ExportFormat is a file format transactions can be exported in.
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"  // Buxfer-compatible, for spreadsheets
+	ExportFormatJSON ExportFormat = "json" // for scripts and other apps
+	ExportFormatOFX  ExportFormat = "ofx"  // for personal finance software
+)
+
+// ExportService handles CSV export in Buxfer-compatible format, plus JSON and OFX.
 type ExportService struct {
 	transactions repository.TransactionRepository
+	households   repository.HouseholdRepository
+	accounts     repository.AccountRepository
+}
+
+// ExportOptions tunes the CSV for the spreadsheet that will open it.
+type ExportOptions struct {
+	// BOM prefixes a UTF-8 byte-order mark; it is ignored for other encodings.
+	BOM bool
+	// Encoding is one of the ExportEncoding constants; empty means UTF-8.
+	Encoding string
+	// DateLayout formats dates, normally one of ExportDateFormats; empty means ISO.
+	DateLayout string
+	// DecimalComma writes amounts with a comma decimal separator and separates
+	// fields with semicolons, as spreadsheets in most European locales expect.
+	DecimalComma bool
 }
 
-func NewExportService(transactions repository.TransactionRepository) *ExportService {
-	return &ExportService{transactions: transactions}
+func NewExportService(transactions repository.TransactionRepository, households repository.HouseholdRepository, accounts repository.AccountRepository) *ExportService {
+	return &ExportService{transactions: transactions, households: households, accounts: accounts}
 }
 
-// ExportCSV writes Buxfer-format CSV to the given writer.
+// ExportCSV writes Buxfer-format CSV to the given writer, formatted per opts.
+// Rows are written as they are read from the database, so memory use doesn't
+// grow with the size of the export. Dates are calendar dates in the household's
+// timezone. Output that is still buffered when an error occurs is dropped
+// rather than flushed, so an early failure leaves w untouched.
 // Columns: Date,Description,Amount,Account,Tags,Type,Status,Currency
-func (s *ExportService) ExportCSV(ctx context.Context, w io.Writer, householdID uuid.UUID, from, to *time.Time) error {
-	rows, err := s.transactions.ListForExport(ctx, householdID, from, to)
+func (s *ExportService) ExportCSV(ctx context.Context, w io.Writer, householdID uuid.UUID, from, to *time.Time, opts ExportOptions) error {
+	loc, err := householdLocation(ctx, s.households, householdID)
 	if err != nil {
-		return fmt.Errorf("list transactions for export: %w", err)
+		return err
 	}
 
-	cw := csv.NewWriter(w)
-	defer cw.Flush()
+	out := w
+	utf8 := opts.Encoding == "" || opts.Encoding == ExportEncodingUTF8
+	var enc io.WriteCloser
+	if opts.Encoding == ExportEncodingWindows1251 {
+		enc = transform.NewWriter(w, encoding.ReplaceUnsupported(charmap.Windows1251.NewEncoder()))
+		out = enc
+	}
 
-	// Header
-	if err := cw.Write([]string{"Date", "Description", "Amount", "Account", "Tags", "Type", "Status", "Currency"}); err != nil {
-		return err
+	if opts.DateLayout == "" {
+		opts.DateLayout = time.DateOnly
 	}
 
-	for _, r := range rows {
-		txnType := string(r.Type)
-
-		if r.Type == model.TransactionTypeTransfer {
-			// Two rows for transfers (Buxfer convention)
-			// 1) Outgoing from source
-			if err := cw.Write([]string{
-				r.TransactedAt.Format("2006-01-02"),
-				r.Description,
-				r.Amount.Neg().StringFixed(2),
-				r.AccountName,
-				strings.Join(r.Tags, ", "),
-				txnType,
-				"cleared",
-				r.AccountCurrency,
-			}); err != nil {
-				return err
-			}
-			// 2) Incoming to destination
-			destName := ""
-			if r.DestinationAccountName != nil {
-				destName = *r.DestinationAccountName
-			}
-			if err := cw.Write([]string{
-				r.TransactedAt.Format("2006-01-02"),
-				r.Description,
-				r.Amount.StringFixed(2),
-				destName,
-				strings.Join(r.Tags, ", "),
-				txnType,
-				"cleared",
-				r.AccountCurrency,
-			}); err != nil {
+	cw := csv.NewWriter(out)
+	if opts.DecimalComma {
+		cw.Comma = ';'
+	}
+
+	// The preamble is written lazily so that a failing query leaves w untouched
+	// and the caller can still report the error.
+	started := false
+	start := func() error {
+		started = true
+		// Written straight to w before the csv.Writer buffers anything, so it
+		// always lands ahead of the header.
+		if opts.BOM && utf8 {
+			if _, err := w.Write(utf8BOM); err != nil {
 				return err
 			}
-		} else {
-			// Income or expense — single row
-			amt := r.Amount
-			if r.Type == model.TransactionTypeExpense {
-				amt = amt.Neg()
-			}
-			if err := cw.Write([]string{
-				r.TransactedAt.Format("2006-01-02"),
-				r.Description,
-				amt.StringFixed(2),
-				r.AccountName,
-				strings.Join(r.Tags, ", "),
-				txnType,
-				"cleared",
-				r.AccountCurrency,
-			}); err != nil {
+		}
+		return cw.Write([]string{"Date", "Description", "Amount", "Account", "Tags", "Type", "Status", "Currency"})
+	}
+
+	err = s.transactions.StreamForExport(ctx, householdID, from, to, func(r repository.ExportRow) error {
+		if !started {
+			if err := start(); err != nil {
 				return err
 			}
 		}
+		return writeExportRow(cw, r, loc, opts)
+	})
+	if err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+	if !started {
+		if err := start(); err != nil {
+			return err
+		}
 	}
 
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if enc != nil {
+		return enc.Close()
+	}
 	return nil
 }
+
+// householdLocation loads the household's timezone.
+func householdLocation(ctx context.Context, households repository.HouseholdRepository, householdID uuid.UUID) (*time.Location, error) {
+	hh, err := households.GetByID(ctx, householdID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	loc, err := time.LoadLocation(hh.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone %q: %w", hh.Timezone, err)
+	}
+	return loc, nil
+}
+
+// writeExportRow writes one transaction; transfers become two rows (Buxfer convention).
+func writeExportRow(cw *csv.Writer, r repository.ExportRow, loc *time.Location, opts ExportOptions) error {
+	txnType := string(r.Type)
+	date := r.TransactedAt.In(loc).Format(opts.DateLayout)
+
+	if r.Type == model.TransactionTypeTransfer {
+		// 1) Outgoing from source
+		if err := cw.Write([]string{
+			date,
+			r.Description,
+			formatExportAmount(r.Amount.Neg(), opts.DecimalComma),
+			r.AccountName,
+			strings.Join(r.Tags, ", "),
+			txnType,
+			"cleared",
+			r.AccountCurrency,
+		}); err != nil {
+			return err
+		}
+		// 2) Incoming to destination
+		destName := ""
+		if r.DestinationAccountName != nil {
+			destName = *r.DestinationAccountName
+		}
+		return cw.Write([]string{
+			date,
+			r.Description,
+			formatExportAmount(r.Amount, opts.DecimalComma),
+			destName,
+			strings.Join(r.Tags, ", "),
+			txnType,
+			"cleared",
+			r.AccountCurrency,
+		})
+	}
+
+	// Income or expense — single row
+	amt := r.Amount
+	if r.Type == model.TransactionTypeExpense {
+		amt = amt.Neg()
+	}
+	return cw.Write([]string{
+		date,
+		r.Description,
+		formatExportAmount(amt, opts.DecimalComma),
+		r.AccountName,
+		strings.Join(r.Tags, ", "),
+		txnType,
+		"cleared",
+		r.AccountCurrency,
+	})
+}
+
+// formatExportAmount writes an amount with two decimals and no grouping.
+func formatExportAmount(amt decimal.Decimal, decimalComma bool) string {
+	s := amt.StringFixed(2)
+	if decimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// exportJSONRow is one transaction in a JSON export. Unlike the CSV, a transfer
+// stays a single entry and the amount keeps its stored, unsigned value.
+type exportJSONRow struct {
+	ID                     uuid.UUID             `json:"id"`
+	TransactedAt           string                `json:"transacted_at"` // household timezone
+	Type                   model.TransactionType `json:"type"`
+	Description            string                `json:"description"`
+	Amount                 decimal.Decimal       `json:"amount"`
+	Currency               string                `json:"currency"`
+	AccountID              uuid.UUID             `json:"account_id"`
+	Account                string                `json:"account"`
+	DestinationAccountID   *uuid.UUID            `json:"destination_account_id,omitempty"`
+	DestinationAccountName *string               `json:"destination_account,omitempty"`
+	Tags                   []string              `json:"tags"`
+	Note                   *string               `json:"note,omitempty"`
+}
+
+// ExportJSON writes the transactions as a JSON array, streamed like ExportCSV:
+// rows go out as they are read and an early failure leaves w untouched.
+// dateLayout formats transacted_at, normally one of ExportDateFormats; empty
+// means a full RFC 3339 timestamp.
+func (s *ExportService) ExportJSON(ctx context.Context, w io.Writer, householdID uuid.UUID, from, to *time.Time, dateLayout string) error {
+	loc, err := householdLocation(ctx, s.households, householdID)
+	if err != nil {
+		return err
+	}
+	if dateLayout == "" {
+		dateLayout = time.RFC3339
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	sep := "["
+	err = s.transactions.StreamForExport(ctx, householdID, from, to, func(r repository.ExportRow) error {
+		if _, err := bw.WriteString(sep); err != nil {
+			return err
+		}
+		sep = ","
+		tags := r.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		return enc.Encode(exportJSONRow{
+			ID:                     r.ID,
+			TransactedAt:           r.TransactedAt.In(loc).Format(dateLayout),
+			Type:                   r.Type,
+			Description:            r.Description,
+			Amount:                 r.Amount,
+			Currency:               r.AccountCurrency,
+			AccountID:              r.AccountID,
+			Account:                r.AccountName,
+			DestinationAccountID:   r.DestinationAccountID,
+			DestinationAccountName: r.DestinationAccountName,
+			Tags:                   tags,
+			Note:                   r.Note,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+	if sep == "[" {
+		_, _ = bw.WriteString(sep)
+	}
+	if _, err := bw.WriteString("]\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// ofxHeader opens an OFX 2.2 (XML) file.
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+`
+
+// OFX field limits from the specification.
+const (
+	ofxMaxAcctID = 22
+	ofxMaxName   = 32
+)
+
+type ofxDocument struct {
+	XMLName    xml.Name               `xml:"OFX"`
+	SignOn     ofxSignOn              `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statements []ofxStatementResponse `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	DTServer string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxStatementResponse struct {
+	TrnUID    string       `xml:"TRNUID"`
+	Status    ofxStatus    `xml:"STATUS"`
+	Statement ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	CurDef    string      `xml:"CURDEF"`
+	Account   ofxAccount  `xml:"BANKACCTFROM"`
+	TranList  ofxTranList `xml:"BANKTRANLIST"`
+	LedgerBal ofxBalance  `xml:"LEDGERBAL"`
+}
+
+type ofxAccount struct {
+	BankID   string `xml:"BANKID"`
+	AcctID   string `xml:"ACCTID"`
+	AcctType string `xml:"ACCTTYPE"`
+}
+
+type ofxTranList struct {
+	DTStart      string           `xml:"DTSTART"`
+	DTEnd        string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+type ofxTransaction struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// ExportOFX writes an OFX 2.2 file with one bank statement per account, for
+// import into personal finance software. Statements need their transactions
+// grouped by account, so unlike the CSV and JSON exports the whole export is
+// held in memory before anything is written. A transfer shows up in both
+// accounts' statements; ledger balances are the accounts' current ones.
+func (s *ExportService) ExportOFX(ctx context.Context, w io.Writer, householdID uuid.UUID, from, to *time.Time) error {
+	accounts, err := s.accounts.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return fmt.Errorf("list accounts: %w", err)
+	}
+
+	now := time.Now()
+	doc := ofxDocument{SignOn: ofxSignOn{DTServer: ofxTime(now), Language: "ENG"}}
+	statements := make(map[uuid.UUID]*ofxStatement, len(accounts))
+	for i, acc := range accounts {
+		balance := acc.Balance
+		if acc.IsLiability {
+			balance = balance.Neg() // what is owed is a negative balance
+		}
+		doc.Statements = append(doc.Statements, ofxStatementResponse{
+			TrnUID: fmt.Sprint(i + 1),
+			Statement: ofxStatement{
+				CurDef:    acc.Currency,
+				Account:   ofxAccount{BankID: "hoWallet", AcctID: ofxAccountID(acc.ID), AcctType: ofxAccountType(acc)},
+				LedgerBal: ofxBalance{BalAmt: balance.StringFixed(2), DTAsOf: ofxTime(now)},
+			},
+		})
+	}
+	for i := range doc.Statements {
+		statements[accounts[i].ID] = &doc.Statements[i].Statement
+	}
+
+	add := func(accountID uuid.UUID, t ofxTransaction, at time.Time) {
+		st, ok := statements[accountID]
+		if !ok {
+			return
+		}
+		st.TranList.Transactions = append(st.TranList.Transactions, t)
+		if posted := ofxTime(at); st.TranList.DTStart == "" || posted < st.TranList.DTStart {
+			st.TranList.DTStart = posted
+		}
+		if posted := ofxTime(at); posted > st.TranList.DTEnd {
+			st.TranList.DTEnd = posted
+		}
+	}
+	err = s.transactions.StreamForExport(ctx, householdID, from, to, func(r repository.ExportRow) error {
+		t := ofxTransaction{
+			DTPosted: ofxTime(r.TransactedAt),
+			FITID:    r.ID.String(),
+			Name:     truncateRunes(r.Description, ofxMaxName),
+		}
+		if r.Note != nil {
+			t.Memo = *r.Note
+		}
+		switch r.Type {
+		case model.TransactionTypeIncome:
+			t.TrnType, t.TrnAmt = "CREDIT", r.Amount.StringFixed(2)
+		case model.TransactionTypeExpense:
+			t.TrnType, t.TrnAmt = "DEBIT", r.Amount.Neg().StringFixed(2)
+		case model.TransactionTypeTransfer:
+			t.TrnType, t.TrnAmt = "XFER", r.Amount.Neg().StringFixed(2)
+			if r.DestinationAccountID != nil {
+				in := t
+				in.TrnAmt = r.Amount.StringFixed(2)
+				add(*r.DestinationAccountID, in, r.TransactedAt)
+			}
+		}
+		add(r.AccountID, t, r.TransactedAt)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+
+	// The requested range wins over the span of the transactions found.
+	for i := range doc.Statements {
+		list := &doc.Statements[i].Statement.TranList
+		if from != nil {
+			list.DTStart = ofxTime(*from)
+		}
+		if to != nil {
+			list.DTEnd = ofxTime(*to)
+		}
+		if list.DTStart == "" {
+			list.DTStart, list.DTEnd = ofxTime(now), ofxTime(now)
+		}
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+// ofxTime formats t as an OFX datetime in UTC.
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// ofxAccountID shortens an account ID to the 22 characters OFX allows; the
+// first 88 bits of a UUID are still unique within a household.
+func ofxAccountID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")[:ofxMaxAcctID]
+}
+
+// ofxAccountType maps an account to the closest OFX bank account type.
+func ofxAccountType(acc model.Account) string {
+	switch {
+	case acc.IsLiability:
+		return "CREDITLINE"
+	case acc.Type == model.AccountTypeDeposit:
+		return "SAVINGS"
+	default:
+		return "CHECKING"
+	}
+}
+
+// truncateRunes cuts s to at most n characters.
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
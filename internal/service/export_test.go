@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// newExportFixture returns an export service over a household in Kyiv time
+// with rows as its transactions.
+func newExportFixture(rows []repository.ExportRow) (*ExportService, uuid.UUID, *fakes) {
+	f := newFakes()
+	hh := uuid.New()
+	f.households.byID[hh] = model.Household{ID: hh, Timezone: "Europe/Kyiv"}
+	f.transactions.exportRows = rows
+	return NewExportService(f.transactions, f.households, f.accounts), hh, f
+}
+
+func exportRow(description string, at time.Time) repository.ExportRow {
+	return repository.ExportRow{
+		ID: uuid.New(), TransactedAt: at, Description: description, Amount: decimal.RequireFromString("12.50"),
+		Type: model.TransactionTypeExpense, AccountID: uuid.New(), AccountName: "Картка", AccountCurrency: "UAH",
+	}
+}
+
+func TestExportCSVBOM(t *testing.T) {
+	const header = "Date,Description,Amount,Account,Tags,Type,Status,Currency\n"
+	rows := []repository.ExportRow{exportRow("Кава", time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))}
+	tests := []struct {
+		name       string
+		opts       ExportOptions
+		rows       []repository.ExportRow
+		wantPrefix []byte
+	}{
+		{"bom", ExportOptions{BOM: true}, rows, append(append([]byte{}, utf8BOM...), header...)},
+		{"bom on empty export", ExportOptions{BOM: true}, nil, append(append([]byte{}, utf8BOM...), header...)},
+		{"no bom", ExportOptions{}, rows, []byte(header)},
+		{"bom ignored for windows-1251", ExportOptions{BOM: true, Encoding: ExportEncodingWindows1251}, rows, []byte(header)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, hh, _ := newExportFixture(tt.rows)
+			var out bytes.Buffer
+			if err := svc.ExportCSV(context.Background(), &out, hh, nil, nil, tt.opts); err != nil {
+				t.Fatalf("ExportCSV: %v", err)
+			}
+			if !bytes.HasPrefix(out.Bytes(), tt.wantPrefix) {
+				t.Fatalf("export starts %q, want %q", out.Bytes()[:min(out.Len(), len(tt.wantPrefix))], tt.wantPrefix)
+			}
+			if bytes.Count(out.Bytes(), utf8BOM) > 1 {
+				t.Errorf("BOM written more than once")
+			}
+		})
+	}
+}
+
+// firstWriteRecorder notes how many rows had been read from the database when
+// the first bytes reached the client.
+type firstWriteRecorder struct {
+	bytes.Buffer
+	f           *fakes
+	rowsAtFirst int
+}
+
+func (w *firstWriteRecorder) Write(p []byte) (int, error) {
+	if w.Len() == 0 {
+		w.rowsAtFirst = w.f.transactions.exported
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestExportCSVStreamsLargeExports(t *testing.T) {
+	const n = 5000
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	rows := make([]repository.ExportRow, n)
+	transfers := 0
+	for i := range rows {
+		rows[i] = exportRow(fmt.Sprintf("Row %d", i), start.Add(time.Duration(i)*time.Hour))
+		if i%10 == 0 {
+			dest, name := uuid.New(), "Savings"
+			rows[i].Type = model.TransactionTypeTransfer
+			rows[i].DestinationAccountID, rows[i].DestinationAccountName = &dest, &name
+			transfers++
+		}
+	}
+	svc, hh, f := newExportFixture(rows)
+
+	out := &firstWriteRecorder{f: f}
+	if err := svc.ExportCSV(context.Background(), out, hh, nil, nil, ExportOptions{}); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(out.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("parse export: %v", err)
+	}
+	// A header, a row per transaction and a second row per transfer.
+	if want := 1 + n + transfers; len(records) != want {
+		t.Fatalf("%d CSV records, want %d", len(records), want)
+	}
+	if got := records[len(records)-1][1]; !strings.HasPrefix(got, fmt.Sprintf("Row %d", n-1)) {
+		t.Errorf("last record describes %q, want the last row", got)
+	}
+	if out.rowsAtFirst == 0 || out.rowsAtFirst >= n {
+		t.Errorf("first write after %d of %d rows, want output to start before the query finishes", out.rowsAtFirst, n)
+	}
+}
+
+func TestExportCSVDayBoundary(t *testing.T) {
+	// Kyiv is UTC+2 in winter, so local midnight on 1 February is 22:00 UTC.
+	rows := []repository.ExportRow{
+		exportRow("before midnight", time.Date(2024, 1, 31, 21, 59, 0, 0, time.UTC)),
+		exportRow("after midnight", time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC)),
+	}
+	svc, hh, _ := newExportFixture(rows)
+	var out bytes.Buffer
+	if err := svc.ExportCSV(context.Background(), &out, hh, nil, nil, ExportOptions{}); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := map[string]string{"before midnight": "2024-01-31", "after midnight": "2024-02-01"}
+	if len(records) != len(want)+1 {
+		t.Fatalf("got %d records, want %d", len(records), len(want)+1)
+	}
+	for _, rec := range records[1:] {
+		if rec[0] != want[rec[1]] {
+			t.Errorf("%s: date %s, want %s", rec[1], rec[0], want[rec[1]])
+		}
+	}
+}
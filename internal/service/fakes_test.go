@@ -0,0 +1,552 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// In-memory fakes for service tests. Each embeds its repository interface, so
+// calling a method a fake doesn't implement panics and points at what a test
+// is missing.
+
+// fakeUnitOfWork runs fn against the same fakes, then fails the commit with
+// commitErr if set. When fn or the commit fails, the fakes are rolled back to
+// where they were before fn ran.
+type fakeUnitOfWork struct {
+	repos     *repository.Repos
+	snapshot  func() (restore func())
+	commitErr error
+}
+
+func (u *fakeUnitOfWork) RunInTx(ctx context.Context, fn repository.TxFunc) error {
+	restore := u.snapshot()
+	if err := fn(repository.WithTxRepos(ctx, u.repos)); err != nil {
+		restore()
+		return err
+	}
+	if u.commitErr != nil {
+		restore()
+		return u.commitErr
+	}
+	return nil
+}
+
+type fakeAccounts struct {
+	repository.AccountRepository
+	byID map[uuid.UUID]*model.Account
+}
+
+func (f *fakeAccounts) GetByID(_ context.Context, id, householdID uuid.UUID) (model.Account, error) {
+	acc, ok := f.byID[id]
+	if !ok || acc.HouseholdID != householdID {
+		return model.Account{}, pgx.ErrNoRows
+	}
+	return *acc, nil
+}
+
+func (f *fakeAccounts) GetForUpdate(ctx context.Context, id, householdID uuid.UUID) (model.Account, error) {
+	return f.GetByID(ctx, id, householdID)
+}
+
+func (f *fakeAccounts) UpdateBalance(_ context.Context, id uuid.UUID, delta decimal.Decimal) error {
+	acc, ok := f.byID[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	if acc.IsLiability {
+		delta = delta.Neg()
+	}
+	acc.Balance = acc.Balance.Add(delta)
+	return nil
+}
+
+func (f *fakeAccounts) NameExists(_ context.Context, householdID uuid.UUID, name string) (bool, error) {
+	for _, acc := range f.byID {
+		if acc.HouseholdID == householdID && acc.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeAccounts) Create(_ context.Context, p repository.CreateAccountParams) (model.Account, error) {
+	acc := &model.Account{
+		ID: uuid.New(), HouseholdID: p.HouseholdID, Name: p.Name, Type: p.Type, Balance: p.Balance,
+		Currency: p.Currency, CreatedBy: p.CreatedBy, IncludeInTotals: p.IncludeInTotals,
+		IsLiability: p.IsLiability, DailyLimit: p.DailyLimit,
+	}
+	f.byID[acc.ID] = acc
+	return *acc, nil
+}
+
+// fakeAccountTypes holds custom type names per household.
+type fakeAccountTypes struct {
+	repository.AccountTypeRepository
+	names    map[uuid.UUID][]string
+	accounts *fakeAccounts
+}
+
+func (f *fakeAccountTypes) Exists(_ context.Context, householdID uuid.UUID, name string) (bool, error) {
+	return slices.Contains(f.names[householdID], name), nil
+}
+
+func (f *fakeAccountTypes) Delete(_ context.Context, householdID uuid.UUID, name string) (bool, error) {
+	i := slices.Index(f.names[householdID], name)
+	if i < 0 {
+		return false, nil
+	}
+	f.names[householdID] = slices.Delete(f.names[householdID], i, i+1)
+	return true, nil
+}
+
+// CountAccounts counts the fake accounts of the type.
+func (f *fakeAccountTypes) CountAccounts(_ context.Context, householdID uuid.UUID, name string) (int64, error) {
+	var n int64
+	for _, acc := range f.accounts.byID {
+		if acc.HouseholdID == householdID && string(acc.Type) == name {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeTransactions struct {
+	repository.TransactionRepository
+	byID    map[uuid.UUID]*model.Transaction
+	renamed [][2]string
+	// exportRows are what StreamForExport yields, whatever the filters;
+	// exported counts the rows it has handed out so far.
+	exportRows []repository.ExportRow
+	exported   int
+	// dailyTotals are what SumByDay returns; sumByDay records its params.
+	dailyTotals []model.DailyTotal
+	sumByDay    []repository.SumByDayParams
+}
+
+func (f *fakeTransactions) GetByID(_ context.Context, id, householdID uuid.UUID) (model.Transaction, error) {
+	txn, ok := f.byID[id]
+	if !ok || txn.HouseholdID != householdID {
+		return model.Transaction{}, pgx.ErrNoRows
+	}
+	return *txn, nil
+}
+
+func (f *fakeTransactions) Update(_ context.Context, p repository.UpdateTransactionParams) (model.Transaction, error) {
+	txn, ok := f.byID[p.ID]
+	if !ok || txn.HouseholdID != p.HouseholdID {
+		return model.Transaction{}, pgx.ErrNoRows
+	}
+	txn.Type, txn.Description, txn.Amount, txn.AccountID = p.Type, p.Description, p.Amount, p.AccountID
+	txn.DestinationAccountID, txn.Tags, txn.Note, txn.TransactedAt = p.DestinationAccountID, p.Tags, p.Note, p.TransactedAt
+	txn.Posted = p.Posted
+	return *txn, nil
+}
+
+func (f *fakeTransactions) Delete(_ context.Context, id, householdID uuid.UUID) (model.Transaction, error) {
+	txn, ok := f.byID[id]
+	if !ok || txn.HouseholdID != householdID {
+		return model.Transaction{}, pgx.ErrNoRows
+	}
+	delete(f.byID, id)
+	return *txn, nil
+}
+
+func (f *fakeTransactions) Create(_ context.Context, p repository.CreateTransactionParams) (model.Transaction, error) {
+	txn := &model.Transaction{
+		ID: uuid.New(), HouseholdID: p.HouseholdID, Type: p.Type, Description: p.Description,
+		Amount: p.Amount, AccountID: p.AccountID, DestinationAccountID: p.DestinationAccountID,
+		Tags: p.Tags, Note: p.Note, TransactedAt: p.TransactedAt, Posted: p.Posted, CreatedBy: p.CreatedBy,
+	}
+	f.byID[txn.ID] = txn
+	return *txn, nil
+}
+
+// RenameTag records the rename and reports one changed transaction.
+func (f *fakeTransactions) RenameTag(_ context.Context, _ uuid.UUID, from, to string) (int64, error) {
+	f.renamed = append(f.renamed, [2]string{from, to})
+	return 1, nil
+}
+
+func (f *fakeTransactions) StreamForExport(_ context.Context, _ uuid.UUID, _, _ *time.Time, fn func(repository.ExportRow) error) error {
+	for _, r := range f.exportRows {
+		f.exported++
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeTransactions) SumByDay(_ context.Context, p repository.SumByDayParams) ([]model.DailyTotal, error) {
+	f.sumByDay = append(f.sumByDay, p)
+	return f.dailyTotals, nil
+}
+
+func (f *fakeTransactions) Restore(_ context.Context, txn model.Transaction) (model.Transaction, error) {
+	txn.Splits = nil
+	f.byID[txn.ID] = &txn
+	return txn, nil
+}
+
+func (f *fakeTransactions) CreateSplit(_ context.Context, transactionID uuid.UUID, p repository.CreateSplitParams) (model.TransactionSplit, error) {
+	sp := model.TransactionSplit{ID: uuid.New(), CategoryID: p.CategoryID, Amount: p.Amount}
+	txn := f.byID[transactionID]
+	txn.Splits = append(txn.Splits, sp)
+	return sp, nil
+}
+
+func (f *fakeTransactions) ListSplits(context.Context, uuid.UUID) ([]model.TransactionSplit, error) {
+	return nil, nil
+}
+
+type fakeAudit struct {
+	repository.AuditRepository
+	entries []repository.CreateAuditEntryParams
+	// undoable is what GetLastUndoable returns, if set; undone lists the
+	// entries marked undone.
+	undoable *model.AuditEntry
+	undone   []uuid.UUID
+}
+
+func (f *fakeAudit) Create(_ context.Context, params repository.CreateAuditEntryParams) (model.AuditEntry, error) {
+	f.entries = append(f.entries, params)
+	return model.AuditEntry{ID: uuid.New(), HouseholdID: params.HouseholdID, Action: params.Action}, nil
+}
+
+func (f *fakeAudit) GetLastUndoable(context.Context, uuid.UUID, uuid.UUID, time.Time) (model.AuditEntry, error) {
+	if f.undoable == nil {
+		return model.AuditEntry{}, pgx.ErrNoRows
+	}
+	return *f.undoable, nil
+}
+
+func (f *fakeAudit) MarkUndone(_ context.Context, id uuid.UUID) error {
+	f.undone = append(f.undone, id)
+	return nil
+}
+
+type fakeCategories struct {
+	repository.CategoryRepository
+	byHousehold map[uuid.UUID][]uuid.UUID
+}
+
+func (f *fakeCategories) CountInHousehold(_ context.Context, householdID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var n int64
+	for _, id := range ids {
+		if slices.Contains(f.byHousehold[householdID], id) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeHouseholds struct {
+	repository.HouseholdRepository
+	byID    map[uuid.UUID]model.Household
+	members map[uuid.UUID]map[uuid.UUID]model.HouseholdRole
+}
+
+func (f *fakeHouseholds) Create(_ context.Context, p repository.CreateHouseholdParams) (model.Household, error) {
+	hh := model.Household{ID: uuid.New(), Name: p.Name, Timezone: p.Timezone, DefaultCurrency: p.DefaultCurrency}
+	f.byID[hh.ID] = hh
+	return hh, nil
+}
+
+func (f *fakeHouseholds) AddMember(_ context.Context, householdID, userID uuid.UUID, role model.HouseholdRole) error {
+	if f.members[householdID] == nil {
+		f.members[householdID] = map[uuid.UUID]model.HouseholdRole{}
+	}
+	f.members[householdID][userID] = role
+	return nil
+}
+
+func (f *fakeHouseholds) GetMember(_ context.Context, householdID, userID uuid.UUID) (model.HouseholdMember, error) {
+	role, ok := f.members[householdID][userID]
+	if !ok {
+		return model.HouseholdMember{}, pgx.ErrNoRows
+	}
+	return model.HouseholdMember{HouseholdID: householdID, UserID: userID, Role: role}, nil
+}
+
+func (f *fakeHouseholds) IsMember(_ context.Context, householdID, userID uuid.UUID) (bool, error) {
+	_, ok := f.members[householdID][userID]
+	return ok, nil
+}
+
+func (f *fakeHouseholds) GetByID(_ context.Context, id uuid.UUID) (model.Household, error) {
+	hh, ok := f.byID[id]
+	if !ok {
+		return model.Household{}, pgx.ErrNoRows
+	}
+	return hh, nil
+}
+
+type fakeUsers struct {
+	repository.UserRepository
+	byID map[uuid.UUID]model.User
+	// createErr, if set, fails Create, e.g. with a unique violation from a
+	// concurrent registration.
+	createErr error
+}
+
+func (f *fakeUsers) Create(_ context.Context, email, passwordHash, name string) (model.User, error) {
+	if f.createErr != nil {
+		return model.User{}, f.createErr
+	}
+	u := model.User{ID: uuid.New(), Email: email, PasswordHash: passwordHash, Name: name}
+	f.byID[u.ID] = u
+	return u, nil
+}
+
+func (f *fakeUsers) GetByID(_ context.Context, id uuid.UUID) (model.User, error) {
+	u, ok := f.byID[id]
+	if !ok {
+		return model.User{}, pgx.ErrNoRows
+	}
+	return u, nil
+}
+
+func (f *fakeUsers) Update(_ context.Context, id uuid.UUID, name, email *string) (model.User, error) {
+	u, ok := f.byID[id]
+	if !ok {
+		return model.User{}, pgx.ErrNoRows
+	}
+	if name != nil {
+		u.Name = *name
+	}
+	if email != nil {
+		u.Email = *email
+	}
+	f.byID[id] = u
+	return u, nil
+}
+
+func (f *fakeUsers) GetByEmail(_ context.Context, email string) (model.User, error) {
+	for _, u := range f.byID {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return model.User{}, pgx.ErrNoRows
+}
+
+type fakeRefreshTokens struct {
+	repository.RefreshTokenRepository
+	byHash map[string]*repository.RefreshTokenRow
+}
+
+func (f *fakeRefreshTokens) Create(_ context.Context, p repository.CreateRefreshTokenParams) (uuid.UUID, error) {
+	now := time.Now()
+	row := &repository.RefreshTokenRow{
+		ID: uuid.New(), UserID: p.UserID, TokenHash: p.TokenHash, ExpiresAt: p.ExpiresAt,
+		CreatedAt: now, LastUsedAt: now, FamilyID: p.FamilyID,
+	}
+	f.byHash[p.TokenHash] = row
+	return row.ID, nil
+}
+
+func (f *fakeRefreshTokens) GetByHash(_ context.Context, tokenHash string) (repository.RefreshTokenRow, error) {
+	row, ok := f.byHash[tokenHash]
+	if !ok {
+		return repository.RefreshTokenRow{}, pgx.ErrNoRows
+	}
+	return *row, nil
+}
+
+func (f *fakeRefreshTokens) MarkUsed(_ context.Context, id, replacedBy uuid.UUID) (bool, error) {
+	for _, row := range f.byHash {
+		if row.ID == id {
+			if row.UsedAt != nil {
+				return false, nil
+			}
+			now := time.Now()
+			row.UsedAt, row.ReplacedBy = &now, &replacedBy
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRefreshTokens) DeleteByHash(_ context.Context, tokenHash string, userID uuid.UUID) (bool, error) {
+	row, ok := f.byHash[tokenHash]
+	if !ok || row.UserID != userID {
+		return false, nil
+	}
+	delete(f.byHash, tokenHash)
+	return true, nil
+}
+
+func (f *fakeRefreshTokens) DeleteFamily(_ context.Context, familyID uuid.UUID) error {
+	for h, row := range f.byHash {
+		if row.FamilyID == familyID {
+			delete(f.byHash, h)
+		}
+	}
+	return nil
+}
+
+type fakeInvitations struct {
+	repository.InvitationRepository
+	byToken map[string]*model.Invitation
+	// listLimits records the limit of each ListPendingByHousehold call.
+	listLimits []int32
+}
+
+func (f *fakeInvitations) ListPendingByHousehold(_ context.Context, householdID uuid.UUID, limit, offset int32) ([]model.Invitation, error) {
+	f.listLimits = append(f.listLimits, limit)
+	out := []model.Invitation{}
+	for _, inv := range f.byToken {
+		if inv.HouseholdID == householdID && inv.Status == model.InvitationStatusPending {
+			out = append(out, *inv)
+		}
+	}
+	return out[min(int(offset), len(out)):min(int(offset+limit), len(out))], nil
+}
+
+func (f *fakeInvitations) CountPendingByHousehold(_ context.Context, householdID uuid.UUID) (int64, error) {
+	var n int64
+	for _, inv := range f.byToken {
+		if inv.HouseholdID == householdID && inv.Status == model.InvitationStatusPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeInvitations) GetByToken(_ context.Context, token string) (model.Invitation, error) {
+	inv, ok := f.byToken[token]
+	if !ok {
+		return model.Invitation{}, pgx.ErrNoRows
+	}
+	return *inv, nil
+}
+
+func (f *fakeInvitations) Accept(_ context.Context, id uuid.UUID) (bool, error) {
+	for _, inv := range f.byToken {
+		if inv.ID == id && inv.Status == model.InvitationStatusPending {
+			inv.Status = model.InvitationStatusAccepted
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakes bundles the fakes behind a Repos.
+type fakes struct {
+	repos        *repository.Repos
+	uow          *fakeUnitOfWork
+	accounts     *fakeAccounts
+	accountTypes *fakeAccountTypes
+	transactions *fakeTransactions
+	audit        *fakeAudit
+	households   *fakeHouseholds
+	users        *fakeUsers
+	tokens       *fakeRefreshTokens
+	invitations  *fakeInvitations
+	categories   *fakeCategories
+}
+
+func newFakes() *fakes {
+	f := &fakes{
+		accounts:     &fakeAccounts{byID: map[uuid.UUID]*model.Account{}},
+		accountTypes: &fakeAccountTypes{names: map[uuid.UUID][]string{}},
+		transactions: &fakeTransactions{byID: map[uuid.UUID]*model.Transaction{}},
+		audit:        &fakeAudit{},
+		households:   &fakeHouseholds{byID: map[uuid.UUID]model.Household{}, members: map[uuid.UUID]map[uuid.UUID]model.HouseholdRole{}},
+		users:        &fakeUsers{byID: map[uuid.UUID]model.User{}},
+		tokens:       &fakeRefreshTokens{byHash: map[string]*repository.RefreshTokenRow{}},
+		invitations:  &fakeInvitations{byToken: map[string]*model.Invitation{}},
+		categories:   &fakeCategories{byHousehold: map[uuid.UUID][]uuid.UUID{}},
+	}
+	f.accountTypes.accounts = f.accounts
+	f.uow = &fakeUnitOfWork{snapshot: f.snapshot}
+	f.repos = &repository.Repos{
+		UnitOfWork:    f.uow,
+		Accounts:      f.accounts,
+		AccountTypes:  f.accountTypes,
+		Transactions:  f.transactions,
+		Audit:         f.audit,
+		Households:    f.households,
+		Users:         f.users,
+		RefreshTokens: f.tokens,
+		Invitations:   f.invitations,
+		Categories:    f.categories,
+	}
+	f.uow.repos = f.repos
+	return f
+}
+
+// snapshot records the state the service can change inside a transaction and
+// returns a func that puts it back. Rows held by pointer are restored in place,
+// so a test holding one sees the rollback too.
+func (f *fakes) snapshot() (restore func()) {
+	restores := []func(){
+		snapshotRows(f.accounts.byID),
+		snapshotRows(f.transactions.byID),
+		snapshotRows(f.tokens.byHash),
+		snapshotRows(f.invitations.byToken),
+	}
+	users := maps.Clone(f.users.byID)
+	households := maps.Clone(f.households.byID)
+	members := make(map[uuid.UUID]map[uuid.UUID]model.HouseholdRole, len(f.households.members))
+	for hh, m := range f.households.members {
+		members[hh] = maps.Clone(m)
+	}
+	categories := maps.Clone(f.categories.byHousehold)
+	typeNames := maps.Clone(f.accountTypes.names)
+	nEntries, nUndone, nRenamed := len(f.audit.entries), len(f.audit.undone), len(f.transactions.renamed)
+	return func() {
+		for _, r := range restores {
+			r()
+		}
+		f.users.byID = users
+		f.households.byID = households
+		f.households.members = members
+		f.categories.byHousehold = categories
+		f.accountTypes.names = typeNames
+		f.audit.entries = f.audit.entries[:nEntries]
+		f.audit.undone = f.audit.undone[:nUndone]
+		f.transactions.renamed = f.transactions.renamed[:nRenamed]
+	}
+}
+
+func snapshotRows[K comparable, V any](m map[K]*V) (restore func()) {
+	type row struct {
+		ptr *V
+		val V
+	}
+	saved := make(map[K]row, len(m))
+	for k, p := range m {
+		saved[k] = row{p, *p}
+	}
+	return func() {
+		clear(m)
+		for k, r := range saved {
+			*r.ptr = r.val
+			m[k] = r.ptr
+		}
+	}
+}
+
+// addAccount stores an asset account with the given balance.
+func (f *fakes) addAccount(householdID uuid.UUID, currency string, balance int64) *model.Account {
+	acc := &model.Account{
+		ID:          uuid.New(),
+		HouseholdID: householdID,
+		Name:        "Account",
+		Type:        model.AccountTypeCard,
+		Currency:    currency,
+		Balance:     decimal.NewFromInt(balance),
+	}
+	f.accounts.byID[acc.ID] = acc
+	return acc
+}
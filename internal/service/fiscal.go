@@ -0,0 +1,27 @@
+package service
+
+import "time"
+
+// fiscalMonth returns the fiscal month containing t, as the half-open range
+// [from, to) in loc, for a household whose months start on startDay. A start
+// day a month doesn't have falls on that month's last day: with startDay 31,
+// the month starting January 31 ends when the one starting February 28 (or 29)
+// begins.
+func fiscalMonth(t time.Time, loc *time.Location, startDay int) (from, to time.Time) {
+	local := t.In(loc)
+	year, month := local.Year(), local.Month()
+	from = fiscalMonthStart(year, month, startDay, loc)
+	if local.Before(from) {
+		month--
+		from = fiscalMonthStart(year, month, startDay, loc)
+	}
+	return from, fiscalMonthStart(year, month+1, startDay, loc)
+}
+
+// fiscalMonthStart is the midnight the fiscal month anchored in year and month
+// begins. Month may be out of range; time.Date normalizes it.
+func fiscalMonthStart(year int, month time.Month, startDay int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(max(startDay, 1), lastDay)-1)
+}
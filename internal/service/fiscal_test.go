@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestFiscalMonth(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		at       time.Time
+		loc      *time.Location
+		startDay int
+		from, to string
+	}{
+		{"start day 1", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), time.UTC, 1, "2024-03-01", "2024-04-01"},
+		{"31st, leap February before its last day", time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2024-01-31", "2024-02-29"},
+		{"31st, leap February on its last day", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2024-02-29", "2024-03-31"},
+		{"31st, February before its last day", time.Date(2023, 2, 27, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2023-01-31", "2023-02-28"},
+		{"31st, February on its last day", time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2023-02-28", "2023-03-31"},
+		{"31st, March before the 31st", time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2023-02-28", "2023-03-31"},
+		{"30th, leap February", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), time.UTC, 30, "2024-02-29", "2024-03-30"},
+		{"30th, February before its last day", time.Date(2023, 2, 27, 0, 0, 0, 0, time.UTC), time.UTC, 30, "2023-01-30", "2023-02-28"},
+		{"30th, early March", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), time.UTC, 30, "2023-02-28", "2023-03-30"},
+		{"before the start day in January", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), time.UTC, 25, "2023-12-25", "2024-01-25"},
+		{"after the start day in December", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.UTC, 25, "2023-12-25", "2024-01-25"},
+		{"31st, January 1st", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC, 31, "2023-12-31", "2024-01-31"},
+		// 23:30 UTC on January 31 is already February 1 in Kyiv.
+		{"date taken in the household's timezone", time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC), kyiv, 1, "2024-02-01", "2024-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := fiscalMonth(tt.at, tt.loc, tt.startDay)
+			if from.Location() != tt.loc || to.Location() != tt.loc {
+				t.Errorf("range in %s and %s, want %s", from.Location(), to.Location(), tt.loc)
+			}
+			if !isMidnight(from) || !isMidnight(to) {
+				t.Errorf("range [%s, %s) doesn't start and end at midnight", from, to)
+			}
+			if got := from.Format(time.DateOnly); got != tt.from {
+				t.Errorf("from = %s, want %s", got, tt.from)
+			}
+			if got := to.Format(time.DateOnly); got != tt.to {
+				t.Errorf("to = %s, want %s", got, tt.to)
+			}
+		})
+	}
+}
+
+func isMidnight(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h == 0 && m == 0 && s == 0 && t.Nanosecond() == 0
+}
+
+func TestMonthlyFiscalBoundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		startDay int
+		want     [][2]string // start and end of each month
+	}{
+		{
+			"31st across leap February",
+			time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 31,
+			[][2]string{{"2024-01-31", "2024-02-28"}, {"2024-02-29", "2024-03-30"}},
+		},
+		{
+			"31st across February",
+			time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC), 31,
+			[][2]string{{"2023-01-31", "2023-02-27"}, {"2023-02-28", "2023-03-30"}},
+		},
+		{
+			"30th across February",
+			time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC), 30,
+			[][2]string{{"2023-01-30", "2023-02-27"}, {"2023-02-28", "2023-03-29"}, {"2023-03-30", "2023-04-29"}},
+		},
+		{
+			"across the new year",
+			time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 25,
+			[][2]string{{"2023-11-25", "2023-12-24"}, {"2023-12-25", "2024-01-24"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.households.byID[hh] = model.Household{ID: hh, Timezone: "Europe/Kyiv", DefaultCurrency: "UAH", FiscalMonthStartDay: tt.startDay}
+			svc := NewReportService(f.repos)
+
+			report, err := svc.Monthly(context.Background(), hh, tt.from, tt.to, "")
+			if err != nil {
+				t.Fatalf("Monthly: %v", err)
+			}
+			if len(report.Months) != len(tt.want) {
+				t.Fatalf("got %d months, want %d: %+v", len(report.Months), len(tt.want), report.Months)
+			}
+			for i, m := range report.Months {
+				if m.Start != tt.want[i][0] || m.End != tt.want[i][1] {
+					t.Errorf("month %d = %s..%s, want %s..%s", i, m.Start, m.End, tt.want[i][0], tt.want[i][1])
+				}
+			}
+
+			// The query covers exactly those months, from local midnight to
+			// local midnight.
+			loc, _ := time.LoadLocation("Europe/Kyiv")
+			first, _ := time.ParseInLocation(time.DateOnly, tt.want[0][0], loc)
+			last, _ := time.ParseInLocation(time.DateOnly, tt.want[len(tt.want)-1][1], loc)
+			p := f.transactions.sumByDay[0]
+			if !p.From.Equal(first) || !p.To.Equal(last.AddDate(0, 0, 1)) {
+				t.Errorf("queried [%s, %s), want [%s, %s)", p.From, p.To, first, last.AddDate(0, 0, 1))
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/howallet/howallet/internal/config"
+)
+
+// errUnknownHash means a stored hash is in no format the hashers recognise.
+var errUnknownHash = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords and checks them against stored hashes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash, and whether hash should be
+	// replaced because it was made with another algorithm or weaker parameters.
+	Verify(hash, password string) (ok, rehash bool, err error)
+}
+
+// NewPasswordHasher hashes with the configured algorithm and verifies both
+// bcrypt and argon2id hashes, telling them apart by their prefix.
+func NewPasswordHasher(cfg config.PasswordConfig) PasswordHasher {
+	h := &multiHasher{
+		bcrypt: bcryptHasher{cost: cfg.BcryptCost},
+		argon2: argon2Hasher{time: cfg.Argon2Time, memory: cfg.Argon2MemoryKiB, threads: cfg.Argon2Threads},
+	}
+	h.primary = h.bcrypt
+	if cfg.Hash == config.PasswordHashArgon2id {
+		h.primary = h.argon2
+	}
+	return h
+}
+
+type multiHasher struct {
+	primary PasswordHasher
+	bcrypt  bcryptHasher
+	argon2  argon2Hasher
+}
+
+func (h *multiHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+func (h *multiHasher) Verify(hash, password string) (bool, bool, error) {
+	var own PasswordHasher
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		own = h.argon2
+	case strings.HasPrefix(hash, "$2"):
+		own = h.bcrypt
+	default:
+		return false, false, errUnknownHash
+	}
+	ok, rehash, err := own.Verify(hash, password)
+	return ok, rehash || own != h.primary, err
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (h bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(hash), err
+}
+
+func (h bcryptHasher) Verify(hash, password string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err == nil && cost < h.cost, nil
+}
+
+// argon2idPrefix starts hashes in the PHC string format:
+// $argon2id$v=19$m=<KiB>,t=<time>,p=<threads>$<salt>$<key>, base64 without padding.
+const argon2idPrefix = "$argon2id$"
+
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+type argon2Hasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func (h argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, argon2KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		h.memory, h.time, h.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h argon2Hasher) Verify(hash, password string) (bool, bool, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, false, errUnknownHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, errUnknownHash
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, errUnknownHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, errUnknownHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, errUnknownHash
+	}
+
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+	weaker := time < h.time || memory < h.memory || threads < h.threads
+	return true, weaker, nil
+}
@@ -6,40 +6,82 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 
+	"github.com/howallet/howallet/internal/config"
 	"github.com/howallet/howallet/internal/model"
-	"github.com/howallet/howallet/internal/repository/postgres"
+	"github.com/howallet/howallet/internal/repository"
 )
 
 var (
-	ErrHouseholdNotFound = errors.New("household not found")
-	ErrNotHouseholdOwner = errors.New("only household owner can perform this action")
-	ErrNotMember         = errors.New("user is not a member of this household")
-	ErrInvitationInvalid = errors.New("invitation is invalid or expired")
-	ErrAlreadyMember     = errors.New("user is already a member")
+	ErrHouseholdNotFound  = errors.New("household not found")
+	ErrNotHouseholdOwner  = errors.New("only household owner can perform this action")
+	ErrNotMember          = errors.New("user is not a member of this household")
+	ErrInvitationInvalid  = errors.New("invitation is invalid or expired")
+	ErrAlreadyMember      = errors.New("user is already a member")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrInvitationEmail    = errors.New("invitation was sent to a different email address")
+	ErrInvalidHousehold   = errors.New("household name must not be empty")
+	ErrInvalidCurrency    = errors.New("currency must be a three-letter code")
+	ErrInvalidSettings    = errors.New("invalid household settings")
 )
 
+// defaultTimezone is used for households created without one.
+const defaultTimezone = "UTC"
+
 type HouseholdService struct {
-	repos       *postgres.Repos
-	emailSvc    *EmailService
-	frontendURL string
+	repos         *repository.Repos
+	mailer        Mailer
+	frontendURL   string
+	invitationTTL time.Duration
+	webhooks      *WebhookDispatcher
+	notifications *NotificationService
+	pagination    config.PaginationConfig
+	// defaultCurrency is used for households created without one.
+	defaultCurrency string
+	// maxAmount bounds the opening balances of accounts created with a household.
+	maxAmount decimal.Decimal
+	logger    *slog.Logger
 }
 
-func NewHouseholdService(repos *postgres.Repos, emailSvc *EmailService, frontendURL string) *HouseholdService {
-	return &HouseholdService{repos: repos, emailSvc: emailSvc, frontendURL: frontendURL}
+func NewHouseholdService(repos *repository.Repos, mailer Mailer, frontendURL string, invitationTTL time.Duration, webhooks *WebhookDispatcher, notifications *NotificationService, pagination config.PaginationConfig, defaultCurrency string, maxAmount decimal.Decimal, logger *slog.Logger) *HouseholdService {
+	return &HouseholdService{repos: repos, mailer: mailer, frontendURL: frontendURL, invitationTTL: invitationTTL, webhooks: webhooks, notifications: notifications, pagination: pagination, defaultCurrency: defaultCurrency, maxAmount: maxAmount, logger: logger}
 }
 
 func (s *HouseholdService) Create(ctx context.Context, userID uuid.UUID, req model.CreateHouseholdRequest) (*model.Household, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	if err := validateTimezone(timezone); err != nil {
+		return nil, err
+	}
+	currency := s.defaultCurrency
+	if req.DefaultCurrency != "" {
+		var err error
+		if currency, err = normalizeCurrency(req.DefaultCurrency); err != nil {
+			return nil, err
+		}
+	}
+
 	var hh model.Household
 	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+		txRepos := repository.TxReposFromCtx(txCtx)
 
 		var txErr error
-		hh, txErr = txRepos.Households.Create(txCtx, req.Name, userID)
+		hh, txErr = txRepos.Households.Create(txCtx, repository.CreateHouseholdParams{
+			Name:            req.Name,
+			Timezone:        timezone,
+			DefaultCurrency: currency,
+			OwnerID:         userID,
+		})
 		if txErr != nil {
 			return fmt.Errorf("create household: %w", txErr)
 		}
@@ -48,7 +90,11 @@ func (s *HouseholdService) Create(ctx context.Context, userID uuid.UUID, req mod
 		if txErr != nil {
 			return fmt.Errorf("add owner: %w", txErr)
 		}
-		return nil
+
+		if len(req.Accounts) > 0 {
+			hh.Accounts, txErr = importAccounts(txCtx, txRepos, hh, userID, req.Accounts, s.maxAmount)
+		}
+		return txErr
 	})
 	if err != nil {
 		return nil, err
@@ -56,6 +102,95 @@ func (s *HouseholdService) Create(ctx context.Context, userID uuid.UUID, req mod
 	return &hh, nil
 }
 
+// Update renames a household and/or changes its timezone.
+func (s *HouseholdService) Update(ctx context.Context, id uuid.UUID, req model.UpdateHouseholdRequest) (*model.Household, error) {
+	if req.Name != nil && *req.Name == "" {
+		return nil, ErrInvalidHousehold
+	}
+	if req.Timezone != nil {
+		if err := validateTimezone(*req.Timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	hh, err := s.repos.Households.Update(ctx, repository.UpdateHouseholdParams{
+		ID:                id,
+		Name:              req.Name,
+		Timezone:          req.Timezone,
+		CaseSensitiveTags: req.CaseSensitiveTags,
+	})
+	if err != nil {
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "update household")
+	}
+	return &hh, nil
+}
+
+func (s *HouseholdService) GetSettings(ctx context.Context, id uuid.UUID) (*model.HouseholdSettings, error) {
+	hh, err := s.repos.Households.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	settings := hh.Settings()
+	return &settings, nil
+}
+
+// UpdateSettings changes the settings set in req. A new default currency only
+// applies to accounts created from now on.
+func (s *HouseholdService) UpdateSettings(ctx context.Context, id uuid.UUID, req model.UpdateHouseholdSettingsRequest) (*model.HouseholdSettings, error) {
+	params := repository.UpdateHouseholdParams{
+		ID:                id,
+		Timezone:          req.Timezone,
+		CaseSensitiveTags: req.CaseSensitiveTags,
+	}
+	if req.Timezone != nil {
+		if err := validateTimezone(*req.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	if req.DefaultCurrency != nil {
+		currency, err := normalizeCurrency(*req.DefaultCurrency)
+		if err != nil {
+			return nil, err
+		}
+		params.DefaultCurrency = &currency
+	}
+	if day := req.FiscalMonthStartDay; day != nil {
+		if *day < 1 || *day > model.MaxFiscalMonthStartDay {
+			return nil, fmt.Errorf("%w: fiscal_month_start_day must be between 1 and %d", ErrInvalidSettings, model.MaxFiscalMonthStartDay)
+		}
+		d := int32(*day)
+		params.FiscalMonthStartDay = &d
+	}
+
+	hh, err := s.repos.Households.Update(ctx, params)
+	if err != nil {
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "update household settings")
+	}
+	settings := hh.Settings()
+	return &settings, nil
+}
+
+// validateTimezone accepts IANA zone names such as "Europe/Berlin". "Local" is
+// refused: it would mean the server's zone, not the household's.
+func validateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+	return nil
+}
+
+// normalizeCurrency upper-cases a currency code and checks it has three letters.
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(code)
+	if len(code) != 3 || strings.IndexFunc(code, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	return code, nil
+}
+
 func (s *HouseholdService) List(ctx context.Context, userID uuid.UUID) ([]model.Household, error) {
 	list, err := s.repos.Households.ListByUser(ctx, userID)
 	if err != nil {
@@ -64,43 +199,64 @@ func (s *HouseholdService) List(ctx context.Context, userID uuid.UUID) ([]model.
 	return list, nil
 }
 
-func (s *HouseholdService) Get(ctx context.Context, id uuid.UUID) (*model.Household, error) {
+// Get returns a household with its member count and userID's role in it.
+func (s *HouseholdService) Get(ctx context.Context, id, userID uuid.UUID) (*model.HouseholdDetail, error) {
 	hh, err := s.repos.Households.GetByID(ctx, id)
 	if err != nil {
-		return nil, ErrHouseholdNotFound
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "get household")
 	}
-	return &hh, nil
+	count, err := s.repos.Households.CountMembers(ctx, repository.ListMembersParams{HouseholdID: id})
+	if err != nil {
+		return nil, fmt.Errorf("count members: %w", err)
+	}
+	member, err := s.repos.Households.GetMember(ctx, id, userID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrNotMember, "get member")
+	}
+	return &model.HouseholdDetail{Household: hh, MemberCount: count, Role: member.Role}, nil
 }
 
-func (s *HouseholdService) ListMembers(ctx context.Context, householdID uuid.UUID) ([]model.HouseholdMember, error) {
-	members, err := s.repos.Households.ListMembers(ctx, householdID)
+// ListMembers returns a page of the members matching q's role and search
+// filters, with a total count.
+func (s *HouseholdService) ListMembers(ctx context.Context, householdID uuid.UUID, q model.ListMembersQuery) (*model.PaginatedResponse, error) {
+	q.Limit = s.pagination.Limit(q.Limit)
+	params := memberParams(householdID, q)
+
+	members, err := s.repos.Households.ListMembers(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
-	return members, nil
-}
 
-func (s *HouseholdService) RemoveMember(ctx context.Context, householdID, ownerID, targetUserID uuid.UUID) error {
-	member, err := s.repos.Households.GetMember(ctx, householdID, ownerID)
+	total, err := s.repos.Households.CountMembers(ctx, params)
 	if err != nil {
-		return ErrNotMember
+		return nil, fmt.Errorf("count members: %w", err)
 	}
-	if member.Role != model.HouseholdRoleOwner {
-		return ErrNotHouseholdOwner
+
+	return model.NewPaginatedResponse(members, total, q.Limit, q.Offset), nil
+}
+
+func memberParams(householdID uuid.UUID, q model.ListMembersQuery) repository.ListMembersParams {
+	return repository.ListMembersParams{
+		HouseholdID: householdID,
+		Role:        q.Role,
+		Search:      q.Search,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
 	}
+}
 
+// RemoveMember removes a user from the household. The caller must have checked
+// that the acting user is the owner (see middleware.RoleFromCtx).
+func (s *HouseholdService) RemoveMember(ctx context.Context, householdID, targetUserID uuid.UUID) error {
 	return s.repos.Households.RemoveMember(ctx, householdID, targetUserID)
 }
 
-// Invite creates an invitation token for the given email.
-func (s *HouseholdService) Invite(ctx context.Context, householdID, inviterID uuid.UUID, email string) (*model.Invitation, error) {
-	// Verify inviter is owner
-	member, err := s.repos.Households.GetMember(ctx, householdID, inviterID)
+// Invite creates an invitation token for the given email. The caller must have
+// checked that the inviter is the owner.
+func (s *HouseholdService) Invite(ctx context.Context, householdID, inviterID uuid.UUID, email string) (*model.InviteResponse, error) {
+	email, err := validateEmail(email)
 	if err != nil {
-		return nil, ErrNotMember
-	}
-	if member.Role != model.HouseholdRoleOwner {
-		return nil, ErrNotHouseholdOwner
+		return nil, err
 	}
 
 	// Check if already a member
@@ -119,26 +275,50 @@ func (s *HouseholdService) Invite(ctx context.Context, householdID, inviterID uu
 	}
 	token := hex.EncodeToString(tokenBytes)
 
-	inv, err := s.repos.Invitations.Create(ctx, householdID, inviterID, email, token, time.Now().Add(7*24*time.Hour))
+	inv, err := s.repos.Invitations.Create(ctx, householdID, inviterID, email, token, time.Now().Add(s.invitationTTL))
 	if err != nil {
 		return nil, fmt.Errorf("create invitation: %w", err)
 	}
 
 	// Send invitation email (best-effort: don't fail the invite if email fails)
-	if s.emailSvc != nil && s.emailSvc.cfg.Host != "" {
-		hh, _ := s.repos.Households.GetByID(ctx, householdID)
-		inviter, _ := s.repos.Users.GetByID(ctx, inviterID)
-		inviterName := "A hoWallet user"
-		if inviter.Name != "" {
-			inviterName = inviter.Name
-		}
-		_ = s.emailSvc.SendInvitation(email, hh.Name, inviterName, token, s.frontendURL)
+	resp := &model.InviteResponse{Invitation: inv, AcceptURL: invitationURL(s.frontendURL, inv.Token)}
+	if s.mailer != nil {
+		resp.EmailSent = s.sendInvitationEmail(ctx, inv, inviterID)
+	}
+
+	return resp, nil
+}
+
+// invitationEmailTimeout bounds how long an invite waits for the mail server.
+const invitationEmailTimeout = 10 * time.Second
+
+// sendInvitationEmail looks up what the invitation email needs and sends it,
+// giving up after invitationEmailTimeout. It reports whether the mail server
+// accepted the email; a failed send is only logged.
+func (s *HouseholdService) sendInvitationEmail(ctx context.Context, inv model.Invitation, inviterID uuid.UUID) bool {
+	hh, err := s.repos.Households.GetByID(ctx, inv.HouseholdID)
+	if err != nil {
+		s.logger.Error("invitation email not sent", slog.String("invitation_id", inv.ID.String()), slog.String("error", err.Error()))
+		return false
+	}
+
+	inviterName := "A hoWallet user"
+	if inviter, err := s.repos.Users.GetByID(ctx, inviterID); err == nil && inviter.Name != "" {
+		inviterName = inviter.Name
 	}
 
-	return &inv, nil
+	ctx, cancel := context.WithTimeout(ctx, invitationEmailTimeout)
+	defer cancel()
+	err = s.mailer.SendInvitation(ctx, inv.Email, hh.Name, inviterName, inv.Token, s.frontendURL, inv.ExpiresAt)
+	// ErrEmailDisabled is expected without SMTP; the accept URL is in the response.
+	if err != nil && !errors.Is(err, ErrEmailDisabled) {
+		s.logger.Error("invitation email failed", slog.String("invitation_id", inv.ID.String()), slog.String("error", err.Error()))
+	}
+	return err == nil
 }
 
 // AcceptInvitation accepts an invitation token and adds the user to the household.
+// Only the user the invitation was sent to may accept it.
 func (s *HouseholdService) AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) error {
 	inv, err := s.repos.Invitations.GetByToken(ctx, token)
 	if err != nil {
@@ -148,41 +328,107 @@ func (s *HouseholdService) AcceptInvitation(ctx context.Context, token string, u
 		return fmt.Errorf("get invitation: %w", err)
 	}
 
+	// Expired invitations are reported as such by GetByToken.
 	if inv.Status != model.InvitationStatusPending {
 		return ErrInvitationInvalid
 	}
-	if inv.ExpiresAt.Before(time.Now()) {
-		return ErrInvitationInvalid
+
+	// The token alone isn't enough: it may have been forwarded or leaked.
+	user, err := s.repos.Users.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return ErrInvitationEmail
 	}
 
-	return s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		// AddMember ignores existing members, so check first; the invitation stays
+		// pending rather than being consumed by a no-op.
+		isMember, err := txRepos.Households.IsMember(txCtx, inv.HouseholdID, userID)
+		if err != nil {
+			return fmt.Errorf("check membership: %w", err)
+		}
+		if isMember {
+			return ErrAlreadyMember
+		}
 
 		if err := txRepos.Households.AddMember(txCtx, inv.HouseholdID, userID, model.HouseholdRoleMember); err != nil {
+			if mapped := constraintError(err, ErrAlreadyMember, ErrInvitationInvalid); mapped != nil {
+				return mapped
+			}
 			return fmt.Errorf("add member: %w", err)
 		}
 
-		if err := txRepos.Invitations.Accept(txCtx, inv.ID); err != nil {
+		// Guards against a concurrent accept or expiry since the read above.
+		accepted, err := txRepos.Invitations.Accept(txCtx, inv.ID)
+		if err != nil {
 			return fmt.Errorf("accept invitation: %w", err)
 		}
+		if !accepted {
+			return ErrInvitationInvalid
+		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.webhooks.Publish(inv.HouseholdID, model.WebhookEventMemberAdded, map[string]interface{}{
+		"user_id": userID,
+		"role":    model.HouseholdRoleMember,
+	})
+	s.notifications.MemberJoined(ctx, inv.HouseholdID, userID, inv.InvitedBy)
+	return nil
 }
 
-// CheckMembership verifies the user is a member of the household.
-func (s *HouseholdService) CheckMembership(ctx context.Context, householdID, userID uuid.UUID) error {
-	isMember, err := s.repos.Households.IsMember(ctx, householdID, userID)
+// RevokeInvitation cancels a pending invitation. Only the household owner may revoke;
+// the caller must have checked that.
+func (s *HouseholdService) RevokeInvitation(ctx context.Context, householdID, invitationID uuid.UUID) error {
+	deleted, err := s.repos.Invitations.DeletePending(ctx, invitationID, householdID)
 	if err != nil {
-		return fmt.Errorf("check membership: %w", err)
+		return fmt.Errorf("delete invitation: %w", err)
 	}
-	if !isMember {
-		return ErrNotMember
+	if !deleted {
+		return ErrInvitationNotFound
 	}
 	return nil
 }
 
-// ListPendingInvitations returns pending invitations for a household.
-func (s *HouseholdService) ListPendingInvitations(ctx context.Context, householdID uuid.UUID) ([]model.Invitation, error) {
-	return s.repos.Invitations.ListPendingByHousehold(ctx, householdID)
+// CheckMembership verifies the user is a member of the household and returns their role.
+// It returns ErrHouseholdNotFound rather than ErrNotMember if the household doesn't exist.
+func (s *HouseholdService) CheckMembership(ctx context.Context, householdID, userID uuid.UUID) (model.HouseholdRole, error) {
+	member, err := s.repos.Households.GetMember(ctx, householdID, userID)
+	if err == nil {
+		return member.Role, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("check membership: %w", err)
+	}
+	if _, err := s.repos.Households.GetByID(ctx, householdID); err != nil {
+		return "", notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	return "", ErrNotMember
+}
+
+// ListPendingInvitations returns a page of a household's pending invitations,
+// newest first, with a total count. Invitations carry their accept token, so
+// the caller must restrict this to the owner.
+func (s *HouseholdService) ListPendingInvitations(ctx context.Context, householdID uuid.UUID, limit, offset int32) (*model.PaginatedResponse, error) {
+	limit = s.pagination.Limit(limit)
+
+	invitations, err := s.repos.Invitations.ListPendingByHousehold(ctx, householdID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list invitations: %w", err)
+	}
+
+	total, err := s.repos.Invitations.CountPendingByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("count invitations: %w", err)
+	}
+
+	return model.NewPaginatedResponse(invitations, total, limit, offset), nil
 }
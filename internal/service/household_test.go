@@ -0,0 +1,275 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+)
+
+// mockMailer records invitations. A send waits for release, if set, or for ctx
+// to be done, before returning err.
+type mockMailer struct {
+	err     error
+	release chan struct{}
+	sent    []string
+}
+
+func (m *mockMailer) SendInvitation(ctx context.Context, toEmail, householdName, inviterName, token, frontendURL string, expiresAt time.Time) error {
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	m.sent = append(m.sent, toEmail+"|"+householdName+"|"+inviterName+"|"+token)
+	return m.err
+}
+
+func newTestHouseholdService(f *fakes, mailer Mailer, logs *bytes.Buffer) *HouseholdService {
+	logger := slog.New(slog.NewTextHandler(logs, nil))
+	return NewHouseholdService(f.repos, mailer, "https://app.example.com", time.Hour, nil, nil,
+		config.PaginationConfig{DefaultLimit: 50, MaxLimit: 100}, "USD", decimal.New(1, 12), logger)
+}
+
+func TestSendInvitationEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		mailer     *mockMailer
+		wantSent   bool
+		wantLogged bool
+	}{
+		{"delivered", &mockMailer{}, true, false},
+		{"smtp failure", &mockMailer{err: errors.New("550 mailbox unavailable")}, false, true},
+		{"email disabled", &mockMailer{err: ErrEmailDisabled}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := model.Household{ID: uuid.New(), Name: "Home"}
+			f.households.byID[hh.ID] = hh
+			inviter := model.User{ID: uuid.New(), Name: "Olena"}
+			f.users.byID[inviter.ID] = inviter
+			var logs bytes.Buffer
+			svc := newTestHouseholdService(f, tt.mailer, &logs)
+
+			inv := model.Invitation{ID: uuid.New(), HouseholdID: hh.ID, Email: "guest@example.com", Token: "tok"}
+			if got := svc.sendInvitationEmail(context.Background(), inv, inviter.ID); got != tt.wantSent {
+				t.Errorf("sent = %v, want %v", got, tt.wantSent)
+			}
+			if want := []string{"guest@example.com|Home|Olena|tok"}; len(tt.mailer.sent) != 1 || tt.mailer.sent[0] != want[0] {
+				t.Errorf("sent %q, want %q", tt.mailer.sent, want)
+			}
+			if logged := strings.Contains(logs.String(), "invitation email failed"); logged != tt.wantLogged {
+				t.Errorf("failure logged = %v, want %v (logs: %s)", logged, tt.wantLogged, logs.String())
+			}
+		})
+	}
+}
+
+// A mail server that hangs is given up on and reported as not sent.
+func TestSendInvitationEmailTimesOut(t *testing.T) {
+	f := newFakes()
+	hh := model.Household{ID: uuid.New(), Name: "Home"}
+	f.households.byID[hh.ID] = hh
+	mailer := &mockMailer{release: make(chan struct{})}
+	var logs bytes.Buffer
+	svc := newTestHouseholdService(f, mailer, &logs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if svc.sendInvitationEmail(ctx, model.Invitation{ID: uuid.New(), HouseholdID: hh.ID}, uuid.New()) {
+		t.Error("a send the server never answered was reported as sent")
+	}
+	if !strings.Contains(logs.String(), "invitation email failed") {
+		t.Errorf("timeout not logged: %s", logs.String())
+	}
+}
+
+// An SMTP server that accepts the connection but never greets must not hold
+// the sender past its deadline.
+func TestSMTPMailerHonoursDeadline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	mailer := NewSMTPMailer(&config.SMTPConfig{Host: host, Port: port, From: "noreply@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = mailer.SendInvitation(ctx, "guest@example.com", "Home", "Olena", "tok", "https://app.example.com", time.Now())
+	if err == nil {
+		t.Fatal("SendInvitation succeeded against a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SendInvitation returned after %s, want about 100ms", elapsed)
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	tests := []struct {
+		name       string
+		userEmail  string
+		member     bool
+		wantErr    error
+		wantStatus model.InvitationStatus
+	}{
+		{"matching email", "olena@example.com", false, nil, model.InvitationStatusAccepted},
+		{"email differs only in case", "Olena@Example.com", false, nil, model.InvitationStatusAccepted},
+		{"someone else's invitation", "taras@example.com", false, ErrInvitationEmail, model.InvitationStatusPending},
+		{"already a member", "olena@example.com", true, ErrAlreadyMember, model.InvitationStatusPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			user := model.User{ID: uuid.New(), Email: tt.userEmail}
+			f.users.byID[user.ID] = user
+			if tt.member {
+				f.households.members[hh] = map[uuid.UUID]model.HouseholdRole{user.ID: model.HouseholdRoleMember}
+			}
+			inv := &model.Invitation{
+				ID: uuid.New(), HouseholdID: hh, Email: "olena@example.com", InvitedBy: uuid.New(),
+				Token: "tok", Status: model.InvitationStatusPending, ExpiresAt: time.Now().Add(time.Hour),
+			}
+			f.invitations.byToken[inv.Token] = inv
+			svc := newTestHouseholdService(f, &mockMailer{}, &bytes.Buffer{})
+
+			err := svc.AcceptInvitation(context.Background(), inv.Token, user.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AcceptInvitation = %v, want %v", err, tt.wantErr)
+			}
+			if inv.Status != tt.wantStatus {
+				t.Errorf("invitation status %q, want %q", inv.Status, tt.wantStatus)
+			}
+			_, joined := f.households.members[hh][user.ID]
+			if wantJoined := tt.member || tt.wantErr == nil; joined != wantJoined {
+				t.Errorf("member after accept = %v, want %v", joined, wantJoined)
+			}
+		})
+	}
+}
+
+func TestCheckMembership(t *testing.T) {
+	f := newFakes()
+	hh := model.Household{ID: uuid.New(), Name: "Home"}
+	f.households.byID[hh.ID] = hh
+	owner, stranger := uuid.New(), uuid.New()
+	f.households.members[hh.ID] = map[uuid.UUID]model.HouseholdRole{owner: model.HouseholdRoleOwner}
+	svc := newTestHouseholdService(f, &mockMailer{}, &bytes.Buffer{})
+
+	tests := []struct {
+		name        string
+		householdID uuid.UUID
+		userID      uuid.UUID
+		wantRole    model.HouseholdRole
+		wantErr     error
+	}{
+		{"member", hh.ID, owner, model.HouseholdRoleOwner, nil},
+		{"not a member", hh.ID, stranger, "", ErrNotMember},
+		{"unknown household", uuid.New(), owner, "", ErrHouseholdNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := svc.CheckMembership(context.Background(), tt.householdID, tt.userID)
+			if !errors.Is(err, tt.wantErr) || role != tt.wantRole {
+				t.Fatalf("CheckMembership = %q, %v; want %q, %v", role, err, tt.wantRole, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListPendingInvitationsIsBounded(t *testing.T) {
+	f := newFakes()
+	hh := uuid.New()
+	for i := range 3 {
+		token := fmt.Sprintf("tok-%d", i)
+		f.invitations.byToken[token] = &model.Invitation{
+			ID: uuid.New(), HouseholdID: hh, Token: token, Status: model.InvitationStatusPending,
+		}
+	}
+	svc := newTestHouseholdService(f, &mockMailer{}, &bytes.Buffer{})
+
+	tests := []struct {
+		name      string
+		limit     int32
+		wantLimit int32
+		wantItems int
+	}{
+		{"limit left out", 0, 50, 3},
+		{"limit over the maximum", 1000, 100, 3},
+		{"small page", 2, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := svc.ListPendingInvitations(context.Background(), hh, tt.limit, 0)
+			if err != nil {
+				t.Fatalf("ListPendingInvitations: %v", err)
+			}
+			if got := f.invitations.listLimits[len(f.invitations.listLimits)-1]; got != tt.wantLimit {
+				t.Errorf("queried with limit %d, want %d", got, tt.wantLimit)
+			}
+			if page.Limit != tt.wantLimit || page.Total != 3 {
+				t.Errorf("page limit %d total %d, want %d and 3", page.Limit, page.Total, tt.wantLimit)
+			}
+			if items := page.Data.([]model.Invitation); len(items) != tt.wantItems {
+				t.Errorf("got %d invitations, want %d", len(items), tt.wantItems)
+			}
+		})
+	}
+}
+
+// Changing your email to the invited address must not let you accept an
+// invitation meant for someone else.
+func TestAcceptInvitationAfterEmailChange(t *testing.T) {
+	f := newFakes()
+	hh := uuid.New()
+	user := model.User{ID: uuid.New(), Email: "taras@example.com", Name: "Taras"}
+	f.users.byID[user.ID] = user
+	inv := &model.Invitation{
+		ID: uuid.New(), HouseholdID: hh, Email: "olena@example.com", InvitedBy: uuid.New(),
+		Token: "tok", Status: model.InvitationStatusPending, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	f.invitations.byToken[inv.Token] = inv
+	auth := newTestAuthService(f)
+	svc := newTestHouseholdService(f, &mockMailer{}, &bytes.Buffer{})
+
+	_, err := auth.UpdateProfile(context.Background(), user.ID, model.UpdateProfileRequest{Email: &inv.Email})
+	if !errors.Is(err, ErrEmailChangeUnsupported) {
+		t.Fatalf("UpdateProfile = %v, want ErrEmailChangeUnsupported", err)
+	}
+	if got := f.users.byID[user.ID].Email; got != user.Email {
+		t.Fatalf("email changed to %q", got)
+	}
+
+	if err := svc.AcceptInvitation(context.Background(), inv.Token, user.ID); !errors.Is(err, ErrInvitationEmail) {
+		t.Fatalf("AcceptInvitation = %v, want ErrInvitationEmail", err)
+	}
+	if _, joined := f.households.members[hh][user.ID]; joined {
+		t.Error("user joined the household")
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// Janitor periodically removes expired (and, if enabled, idle) refresh tokens
+// and expires stale invitations.
+type Janitor struct {
+	repos    *repository.Repos
+	interval time.Duration
+	// refreshIdleTTL deletes refresh tokens unused for this long; 0 disables it.
+	refreshIdleTTL time.Duration
+	logger         *slog.Logger
+}
+
+func NewJanitor(repos *repository.Repos, interval, refreshIdleTTL time.Duration, logger *slog.Logger) *Janitor {
+	return &Janitor{repos: repos, interval: interval, refreshIdleTTL: refreshIdleTTL, logger: logger}
+}
+
+// Run cleans up once immediately and then on every tick until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Janitor) cleanup(ctx context.Context) {
+	// failed names the steps that errored, whose counts below are meaningless.
+	var failed []string
+	fail := func(step string, err error) {
+		failed = append(failed, step)
+		if ctx.Err() == nil {
+			j.logger.Error("janitor: "+step, slog.String("error", err.Error()))
+		}
+	}
+
+	tokens, err := j.repos.RefreshTokens.DeleteExpired(ctx)
+	if err != nil {
+		fail("delete expired refresh tokens", err)
+	}
+
+	var idleTokens int64
+	if j.refreshIdleTTL > 0 {
+		idleTokens, err = j.repos.RefreshTokens.DeleteIdle(ctx, time.Now().Add(-j.refreshIdleTTL))
+		if err != nil {
+			fail("delete idle refresh tokens", err)
+		}
+	}
+
+	invitations, err := j.repos.Invitations.ExpireStale(ctx)
+	if err != nil {
+		fail("expire stale invitations", err)
+	}
+
+	// Shutting down mid-run isn't worth a summary.
+	if ctx.Err() != nil {
+		return
+	}
+	attrs := []any{
+		slog.Int64("refresh_tokens_deleted", tokens),
+		slog.Int64("idle_refresh_tokens_deleted", idleTokens),
+		slog.Int64("invitations_expired", invitations),
+	}
+	if len(failed) > 0 {
+		j.logger.Warn("janitor run incomplete", append(attrs, slog.Any("failed", failed))...)
+		return
+	}
+	j.logger.Info("janitor run complete", attrs...)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/howallet/howallet/internal/repository"
+)
+
+type janitorTokens struct {
+	repository.RefreshTokenRepository
+	err error
+}
+
+func (f *janitorTokens) DeleteExpired(context.Context) (int64, error) { return 3, f.err }
+
+func (f *janitorTokens) DeleteIdle(context.Context, time.Time) (int64, error) { return 2, nil }
+
+type janitorInvitations struct {
+	repository.InvitationRepository
+}
+
+func (janitorInvitations) ExpireStale(context.Context) (int64, error) { return 1, nil }
+
+func TestJanitorSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		cancel   bool
+		want     string
+		unwanted string
+	}{
+		{"all steps succeed", nil, false, `level=INFO msg="janitor run complete"`, "incomplete"},
+		{"a step fails", errors.New("connection reset"), false, `level=WARN msg="janitor run incomplete"`, "run complete"},
+		{"shutting down", context.Canceled, true, "", "janitor run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			repos := &repository.Repos{
+				RefreshTokens: &janitorTokens{err: tt.err},
+				Invitations:   janitorInvitations{},
+			}
+			j := NewJanitor(repos, time.Hour, time.Hour, slog.New(slog.NewTextHandler(&logs, nil)))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+			j.cleanup(ctx)
+
+			if !strings.Contains(logs.String(), tt.want) {
+				t.Errorf("logs %q do not contain %q", logs.String(), tt.want)
+			}
+			if strings.Contains(logs.String(), tt.unwanted) {
+				t.Errorf("logs %q contain %q", logs.String(), tt.unwanted)
+			}
+		})
+	}
+}
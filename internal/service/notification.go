@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidThreshold     = errors.New("large_transaction_threshold must be a positive amount")
+)
+
+// NotificationService creates in-app notifications for household events and
+// serves them to their recipients.
+type NotificationService struct {
+	repos      *repository.Repos
+	pagination config.PaginationConfig
+	logger     *slog.Logger
+	maxAmount  decimal.Decimal
+}
+
+func NewNotificationService(repos *repository.Repos, pagination config.PaginationConfig, logger *slog.Logger, maxAmount decimal.Decimal) *NotificationService {
+	return &NotificationService{repos: repos, pagination: pagination, logger: logger, maxAmount: maxAmount}
+}
+
+// List pages through the user's notifications, newest first. Notifications from
+// households the user has left are not returned.
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, q model.ListNotificationsQuery) (*model.PaginatedResponse, error) {
+	q.Limit = s.pagination.Limit(q.Limit)
+
+	list, err := s.repos.Notifications.List(ctx, userID, q)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	total, err := s.repos.Notifications.Count(ctx, userID, q.UnreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("count notifications: %w", err)
+	}
+	return model.NewPaginatedResponse(list, total, q.Limit, q.Offset), nil
+}
+
+// MarkRead marks one of the user's notifications read. Marking it again is a no-op.
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID uuid.UUID) (*model.Notification, error) {
+	n, err := s.repos.Notifications.MarkRead(ctx, id, userID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrNotificationNotFound, "mark notification read")
+	}
+	return &n, nil
+}
+
+func (s *NotificationService) Preferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	prefs, err := s.repos.Notifications.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences replaces the user's preferences.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req model.UpdateNotificationPreferencesRequest) (*model.NotificationPreferences, error) {
+	prefs := model.NotificationPreferences{
+		MemberJoined:       req.MemberJoined,
+		InvitationAccepted: req.InvitationAccepted,
+	}
+	if req.LargeTransactionThreshold != nil && strings.TrimSpace(*req.LargeTransactionThreshold) != "" {
+		threshold, err := parseAmount(strings.TrimSpace(*req.LargeTransactionThreshold), s.maxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidThreshold, err)
+		}
+		if !threshold.IsPositive() {
+			return nil, ErrInvalidThreshold
+		}
+		prefs.LargeTransactionThreshold = &threshold
+	}
+
+	saved, err := s.repos.Notifications.SavePreferences(ctx, userID, prefs)
+	if err != nil {
+		return nil, fmt.Errorf("save notification preferences: %w", err)
+	}
+	return &saved, nil
+}
+
+// --- events ---
+//
+// The methods below are called after the triggering change has committed.
+// Failures are logged rather than returned: a lost notification must not fail
+// the request. A nil service ignores events.
+
+// TransactionCreated notifies members whose large-transaction threshold the
+// amount reaches. Transfers move money within the household and never notify.
+func (s *NotificationService) TransactionCreated(ctx context.Context, txn model.Transaction) {
+	if s == nil || txn.Type == model.TransactionTypeTransfer {
+		return
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"transaction_id": txn.ID,
+		"type":           txn.Type,
+		"amount":         txn.Amount,
+		"description":    txn.Description,
+		"account_id":     txn.AccountID,
+		"created_by":     txn.CreatedBy,
+	})
+	if err != nil {
+		s.logError(model.NotificationKindLargeTransaction, err)
+		return
+	}
+	// Detached from the request so a client hanging up doesn't drop the notifications.
+	ctx = context.WithoutCancel(ctx)
+	if _, err := s.repos.Notifications.NotifyLargeTransaction(ctx, txn.HouseholdID, txn.CreatedBy, txn.Amount, data); err != nil {
+		s.logError(model.NotificationKindLargeTransaction, err)
+	}
+}
+
+// MemberJoined tells the inviter their invitation was accepted and the other
+// members that someone joined.
+func (s *NotificationService) MemberJoined(ctx context.Context, householdID, memberID, inviterID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"user_id":    memberID,
+		"invited_by": inviterID,
+	})
+	if err != nil {
+		s.logError(model.NotificationKindMemberJoined, err)
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	if _, err := s.repos.Notifications.NotifyInvitationAccepted(ctx, householdID, inviterID, data); err != nil {
+		s.logError(model.NotificationKindInvitationAccepted, err)
+	}
+	if _, err := s.repos.Notifications.NotifyMemberJoined(ctx, householdID, memberID, inviterID, data); err != nil {
+		s.logError(model.NotificationKindMemberJoined, err)
+	}
+}
+
+func (s *NotificationService) logError(kind model.NotificationKind, err error) {
+	s.logger.Error("notification: create", slog.String("kind", string(kind)), slog.String("error", err.Error()))
+}
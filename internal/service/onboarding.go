@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var ErrInvalidOnboarding = errors.New("invalid onboarding request")
+
+// OnboardingService sets up a first account with opening transactions in one go.
+type OnboardingService struct {
+	repos    *repository.Repos
+	webhooks *WebhookDispatcher
+	txnCfg   config.TransactionConfig
+}
+
+func NewOnboardingService(repos *repository.Repos, webhooks *WebhookDispatcher, txnCfg config.TransactionConfig) *OnboardingService {
+	return &OnboardingService{repos: repos, webhooks: webhooks, txnCfg: txnCfg}
+}
+
+// Onboard creates the account and its opening transactions atomically. Every
+// transaction is booked against the new account; transfers aren't allowed since
+// there is no second account yet. Nothing is written if any item is invalid.
+func (s *OnboardingService) Onboard(ctx context.Context, householdID, userID uuid.UUID, req model.OnboardingRequest) (*model.OnboardingResponse, error) {
+	if req.Account.Name == "" {
+		return nil, fmt.Errorf("%w: account name is required", ErrInvalidOnboarding)
+	}
+	currency, err := accountCurrency(ctx, s.repos.Households, householdID, req.Account.Currency)
+	if err != nil {
+		return nil, err
+	}
+	accParams, err := newCreateAccountParams(householdID, userID, req.Account, currency, s.txnCfg.MaxAmount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: account: %v", ErrInvalidOnboarding, err)
+	}
+
+	txnParams := make([]repository.CreateTransactionParams, 0, len(req.Transactions))
+	for i, t := range req.Transactions {
+		if t.Description == "" || t.Amount == "" {
+			return nil, fmt.Errorf("%w: transaction %d: description and amount are required", ErrInvalidOnboarding, i)
+		}
+		if t.Type != model.TransactionTypeIncome && t.Type != model.TransactionTypeExpense {
+			return nil, fmt.Errorf("%w: transaction %d: type must be income or expense", ErrInvalidOnboarding, i)
+		}
+		t.DestinationAccountID = nil
+		params, err := newCreateTransactionParams(householdID, userID, t, s.txnCfg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: transaction %d: %v", ErrInvalidOnboarding, i, err)
+		}
+		txnParams = append(txnParams, params)
+	}
+
+	resp := &model.OnboardingResponse{Transactions: make([]model.Transaction, 0, len(txnParams))}
+	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		var txErr error
+		if accParams.Type, txErr = checkAccountType(txCtx, txRepos.AccountTypes, householdID, accParams.Type); txErr != nil {
+			if errors.Is(txErr, ErrInvalidAccountType) {
+				return fmt.Errorf("%w: account: %v", ErrInvalidOnboarding, txErr)
+			}
+			return txErr
+		}
+
+		acc, txErr := txRepos.Accounts.Create(txCtx, accParams)
+		if txErr != nil {
+			return fmt.Errorf("create account: %w", txErr)
+		}
+
+		for _, params := range txnParams {
+			params.AccountID = acc.ID
+			txn, txErr := insertTransaction(txCtx, txRepos, params)
+			if txErr != nil {
+				return txErr
+			}
+			resp.Transactions = append(resp.Transactions, txn)
+		}
+
+		// Re-read to pick up the balance after the opening transactions.
+		resp.Account, txErr = txRepos.Accounts.GetByID(txCtx, acc.ID, householdID)
+		if txErr != nil {
+			return fmt.Errorf("get account: %w", txErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txn := range resp.Transactions {
+		s.webhooks.Publish(householdID, model.WebhookEventTransactionCreated, txn)
+	}
+	return resp, nil
+}
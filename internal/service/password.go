@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/howallet/howallet/internal/config"
+)
+
+// ErrWeakPassword is matched (via errors.Is) by every *PasswordPolicyError.
+var ErrWeakPassword = errors.New("password does not meet the policy")
+
+// PasswordPolicyError lists the policy rules a password failed, as
+// human-readable sentences.
+type PasswordPolicyError struct {
+	Failed []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error() + ": " + strings.Join(e.Failed, "; ")
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// checkPassword applies the configured policy to a new password. Every entry
+// point that sets a password must call it.
+func checkPassword(policy config.PasswordConfig, password string) error {
+	var failed []string
+
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		failed = append(failed, fmt.Sprintf("must be at least %d characters", policy.MinLength))
+	}
+	if len(password) > config.MaxPasswordBytes {
+		failed = append(failed, fmt.Sprintf("must be at most %d bytes", config.MaxPasswordBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if policy.RequireMixedCase && !(upper && lower) {
+		failed = append(failed, "must contain upper- and lower-case letters")
+	}
+	if policy.RequireDigit && !digit {
+		failed = append(failed, "must contain a digit")
+	}
+	if policy.RequireSymbol && !symbol {
+		failed = append(failed, "must contain a symbol")
+	}
+
+	if len(failed) > 0 {
+		return &PasswordPolicyError{Failed: failed}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes that the services translate into domain errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// constraintError maps a unique or foreign key violation to the given domain error.
+// It returns nil if err is not such a violation or no mapping was given for it,
+// so callers fall back to wrapping the original error.
+func constraintError(err error, onUnique, onForeignKey error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return onUnique
+	case pgForeignKeyViolation:
+		return onForeignKey
+	}
+	return nil
+}
+
+// notFoundOr returns notFound when err means no row matched, and otherwise wraps
+// err with op so that connection failures and the like surface as server errors.
+func notFoundOr(err, notFound error, op string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestConstraintError(t *testing.T) {
+	unique := &pgconn.PgError{Code: pgUniqueViolation, Message: `duplicate key value violates unique constraint "idx_users_email_lower"`}
+	foreignKey := &pgconn.PgError{Code: pgForeignKeyViolation, Message: `insert or update on table "accounts" violates foreign key constraint`}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unique", unique, ErrEmailTaken},
+		{"wrapped unique", fmt.Errorf("create user: %w", unique), ErrEmailTaken},
+		{"foreign key", foreignKey, ErrAccountNotFound},
+		{"other postgres error", &pgconn.PgError{Code: "40001"}, nil},
+		{"not a postgres error", errors.New("connection reset"), nil},
+		{"no rows", pgx.ErrNoRows, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := constraintError(tt.err, ErrEmailTaken, ErrAccountNotFound); got != tt.want {
+				t.Fatalf("constraintError = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A violation without a mapping falls through to the caller's own wrapping.
+	if got := constraintError(foreignKey, ErrEmailTaken, nil); got != nil {
+		t.Errorf("unmapped foreign key violation = %v, want nil", got)
+	}
+}
+
+// Two registrations racing past the email check: the loser hits the unique
+// index and must get ErrEmailTaken, not a 500 that leaks the constraint name.
+func TestRegisterUniqueViolation(t *testing.T) {
+	f := newFakes()
+	f.users.createErr = fmt.Errorf("insert: %w", &pgconn.PgError{Code: pgUniqueViolation, Message: `duplicate key value violates unique constraint "idx_users_email_lower"`})
+	svc := newTestAuthService(f)
+
+	_, err := svc.Register(context.Background(), model.RegisterRequest{Email: "bob@example.com", Password: "long enough", Name: "Bob"}, model.ClientInfo{})
+	if err != ErrEmailTaken {
+		t.Fatalf("Register error = %v, want exactly ErrEmailTaken", err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// postBatchSize caps how many due transactions one run looks up; the rest are
+// picked up by the next query of the same run.
+const postBatchSize = 100
+
+// Poster periodically posts scheduled transactions whose date has arrived,
+// applying their balance change.
+type Poster struct {
+	repos    *repository.Repos
+	webhooks *WebhookDispatcher
+	alerts   *AlertService
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewPoster(repos *repository.Repos, webhooks *WebhookDispatcher, alerts *AlertService, interval time.Duration, logger *slog.Logger) *Poster {
+	return &Poster{repos: repos, webhooks: webhooks, alerts: alerts, interval: interval, logger: logger}
+}
+
+// Run posts once immediately and then on every tick until ctx is cancelled.
+func (p *Poster) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.postDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Poster) postDue(ctx context.Context) {
+	now := time.Now()
+	var posted int
+	for {
+		ids, err := p.repos.Transactions.ListDue(ctx, now, postBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Error("poster: list due transactions", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		progressed := false
+		for _, id := range ids {
+			ok, err := p.post(ctx, id, now)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				p.logger.Error("poster: post transaction",
+					slog.String("transaction_id", id.String()),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			if ok {
+				posted++
+				progressed = true
+			}
+		}
+		// A short page is the last one; a page of failures would come back
+		// unchanged, so stop until the next tick.
+		if len(ids) < postBatchSize || !progressed {
+			break
+		}
+	}
+
+	if posted > 0 {
+		p.logger.Info("poster run complete", slog.Int("transactions_posted", posted))
+	}
+}
+
+// post marks one transaction posted and applies its balance change. It reports
+// false if the transaction stopped being due since it was listed.
+func (p *Poster) post(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	var txn model.Transaction
+	err := p.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+
+		var err error
+		txn, err = txRepos.Transactions.Post(txCtx, id, now)
+		if err != nil {
+			return err
+		}
+		return applyBalanceChange(txCtx, txRepos.Accounts, txn.Type, txn.Amount, txn.AccountID, txn.DestinationAccountID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	p.webhooks.Publish(txn.HouseholdID, model.WebhookEventTransactionPosted, txn)
+	p.alerts.TransactionPosted(ctx, txn)
+	return true, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+// MaxReportDays caps the range of a daily report.
+const MaxReportDays = 366
+
+// MaxReportMonths caps the range of a monthly report.
+const MaxReportMonths = 24
+
+var ErrInvalidReportRange = errors.New("invalid report range")
+
+// ReportService serves read-only analytics over a household's transactions.
+type ReportService struct {
+	repos *repository.Repos
+}
+
+func NewReportService(repos *repository.Repos) *ReportService {
+	return &ReportService{repos: repos}
+}
+
+// ByMember totals income and expense per member and account between from and
+// to (either may be nil), for splitting shared costs. Transfers are left out.
+func (s *ReportService) ByMember(ctx context.Context, householdID uuid.UUID, from, to *time.Time) ([]model.MemberContribution, error) {
+	if from != nil && to != nil && from.After(*to) {
+		return nil, fmt.Errorf("%w: from is after to", ErrInvalidReportRange)
+	}
+	out, err := s.repos.Transactions.SumByMember(ctx, householdID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("sum transactions by member: %w", err)
+	}
+	return out, nil
+}
+
+// Daily totals income and expense for each calendar day from through to
+// (inclusive dates in the household's timezone), with zeros for days without
+// transactions. Only accounts in currency count; empty means the household's
+// default currency. Transfers are left out.
+func (s *ReportService) Daily(ctx context.Context, householdID uuid.UUID, from, to time.Time, currency string) (*model.DailyReport, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from is after to", ErrInvalidReportRange)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > MaxReportDays {
+		return nil, fmt.Errorf("%w: at most %d days", ErrInvalidReportRange, MaxReportDays)
+	}
+
+	hh, loc, currency, err := s.reportHousehold(ctx, householdID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repos.Transactions.SumByDay(ctx, repository.SumByDayParams{
+		HouseholdID: householdID,
+		Timezone:    hh.Timezone,
+		Currency:    currency,
+		From:        time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc),
+		To:          time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sum transactions by day: %w", err)
+	}
+	byDate := make(map[string]model.DailyTotal, len(totals))
+	for _, t := range totals {
+		byDate[t.Date] = t
+	}
+
+	report := &model.DailyReport{Currency: currency, Days: make([]model.DailyTotal, 0, days)}
+	for i := range days {
+		date := from.AddDate(0, 0, i).Format(time.DateOnly)
+		t, ok := byDate[date]
+		if !ok {
+			t = model.DailyTotal{Date: date, Income: decimal.Zero, Expense: decimal.Zero}
+		}
+		report.Days = append(report.Days, t)
+	}
+	return report, nil
+}
+
+// Monthly totals income and expense for each fiscal month from the one
+// containing from through the one containing to (dates in the household's
+// timezone), with zeros for months without transactions. Months start on the
+// household's fiscal month start day. Currency and transfers are handled as in
+// Daily.
+func (s *ReportService) Monthly(ctx context.Context, householdID uuid.UUID, from, to time.Time, currency string) (*model.MonthlyReport, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from is after to", ErrInvalidReportRange)
+	}
+	hh, loc, currency, err := s.reportHousehold(ctx, householdID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	start, _ := fiscalMonth(time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc), loc, hh.FiscalMonthStartDay)
+	bounds := []time.Time{start} // start of each month, then the end of the last
+	for !start.After(last) {
+		if len(bounds) > MaxReportMonths {
+			return nil, fmt.Errorf("%w: at most %d months", ErrInvalidReportRange, MaxReportMonths)
+		}
+		_, start = fiscalMonth(start, loc, hh.FiscalMonthStartDay)
+		bounds = append(bounds, start)
+	}
+
+	totals, err := s.repos.Transactions.SumByDay(ctx, repository.SumByDayParams{
+		HouseholdID: householdID,
+		Timezone:    hh.Timezone,
+		Currency:    currency,
+		From:        bounds[0],
+		To:          bounds[len(bounds)-1],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sum transactions by day: %w", err)
+	}
+
+	report := &model.MonthlyReport{
+		Currency:            currency,
+		FiscalMonthStartDay: hh.FiscalMonthStartDay,
+		Months:              make([]model.MonthlyTotal, len(bounds)-1),
+	}
+	for i := range report.Months {
+		report.Months[i] = model.MonthlyTotal{
+			Start:   bounds[i].Format(time.DateOnly),
+			End:     bounds[i+1].AddDate(0, 0, -1).Format(time.DateOnly),
+			Income:  decimal.Zero,
+			Expense: decimal.Zero,
+		}
+	}
+	// Both lists are in date order and dates compare as strings.
+	i := 0
+	for _, t := range totals {
+		for i < len(report.Months)-1 && t.Date > report.Months[i].End {
+			i++
+		}
+		report.Months[i].Income = report.Months[i].Income.Add(t.Income)
+		report.Months[i].Expense = report.Months[i].Expense.Add(t.Expense)
+	}
+	return report, nil
+}
+
+// BudgetStatus reports each budget's spending in the fiscal month containing
+// date (in the household's timezone; nil means today).
+func (s *ReportService) BudgetStatus(ctx context.Context, householdID uuid.UUID, date *time.Time) (*model.BudgetStatusReport, error) {
+	hh, loc, _, err := s.reportHousehold(ctx, householdID, "")
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	if date != nil {
+		at = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	}
+	from, to := fiscalMonth(at, loc, hh.FiscalMonthStartDay)
+
+	budgets, err := s.repos.Budgets.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list budgets: %w", err)
+	}
+	report := &model.BudgetStatusReport{
+		PeriodStart: from.Format(time.DateOnly),
+		PeriodEnd:   to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Budgets:     make([]model.BudgetStatus, 0, len(budgets)),
+	}
+	for _, b := range budgets {
+		spent, err := s.repos.Budgets.CategorySpend(ctx, householdID, b.CategoryID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("sum category spend: %w", err)
+		}
+		report.Budgets = append(report.Budgets, model.BudgetStatus{
+			BudgetID:   b.ID,
+			CategoryID: b.CategoryID,
+			Amount:     b.Amount,
+			Spent:      spent,
+			Remaining:  b.Amount.Sub(spent),
+		})
+	}
+	return report, nil
+}
+
+// reportHousehold loads the household a report is for, its location and the
+// report currency: currency normalized, or the household's default if empty.
+func (s *ReportService) reportHousehold(ctx context.Context, householdID uuid.UUID, currency string) (model.Household, *time.Location, string, error) {
+	hh, err := s.repos.Households.GetByID(ctx, householdID)
+	if err != nil {
+		return hh, nil, "", notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	loc, err := time.LoadLocation(hh.Timezone)
+	if err != nil {
+		return hh, nil, "", fmt.Errorf("load timezone %q: %w", hh.Timezone, err)
+	}
+	if currency == "" {
+		return hh, loc, hh.DefaultCurrency, nil
+	}
+	currency, err = normalizeCurrency(currency)
+	return hh, loc, currency, err
+}
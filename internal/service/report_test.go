@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+)
+
+func TestDailyUsesHouseholdDayBoundaries(t *testing.T) {
+	f := newFakes()
+	hh := uuid.New()
+	f.households.byID[hh] = model.Household{ID: hh, Timezone: "Europe/Kyiv", DefaultCurrency: "UAH"}
+	f.transactions.dailyTotals = []model.DailyTotal{
+		{Date: "2024-02-01", Income: decimal.Zero, Expense: decimal.RequireFromString("12.50")},
+	}
+	svc := NewReportService(f.repos)
+
+	from := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	report, err := svc.Daily(context.Background(), hh, from, to, "")
+	if err != nil {
+		t.Fatalf("Daily: %v", err)
+	}
+
+	if len(f.transactions.sumByDay) != 1 {
+		t.Fatalf("SumByDay called %d times, want 1", len(f.transactions.sumByDay))
+	}
+	p := f.transactions.sumByDay[0]
+	// Kyiv is UTC+2 in winter: the range runs from local midnight on the 31st
+	// to local midnight on the 2nd.
+	wantFrom := time.Date(2024, 1, 30, 22, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2024, 2, 1, 22, 0, 0, 0, time.UTC)
+	if !p.From.Equal(wantFrom) || !p.To.Equal(wantTo) {
+		t.Errorf("range [%s, %s), want [%s, %s)", p.From.UTC(), p.To.UTC(), wantFrom, wantTo)
+	}
+	if p.Timezone != "Europe/Kyiv" || p.Currency != "UAH" {
+		t.Errorf("timezone %q currency %q, want Europe/Kyiv UAH", p.Timezone, p.Currency)
+	}
+
+	if len(report.Days) != 2 {
+		t.Fatalf("got %d days, want 2", len(report.Days))
+	}
+	if d := report.Days[0]; d.Date != "2024-01-31" || !d.Expense.IsZero() {
+		t.Errorf("day 0 = %+v, want 2024-01-31 with no spending", d)
+	}
+	if d := report.Days[1]; d.Date != "2024-02-01" || !d.Expense.Equal(decimal.RequireFromString("12.50")) {
+		t.Errorf("day 1 = %+v, want 2024-02-01 with 12.50 spent", d)
+	}
+}
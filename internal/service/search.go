@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var ErrInvalidSearch = errors.New("q must be 1-100 characters")
+
+const (
+	// MaxSearchResults caps the matches returned per kind, keeping searches fast.
+	MaxSearchResults = 10
+	maxSearchLen     = 100
+)
+
+// SearchService finds accounts and transactions of a household by text.
+type SearchService struct {
+	repos *repository.Repos
+}
+
+func NewSearchService(repos *repository.Repos) *SearchService {
+	return &SearchService{repos: repos}
+}
+
+// Search returns the accounts whose name contains q, followed by the newest
+// transactions whose description, note or tags contain it, ignoring case; at
+// most MaxSearchResults of each.
+func (s *SearchService) Search(ctx context.Context, householdID uuid.UUID, q string) ([]model.SearchResult, error) {
+	q = strings.TrimSpace(q)
+	if q == "" || utf8.RuneCountInString(q) > maxSearchLen {
+		return nil, ErrInvalidSearch
+	}
+
+	accounts, err := s.repos.Accounts.Search(ctx, householdID, q, MaxSearchResults)
+	if err != nil {
+		return nil, fmt.Errorf("search accounts: %w", err)
+	}
+	txns, err := s.repos.Transactions.Search(ctx, householdID, q, MaxSearchResults)
+	if err != nil {
+		return nil, fmt.Errorf("search transactions: %w", err)
+	}
+
+	out := make([]model.SearchResult, 0, len(accounts)+len(txns))
+	for i := range accounts {
+		out = append(out, model.SearchResult{Type: model.SearchResultAccount, Account: &accounts[i]})
+	}
+	for i := range txns {
+		out = append(out, model.SearchResult{Type: model.SearchResultTransaction, Transaction: &txns[i]})
+	}
+	return out, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrInvalidTemplate  = errors.New("invalid template")
+)
+
+// TemplateService manages transaction templates and creates transactions from them.
+type TemplateService struct {
+	repos *repository.Repos
+	txns  *TransactionService
+}
+
+func NewTemplateService(repos *repository.Repos, txns *TransactionService) *TemplateService {
+	return &TemplateService{repos: repos, txns: txns}
+}
+
+func (s *TemplateService) Create(ctx context.Context, householdID uuid.UUID, req model.TemplateRequest) (*model.TransactionTemplate, error) {
+	params, err := s.templateParams(ctx, householdID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := s.repos.Templates.Create(ctx, params)
+	if err != nil {
+		if mapped := constraintError(err, nil, ErrAccountNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, fmt.Errorf("create template: %w", err)
+	}
+	return &tpl, nil
+}
+
+func (s *TemplateService) List(ctx context.Context, householdID uuid.UUID) ([]model.TransactionTemplate, error) {
+	templates, err := s.repos.Templates.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) Get(ctx context.Context, id, householdID uuid.UUID) (*model.TransactionTemplate, error) {
+	tpl, err := s.repos.Templates.GetByID(ctx, id, householdID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrTemplateNotFound, "get template")
+	}
+	return &tpl, nil
+}
+
+// Update replaces every field of a template.
+func (s *TemplateService) Update(ctx context.Context, id, householdID uuid.UUID, req model.TemplateRequest) (*model.TransactionTemplate, error) {
+	params, err := s.templateParams(ctx, householdID, req)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = id
+
+	tpl, err := s.repos.Templates.Update(ctx, params)
+	if err != nil {
+		if mapped := constraintError(err, nil, ErrAccountNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, notFoundOr(err, ErrTemplateNotFound, "update template")
+	}
+	return &tpl, nil
+}
+
+func (s *TemplateService) Delete(ctx context.Context, id, householdID uuid.UUID) error {
+	deleted, err := s.repos.Templates.Delete(ctx, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if !deleted {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// Apply creates a transaction from a template, with req overriding the template's
+// fields. The result must be a complete transaction: a template without an amount
+// or account needs them in req.
+func (s *TemplateService) Apply(ctx context.Context, id, householdID, userID uuid.UUID, req model.TransactionOverrides) (*model.Transaction, error) {
+	tpl, err := s.repos.Templates.GetByID(ctx, id, householdID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrTemplateNotFound, "get template")
+	}
+
+	txnReq := model.CreateTransactionRequest{
+		Type:                 tpl.Type,
+		Description:          tpl.Description,
+		DestinationAccountID: tpl.DestinationAccountID,
+		Tags:                 tpl.Tags,
+		Note:                 tpl.Note,
+	}
+	if tpl.Amount != nil {
+		txnReq.Amount = tpl.Amount.String()
+	}
+	if tpl.AccountID != nil {
+		txnReq.AccountID = *tpl.AccountID
+	}
+
+	applyOverrides(&txnReq, req)
+
+	if txnReq.Amount == "" {
+		return nil, fmt.Errorf("%w: amount is required", ErrInvalidAmount)
+	}
+	if txnReq.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("%w: account_id is required", ErrInvalidTemplate)
+	}
+	if err := s.checkAccounts(ctx, householdID, &txnReq.AccountID, txnReq.DestinationAccountID); err != nil {
+		return nil, err
+	}
+
+	return s.txns.Create(ctx, householdID, userID, txnReq)
+}
+
+// templateParams validates a template request. Accounts, when given, must belong
+// to the household.
+func (s *TemplateService) templateParams(ctx context.Context, householdID uuid.UUID, req model.TemplateRequest) (repository.TemplateParams, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return repository.TemplateParams{}, fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	switch req.Type {
+	case model.TransactionTypeIncome, model.TransactionTypeExpense, model.TransactionTypeTransfer:
+	default:
+		return repository.TemplateParams{}, fmt.Errorf("%w: type must be income, expense or transfer", ErrInvalidTemplate)
+	}
+
+	var amount *decimal.Decimal
+	if req.Amount != nil {
+		a, err := parseAmount(*req.Amount, s.txns.txnCfg.MaxAmount)
+		if err != nil {
+			return repository.TemplateParams{}, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+		}
+		if !a.IsPositive() {
+			return repository.TemplateParams{}, fmt.Errorf("%w: amount must be a positive number", ErrInvalidAmount)
+		}
+		amount = &a
+	}
+
+	if err := s.checkAccounts(ctx, householdID, req.AccountID, req.DestinationAccountID); err != nil {
+		return repository.TemplateParams{}, err
+	}
+
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return repository.TemplateParams{
+		HouseholdID:          householdID,
+		Name:                 name,
+		Type:                 req.Type,
+		Description:          req.Description,
+		Amount:               amount,
+		AccountID:            req.AccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Tags:                 tags,
+		Note:                 req.Note,
+	}, nil
+}
+
+// checkAccounts returns ErrAccountNotFound unless every non-nil account belongs
+// to the household.
+func (s *TemplateService) checkAccounts(ctx context.Context, householdID uuid.UUID, ids ...*uuid.UUID) error {
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+		if _, err := s.repos.Accounts.GetByID(ctx, *id, householdID); err != nil {
+			return notFoundOr(err, ErrAccountNotFound, "get account")
+		}
+	}
+	return nil
+}
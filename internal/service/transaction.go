@@ -4,78 +4,180 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 
+	"github.com/howallet/howallet/internal/config"
 	"github.com/howallet/howallet/internal/model"
 	"github.com/howallet/howallet/internal/repository"
-	"github.com/howallet/howallet/internal/repository/postgres"
 )
 
 var (
 	ErrTransactionNotFound = errors.New("transaction not found")
 	ErrTransferMissingDest = errors.New("transfer requires destination_account_id")
+	ErrSameAccountTransfer = errors.New("transfer source and destination must be different accounts")
+	ErrCurrencyMismatch    = errors.New("transfer accounts must have the same currency")
+	ErrInvalidSplits       = errors.New("split amounts must be positive and sum to the transaction amount")
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInvalidTagRename    = errors.New("from and to must be different, non-empty tags within the length limit")
+	ErrInvalidTags         = errors.New("invalid tags")
+	ErrInvalidTransactedAt = errors.New("invalid transacted_at")
+	ErrInvalidBatch        = errors.New("invalid batch")
+	ErrDailyLimitExceeded  = errors.New("transaction exceeds the account's daily limit")
+	// ErrConflict means the record changed after the client read it (its
+	// expected_updated_at no longer matches); the client should reload and retry.
+	ErrConflict = errors.New("modified by someone else, reload and try again")
+	// ErrTransactionNotSaved means the database refused the commit and rolled
+	// back: neither the transaction nor any account balance was changed.
+	ErrTransactionNotSaved = errors.New("changes were not saved, please retry")
+	// ErrTransactionOutcomeUnknown means the commit got no answer: the changes
+	// may have been saved, so blindly retrying a create can duplicate it.
+	ErrTransactionOutcomeUnknown = errors.New("changes may or may not have been saved, reload before retrying")
 )
 
+// MaxBatchSize caps the number of transactions a batch operation may touch.
+const MaxBatchSize = 200
+
 type TransactionService struct {
-	repos *postgres.Repos
+	repos         *repository.Repos
+	webhooks      *WebhookDispatcher
+	notifications *NotificationService
+	alerts        *AlertService
+	txnCfg        config.TransactionConfig
+	pagination    config.PaginationConfig
 }
 
-func NewTransactionService(repos *postgres.Repos) *TransactionService {
-	return &TransactionService{repos: repos}
+// NewTransactionService creates the service. txnCfg bounds how far ahead of now
+// a transaction may be dated, its amount and how many tags it may carry.
+func NewTransactionService(repos *repository.Repos, webhooks *WebhookDispatcher, notifications *NotificationService, alerts *AlertService, txnCfg config.TransactionConfig, pagination config.PaginationConfig) *TransactionService {
+	return &TransactionService{repos: repos, webhooks: webhooks, notifications: notifications, alerts: alerts, txnCfg: txnCfg, pagination: pagination}
 }
 
 // Create creates a transaction and updates account balances atomically.
 func (s *TransactionService) Create(ctx context.Context, householdID, userID uuid.UUID, req model.CreateTransactionRequest) (*model.Transaction, error) {
-	amount, err := decimal.NewFromString(req.Amount)
+	params, err := newCreateTransactionParams(householdID, userID, req, s.txnCfg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid amount: %w", err)
-	}
-
-	if req.Type == model.TransactionTypeTransfer && req.DestinationAccountID == nil {
-		return nil, ErrTransferMissingDest
-	}
-
-	tags := req.Tags
-	if tags == nil {
-		tags = []string{}
+		return nil, err
 	}
 
 	var txn model.Transaction
 	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+		txRepos := repository.TxReposFromCtx(txCtx)
+		if err := checkDailyLimit(txCtx, txRepos, params); err != nil {
+			return err
+		}
 
 		var txErr error
-		txn, txErr = txRepos.Transactions.Create(txCtx, repository.CreateTransactionParams{
-			HouseholdID:          householdID,
-			Type:                 req.Type,
-			Description:          req.Description,
-			Amount:               amount,
-			AccountID:            req.AccountID,
-			DestinationAccountID: req.DestinationAccountID,
-			Tags:                 tags,
-			Note:                 req.Note,
-			TransactedAt:         req.TransactedAt,
-			CreatedBy:            userID,
-		})
-		if txErr != nil {
-			return fmt.Errorf("create transaction: %w", txErr)
+		if txn, txErr = insertTransaction(txCtx, txRepos, params); txErr != nil {
+			return txErr
 		}
-
-		return applyBalanceChange(txCtx, txRepos.Accounts, req.Type, amount, req.AccountID, req.DestinationAccountID)
+		txn.Balances, txErr = accountBalances(txCtx, txRepos.Accounts, householdID, transactionAccounts(txn)...)
+		return txErr
 	})
 	if err != nil {
-		return nil, err
+		return nil, commitError(err)
 	}
 
+	s.webhooks.Publish(householdID, model.WebhookEventTransactionCreated, txn)
+	s.notifications.TransactionCreated(ctx, txn)
+	s.alerts.TransactionCreated(ctx, txn)
 	return &txn, nil
 }
 
-// List returns paginated transactions with filters.
+// newCreateTransactionParams validates a create request and converts it to repository params.
+// A missing transacted_at defaults to now; a future one makes the transaction scheduled.
+func newCreateTransactionParams(householdID, userID uuid.UUID, req model.CreateTransactionRequest, txnCfg config.TransactionConfig) (repository.CreateTransactionParams, error) {
+	amount, err := parseAmount(req.Amount, txnCfg.MaxAmount)
+	if err != nil {
+		return repository.CreateTransactionParams{}, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+
+	transactedAt := req.TransactedAt
+	if transactedAt.IsZero() {
+		transactedAt = time.Now()
+	}
+	if err := checkTransactedAt(transactedAt, txnCfg.MaxFuture); err != nil {
+		return repository.CreateTransactionParams{}, err
+	}
+
+	if err := checkTransferAccounts(req.Type, req.AccountID, req.DestinationAccountID); err != nil {
+		return repository.CreateTransactionParams{}, err
+	}
+
+	splits, err := parseSplits(amount, req.Splits, txnCfg.MaxAmount)
+	if err != nil {
+		return repository.CreateTransactionParams{}, err
+	}
+
+	tags, err := normalizeTags(req.Tags, txnCfg)
+	if err != nil {
+		return repository.CreateTransactionParams{}, err
+	}
+
+	return repository.CreateTransactionParams{
+		HouseholdID:          householdID,
+		Type:                 req.Type,
+		Description:          req.Description,
+		Amount:               amount,
+		AccountID:            req.AccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Tags:                 tags,
+		Note:                 req.Note,
+		TransactedAt:         transactedAt,
+		CreatedBy:            userID,
+		Posted:               isDue(transactedAt),
+		Splits:               splits,
+	}, nil
+}
+
+// insertTransaction creates a transaction with its splits, applies its balance change
+// unless it is scheduled, and records it in the audit log. repos must be the transactional repos of the surrounding RunInTx.
+func insertTransaction(ctx context.Context, repos *repository.Repos, params repository.CreateTransactionParams) (model.Transaction, error) {
+	if err := checkTransferCurrencies(ctx, repos.Accounts, params.HouseholdID, params.Type, params.AccountID, params.DestinationAccountID); err != nil {
+		return model.Transaction{}, err
+	}
+	var err error
+	if params.Tags, err = foldTags(ctx, repos.Households, params.HouseholdID, params.Tags); err != nil {
+		return model.Transaction{}, err
+	}
+
+	txn, err := repos.Transactions.Create(ctx, params)
+	if err != nil {
+		// A missing account or destination account violates its foreign key.
+		if mapped := constraintError(err, nil, ErrAccountNotFound); mapped != nil {
+			return model.Transaction{}, mapped
+		}
+		return model.Transaction{}, fmt.Errorf("create transaction: %w", err)
+	}
+
+	if txn.Splits, err = storeSplits(ctx, repos, params.HouseholdID, txn.ID, params.Splits); err != nil {
+		return model.Transaction{}, err
+	}
+
+	if params.Posted {
+		if err := applyBalanceChange(ctx, repos.Accounts, params.Type, params.Amount, params.AccountID, params.DestinationAccountID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+
+	if err := recordAudit(ctx, repos.Audit, params.HouseholdID, params.CreatedBy, model.AuditActionTransactionCreated, auditEntityTransaction, txn.ID, nil, txn); err != nil {
+		return model.Transaction{}, err
+	}
+	return txn, nil
+}
+
+// List returns paginated transactions with filters. The limit is clamped to the
+// configured maximum; the response carries the effective one.
 func (s *TransactionService) List(ctx context.Context, householdID uuid.UUID, q model.ListTransactionsQuery) (*model.PaginatedResponse, error) {
-	if q.Limit <= 0 {
-		q.Limit = 50
+	q.Limit = s.pagination.Limit(q.Limit)
+	if err := s.checkCreatedByFilter(ctx, householdID, q.CreatedBy); err != nil {
+		return nil, err
 	}
 
 	params := repository.ListTransactionsParams{
@@ -83,7 +185,10 @@ func (s *TransactionService) List(ctx context.Context, householdID uuid.UUID, q
 		From:        q.From,
 		To:          q.To,
 		Type:        q.Type,
-		AccountID:   q.AccountID,
+		AccountIDs:  q.AccountIDs,
+		Flagged:     q.Flagged,
+		CreatedBy:   q.CreatedBy,
+		Posted:      q.Posted,
 		Limit:       q.Limit,
 		Offset:      q.Offset,
 	}
@@ -98,103 +203,598 @@ func (s *TransactionService) List(ctx context.Context, householdID uuid.UUID, q
 		From:        q.From,
 		To:          q.To,
 		Type:        q.Type,
-		AccountID:   q.AccountID,
+		AccountIDs:  q.AccountIDs,
+		Flagged:     q.Flagged,
+		CreatedBy:   q.CreatedBy,
+		Posted:      q.Posted,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("count transactions: %w", err)
 	}
 
-	return &model.PaginatedResponse{
-		Data:   txns,
-		Total:  total,
-		Limit:  q.Limit,
-		Offset: q.Offset,
-	}, nil
+	if q.ExpandCreatedBy {
+		refs := make([]*model.Transaction, len(txns))
+		for i := range txns {
+			refs[i] = &txns[i]
+		}
+		if err := s.ExpandCreators(ctx, householdID, refs...); err != nil {
+			return nil, err
+		}
+	}
+
+	return model.NewPaginatedResponse(txns, total, q.Limit, q.Offset), nil
 }
 
-// Get returns a single transaction.
+// ExpandCreators sets Creator on each transaction whose creator is still a
+// member of the household; the others keep only CreatedBy.
+func (s *TransactionService) ExpandCreators(ctx context.Context, householdID uuid.UUID, txns ...*model.Transaction) error {
+	var ids []uuid.UUID
+	for _, t := range txns {
+		if !slices.Contains(ids, t.CreatedBy) {
+			ids = append(ids, t.CreatedBy)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	members, err := s.repos.Households.ListMembers(ctx, repository.ListMembersParams{
+		HouseholdID: householdID,
+		UserIDs:     ids,
+	})
+	if err != nil {
+		return fmt.Errorf("list creators: %w", err)
+	}
+	creators := make(map[uuid.UUID]*model.UserSummary, len(members))
+	for _, m := range members {
+		creators[m.UserID] = &model.UserSummary{ID: m.UserID, Name: m.UserName, Email: m.Email}
+	}
+	for _, t := range txns {
+		t.Creator = creators[t.CreatedBy]
+	}
+	return nil
+}
+
+// Summary totals the transactions matching q's filters per currency; paging is ignored.
+func (s *TransactionService) Summary(ctx context.Context, householdID uuid.UUID, q model.ListTransactionsQuery) ([]model.TransactionSummary, error) {
+	if err := s.checkCreatedByFilter(ctx, householdID, q.CreatedBy); err != nil {
+		return nil, err
+	}
+	summary, err := s.repos.Transactions.Summarize(ctx, repository.CountTransactionsParams{
+		HouseholdID: householdID,
+		From:        q.From,
+		To:          q.To,
+		Type:        q.Type,
+		AccountIDs:  q.AccountIDs,
+		Flagged:     q.Flagged,
+		CreatedBy:   q.CreatedBy,
+		Posted:      q.Posted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("summarize transactions: %w", err)
+	}
+	return summary, nil
+}
+
+// ListTags returns the distinct tags used in the household, for autocompletion.
+func (s *TransactionService) ListTags(ctx context.Context, householdID uuid.UUID, prefix string) ([]string, error) {
+	tags, err := s.repos.Transactions.ListTags(ctx, householdID, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// RenameTag renames from to to on every transaction in the household. Renaming to a
+// tag that already exists merges the two. Returns the number of transactions changed.
+// Both tags are lower-cased unless the household keeps tags case-sensitive.
+func (s *TransactionService) RenameTag(ctx context.Context, householdID uuid.UUID, from, to string) (int64, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" || from == to || utf8.RuneCountInString(to) > s.txnCfg.MaxTagLength {
+		return 0, ErrInvalidTagRename
+	}
+	folded, err := foldTags(ctx, s.repos.Households, householdID, []string{from, to})
+	if err != nil {
+		return 0, err
+	}
+	if len(folded) < 2 { // from and to differ only in case
+		return 0, ErrInvalidTagRename
+	}
+	from, to = folded[0], folded[1]
+
+	n, err := s.repos.Transactions.RenameTag(ctx, householdID, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("rename tag: %w", err)
+	}
+	return n, nil
+}
+
+// checkCreatedByFilter makes sure a created_by filter names a household member.
+func (s *TransactionService) checkCreatedByFilter(ctx context.Context, householdID uuid.UUID, createdBy *uuid.UUID) error {
+	if createdBy == nil {
+		return nil
+	}
+	isMember, err := s.repos.Households.IsMember(ctx, householdID, *createdBy)
+	if err != nil {
+		return fmt.Errorf("check member: %w", err)
+	}
+	if !isMember {
+		return ErrNotMember
+	}
+	return nil
+}
+
+// Get returns a single transaction including its splits.
 func (s *TransactionService) Get(ctx context.Context, id, householdID uuid.UUID) (*model.Transaction, error) {
 	txn, err := s.repos.Transactions.GetByID(ctx, id, householdID)
 	if err != nil {
-		return nil, ErrTransactionNotFound
+		return nil, notFoundOr(err, ErrTransactionNotFound, "get transaction")
+	}
+	if txn.Splits, err = s.repos.Transactions.ListSplits(ctx, id); err != nil {
+		return nil, fmt.Errorf("list splits: %w", err)
 	}
 	return &txn, nil
 }
 
-// Update modifies a transaction, rolling back old balances and applying new ones.
-func (s *TransactionService) Update(ctx context.Context, id, householdID, userID uuid.UUID, req model.UpdateTransactionRequest) (*model.Transaction, error) {
-	newAmount, err := decimal.NewFromString(req.Amount)
+// Duplicate creates a copy of a transaction dated now, with o overriding any of
+// its fields, and applies its balance change. Splits are copied unless the amount
+// is overridden, since they would no longer add up.
+func (s *TransactionService) Duplicate(ctx context.Context, id, householdID, userID uuid.UUID, o model.TransactionOverrides) (*model.Transaction, error) {
+	src, err := s.Get(ctx, id, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := model.CreateTransactionRequest{
+		Type:                 src.Type,
+		Description:          src.Description,
+		Amount:               src.Amount.String(),
+		AccountID:            src.AccountID,
+		DestinationAccountID: src.DestinationAccountID,
+		Tags:                 src.Tags,
+		Note:                 src.Note,
+	}
+	if o.Amount == nil {
+		for _, sp := range src.Splits {
+			req.Splits = append(req.Splits, model.SplitRequest{CategoryID: sp.CategoryID, Amount: sp.Amount.String()})
+		}
+	}
+	applyOverrides(&req, o)
+
+	return s.Create(ctx, householdID, userID, req)
+}
+
+// Flag marks a transaction for review by household members.
+func (s *TransactionService) Flag(ctx context.Context, id, householdID uuid.UUID, reason *string) (*model.Transaction, error) {
+	txn, err := s.repos.Transactions.SetFlag(ctx, id, householdID, true, reason)
+	if err != nil {
+		return nil, notFoundOr(err, ErrTransactionNotFound, "flag transaction")
+	}
+	return &txn, nil
+}
+
+// Unflag clears the review flag and its reason.
+func (s *TransactionService) Unflag(ctx context.Context, id, householdID uuid.UUID) (*model.Transaction, error) {
+	txn, err := s.repos.Transactions.SetFlag(ctx, id, householdID, false, nil)
 	if err != nil {
-		return nil, fmt.Errorf("invalid amount: %w", err)
+		return nil, notFoundOr(err, ErrTransactionNotFound, "unflag transaction")
 	}
+	return &txn, nil
+}
 
-	if req.Type == model.TransactionTypeTransfer && req.DestinationAccountID == nil {
-		return nil, ErrTransferMissingDest
+// Update modifies a transaction, rolling back old balances and applying new ones.
+// Splits are replaced when req.Splits is set (an empty list clears them); otherwise
+// the existing splits are kept, which is only allowed if the amount is unchanged.
+func (s *TransactionService) Update(ctx context.Context, id, householdID, userID uuid.UUID, req model.UpdateTransactionRequest) (*model.Transaction, error) {
+	// Updates replace the whole transaction, so the date is required rather than
+	// silently moved to today.
+	if req.TransactedAt.IsZero() {
+		return nil, fmt.Errorf("%w: required", ErrInvalidTransactedAt)
 	}
 
-	tags := req.Tags
-	if tags == nil {
-		tags = []string{}
+	return s.update(ctx, id, householdID, userID, func(model.Transaction) model.UpdateTransactionRequest {
+		return req
+	})
+}
+
+// Patch changes only the fields set in p and keeps the rest. Balances are only
+// touched when the type, amount or accounts change. A transaction that stops
+// being a transfer loses its destination account. Tags left out are kept as
+// stored, without re-checking them against the tag limits.
+func (s *TransactionService) Patch(ctx context.Context, id, householdID, userID uuid.UUID, p model.PatchTransactionRequest) (*model.Transaction, error) {
+	return s.update(ctx, id, householdID, userID, func(old model.Transaction) model.UpdateTransactionRequest {
+		return mergePatch(old, p)
+	})
+}
+
+// mergePatch overlays the set fields of p on old, producing a full update request.
+func mergePatch(old model.Transaction, p model.PatchTransactionRequest) model.UpdateTransactionRequest {
+	req := model.UpdateTransactionRequest{
+		Type:                 old.Type,
+		Description:          old.Description,
+		Amount:               old.Amount.String(),
+		AccountID:            old.AccountID,
+		DestinationAccountID: old.DestinationAccountID,
+		Tags:                 old.Tags,
+		Note:                 old.Note,
+		TransactedAt:         old.TransactedAt,
+		Splits:               p.Splits,
+		ExpectedUpdatedAt:    p.ExpectedUpdatedAt,
+	}
+	if p.Type != nil {
+		req.Type = *p.Type
+	}
+	if p.Description != nil {
+		req.Description = *p.Description
+	}
+	if p.Amount != nil {
+		req.Amount = *p.Amount
+	}
+	if p.AccountID != nil {
+		req.AccountID = *p.AccountID
+	}
+	if p.DestinationAccountID != nil {
+		req.DestinationAccountID = p.DestinationAccountID
+	}
+	if req.Type != model.TransactionTypeTransfer {
+		req.DestinationAccountID = nil
+	}
+	if p.Tags != nil {
+		req.Tags = p.Tags
+	}
+	if p.Note != nil {
+		req.Note = p.Note
+	}
+	if p.TransactedAt != nil {
+		req.TransactedAt = *p.TransactedAt
 	}
+	return req
+}
 
+// update loads the transaction, asks build for the full new state and writes it
+// in one database transaction.
+func (s *TransactionService) update(ctx context.Context, id, householdID, userID uuid.UUID, build func(old model.Transaction) model.UpdateTransactionRequest) (*model.Transaction, error) {
 	var txn model.Transaction
-	err = s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
 
-		// Get old transaction to reverse balance
 		old, txErr := txRepos.Transactions.GetByID(txCtx, id, householdID)
 		if txErr != nil {
-			return ErrTransactionNotFound
+			return notFoundOr(txErr, ErrTransactionNotFound, "get transaction")
+		}
+		if old.Splits, txErr = txRepos.Transactions.ListSplits(txCtx, id); txErr != nil {
+			return fmt.Errorf("list splits: %w", txErr)
 		}
 
-		// Reverse old balance
-		if txErr = reverseBalanceChange(txCtx, txRepos.Accounts, old.Type, old.Amount, old.AccountID, old.DestinationAccountID); txErr != nil {
+		if txn, txErr = updateTransaction(txCtx, txRepos, old, userID, build(old), s.txnCfg); txErr != nil {
 			return txErr
 		}
+		// The old accounts changed too if the transaction moved off them.
+		txn.Balances, txErr = accountBalances(txCtx, txRepos.Accounts, householdID,
+			append(transactionAccounts(old), transactionAccounts(txn)...)...)
+		return txErr
+	})
+	if err != nil {
+		return nil, commitError(err)
+	}
 
-		// Update transaction
-		txn, txErr = txRepos.Transactions.Update(txCtx, repository.UpdateTransactionParams{
-			ID:                   id,
-			HouseholdID:          householdID,
-			Type:                 req.Type,
-			Description:          req.Description,
-			Amount:               newAmount,
-			AccountID:            req.AccountID,
-			DestinationAccountID: req.DestinationAccountID,
-			Tags:                 tags,
-			Note:                 req.Note,
-			TransactedAt:         req.TransactedAt,
-		})
-		if txErr != nil {
-			return fmt.Errorf("update transaction: %w", txErr)
+	return &txn, nil
+}
+
+// updateTransaction validates req and replaces old with it, moving the balance
+// effect if the type, amount or accounts changed, or if the new date posts or
+// unschedules it. repos must be the transactional repos of the surrounding RunInTx.
+func updateTransaction(ctx context.Context, repos *repository.Repos, old model.Transaction, userID uuid.UUID, req model.UpdateTransactionRequest, txnCfg config.TransactionConfig) (model.Transaction, error) {
+	// Checked up front to fail before touching balances; the update's WHERE
+	// clause catches a change that races with this transaction.
+	if req.ExpectedUpdatedAt != nil && !old.UpdatedAt.Equal(*req.ExpectedUpdatedAt) {
+		return model.Transaction{}, ErrConflict
+	}
+
+	newAmount, err := parseAmount(req.Amount, txnCfg.MaxAmount)
+	if err != nil {
+		return model.Transaction{}, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+	if err := checkTransactedAt(req.TransactedAt, txnCfg.MaxFuture); err != nil {
+		return model.Transaction{}, err
+	}
+	if err := checkTransferAccounts(req.Type, req.AccountID, req.DestinationAccountID); err != nil {
+		return model.Transaction{}, err
+	}
+
+	var newSplits []repository.CreateSplitParams
+	if req.Splits != nil {
+		if newSplits, err = parseSplits(newAmount, req.Splits, txnCfg.MaxAmount); err != nil {
+			return model.Transaction{}, err
 		}
+	}
+
+	// Unchanged tags, as a patch that leaves them out carries, were accepted
+	// when stored and stay as they are, even if the limits have tightened.
+	tags := old.Tags
+	if !slices.Equal(req.Tags, old.Tags) {
+		if tags, err = normalizeTags(req.Tags, txnCfg); err != nil {
+			return model.Transaction{}, err
+		}
+		if tags, err = foldTags(ctx, repos.Households, old.HouseholdID, tags); err != nil {
+			return model.Transaction{}, err
+		}
+	}
 
-		// Apply new balance
-		return applyBalanceChange(txCtx, txRepos.Accounts, req.Type, newAmount, req.AccountID, req.DestinationAccountID)
+	moved := old.Type != req.Type || !old.Amount.Equal(newAmount) ||
+		old.AccountID != req.AccountID || !sameAccount(old.DestinationAccountID, req.DestinationAccountID)
+	posted := isDue(req.TransactedAt)
+	rebalance := moved || posted != old.Posted
+
+	if moved {
+		if err := checkTransferCurrencies(ctx, repos.Accounts, old.HouseholdID, req.Type, req.AccountID, req.DestinationAccountID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+	if rebalance && old.Posted {
+		// Reverse old balance
+		if err := reverseBalanceChange(ctx, repos.Accounts, old.Type, old.Amount, old.AccountID, old.DestinationAccountID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+
+	txn, err := repos.Transactions.Update(ctx, repository.UpdateTransactionParams{
+		ID:                   old.ID,
+		HouseholdID:          old.HouseholdID,
+		Type:                 req.Type,
+		Description:          req.Description,
+		Amount:               newAmount,
+		AccountID:            req.AccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Tags:                 tags,
+		Note:                 req.Note,
+		TransactedAt:         req.TransactedAt,
+		ExpectedUpdatedAt:    req.ExpectedUpdatedAt,
+		Posted:               posted,
+		WasPosted:            old.Posted,
 	})
 	if err != nil {
-		return nil, err
+		if mapped := constraintError(err, nil, ErrAccountNotFound); mapped != nil {
+			return model.Transaction{}, mapped
+		}
+		// old was read in this transaction, so no row means it changed since.
+		return model.Transaction{}, notFoundOr(err, ErrConflict, "update transaction")
 	}
 
-	return &txn, nil
+	switch {
+	case req.Splits != nil:
+		if txn.Splits, err = replaceSplits(ctx, repos, old.HouseholdID, old.ID, newSplits); err != nil {
+			return model.Transaction{}, err
+		}
+	case len(old.Splits) > 0 && !old.Amount.Equal(newAmount):
+		return model.Transaction{}, ErrInvalidSplits
+	default:
+		txn.Splits = old.Splits
+	}
+
+	// Apply new balance
+	if rebalance && posted {
+		if err := applyBalanceChange(ctx, repos.Accounts, req.Type, newAmount, req.AccountID, req.DestinationAccountID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+
+	if err := recordAudit(ctx, repos.Audit, old.HouseholdID, userID, model.AuditActionTransactionUpdated, auditEntityTransaction, old.ID, old, txn); err != nil {
+		return model.Transaction{}, err
+	}
+	return txn, nil
 }
 
-// Delete removes a transaction and reverses its balance effect.
-func (s *TransactionService) Delete(ctx context.Context, id, householdID uuid.UUID) error {
-	return s.repos.RunInTx(ctx, func(txCtx context.Context) error {
-		txRepos := postgres.TxReposFromCtx(txCtx)
+// sameAccount reports whether two optional account IDs are equal.
+func sameAccount(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
 
-		deleted, err := txRepos.Transactions.Delete(txCtx, id, householdID)
+// Delete removes a transaction and reverses its balance effect, returning the
+// resulting balances of its accounts.
+func (s *TransactionService) Delete(ctx context.Context, id, householdID, userID uuid.UUID) ([]model.AccountBalance, error) {
+	var (
+		deleted  model.Transaction
+		balances []model.AccountBalance
+	)
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+		var txErr error
+		if deleted, txErr = deleteTransaction(txCtx, txRepos, id, householdID, userID); txErr != nil {
+			return txErr
+		}
+		balances, txErr = accountBalances(txCtx, txRepos.Accounts, householdID, transactionAccounts(deleted)...)
+		return txErr
+	})
+	if err != nil {
+		return nil, commitError(err)
+	}
+
+	s.webhooks.Publish(householdID, model.WebhookEventTransactionDeleted, deleted)
+	return balances, nil
+}
+
+// transactionAccounts returns the account and, for a transfer, the destination
+// account of t.
+func transactionAccounts(t model.Transaction) []uuid.UUID {
+	ids := []uuid.UUID{t.AccountID}
+	if t.DestinationAccountID != nil {
+		ids = append(ids, *t.DestinationAccountID)
+	}
+	return ids
+}
+
+// accountBalances reads the current balance of each distinct account in ids, in
+// order. Called inside a RunInTx, it sees the transaction's own balance changes.
+func accountBalances(ctx context.Context, accounts repository.AccountRepository, householdID uuid.UUID, ids ...uuid.UUID) ([]model.AccountBalance, error) {
+	out := make([]model.AccountBalance, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		acc, err := accounts.GetByID(ctx, id, householdID)
 		if err != nil {
-			return ErrTransactionNotFound
+			return nil, notFoundOr(err, ErrAccountNotFound, "get account")
 		}
+		out = append(out, model.AccountBalance{AccountID: acc.ID, Balance: acc.Balance, Currency: acc.Currency})
+	}
+	return out, nil
+}
 
-		return reverseBalanceChange(txCtx, txRepos.Accounts, deleted.Type, deleted.Amount, deleted.AccountID, deleted.DestinationAccountID)
+// DeleteBatch deletes several transactions all-or-nothing and returns how many
+// were deleted. Duplicate IDs count once. IDs that don't exist in the household
+// fail the whole batch with ErrTransactionNotFound unless ignoreMissing is set.
+func (s *TransactionService) DeleteBatch(ctx context.Context, householdID, userID uuid.UUID, ids []uuid.UUID, ignoreMissing bool) (int, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w: ids must not be empty", ErrInvalidBatch)
+	}
+	if len(ids) > MaxBatchSize {
+		return 0, fmt.Errorf("%w: at most %d ids per request", ErrInvalidBatch, MaxBatchSize)
+	}
+
+	var deleted []model.Transaction
+	err := s.repos.RunInTx(ctx, func(txCtx context.Context) error {
+		txRepos := repository.TxReposFromCtx(txCtx)
+		seen := make(map[uuid.UUID]struct{}, len(ids))
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+
+			txn, err := deleteTransaction(txCtx, txRepos, id, householdID, userID)
+			if err != nil {
+				if ignoreMissing && errors.Is(err, ErrTransactionNotFound) {
+					continue
+				}
+				return err
+			}
+			deleted = append(deleted, txn)
+		}
+		return nil
 	})
+	if err != nil {
+		return 0, commitError(err)
+	}
+
+	for _, txn := range deleted {
+		s.webhooks.Publish(householdID, model.WebhookEventTransactionDeleted, txn)
+	}
+	return len(deleted), nil
+}
+
+// deleteTransaction deletes one transaction, reverses its balance change and
+// records it in the audit log. repos must be the transactional repos of the surrounding RunInTx.
+func deleteTransaction(ctx context.Context, repos *repository.Repos, id, householdID, userID uuid.UUID) (model.Transaction, error) {
+	// Splits go away with the row; keep them for the audit snapshot.
+	splits, err := repos.Transactions.ListSplits(ctx, id)
+	if err != nil {
+		return model.Transaction{}, fmt.Errorf("list splits: %w", err)
+	}
+
+	deleted, err := repos.Transactions.Delete(ctx, id, householdID)
+	if err != nil {
+		return model.Transaction{}, notFoundOr(err, ErrTransactionNotFound, "delete transaction")
+	}
+	deleted.Splits = splits
+
+	if deleted.Posted {
+		if err := reverseBalanceChange(ctx, repos.Accounts, deleted.Type, deleted.Amount, deleted.AccountID, deleted.DestinationAccountID); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+
+	if err := recordAudit(ctx, repos.Audit, householdID, userID, model.AuditActionTransactionDeleted, auditEntityTransaction, id, deleted, nil); err != nil {
+		return model.Transaction{}, err
+	}
+	return deleted, nil
 }
 
 // --- balance helpers ---
 
+// isDue reports whether a transaction dated transactedAt is posted now rather
+// than scheduled.
+func isDue(transactedAt time.Time) bool {
+	return !transactedAt.After(time.Now())
+}
+
+// checkTransferAccounts rejects a transfer without a destination or to its own
+// source account. Other types pass unchecked.
+func checkTransferAccounts(txnType model.TransactionType, accountID uuid.UUID, destID *uuid.UUID) error {
+	if txnType != model.TransactionTypeTransfer {
+		return nil
+	}
+	if destID == nil {
+		return ErrTransferMissingDest
+	}
+	if *destID == accountID {
+		return ErrSameAccountTransfer
+	}
+	return nil
+}
+
+// checkDailyLimit rejects an expense or outgoing transfer that would take the
+// account's spending on the transaction's day, in the household's timezone,
+// past its daily limit. The account stays locked until the surrounding
+// transaction ends, so concurrent creates are checked one after the other.
+func checkDailyLimit(ctx context.Context, repos *repository.Repos, params repository.CreateTransactionParams) error {
+	if params.Type == model.TransactionTypeIncome {
+		return nil
+	}
+	acc, err := repos.Accounts.GetForUpdate(ctx, params.AccountID, params.HouseholdID)
+	if err != nil {
+		return notFoundOr(err, ErrAccountNotFound, "get account")
+	}
+	if acc.DailyLimit == nil {
+		return nil
+	}
+
+	loc, err := householdLocation(ctx, repos.Households, params.HouseholdID)
+	if err != nil {
+		return err
+	}
+	y, m, d := params.TransactedAt.In(loc).Date()
+	dayStart := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	spent, err := repos.Transactions.SumOutflow(ctx, params.HouseholdID, params.AccountID, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("sum outflow: %w", err)
+	}
+	if spent.Add(params.Amount).GreaterThan(*acc.DailyLimit) {
+		return fmt.Errorf("%w: %s of %s already spent on %s", ErrDailyLimitExceeded,
+			spent, acc.DailyLimit, dayStart.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// checkTransferCurrencies rejects a transfer between accounts of different
+// currencies: the same amount is moved out of one and into the other, so without
+// a conversion the balances would no longer add up.
+func checkTransferCurrencies(ctx context.Context, accounts repository.AccountRepository, householdID uuid.UUID, txnType model.TransactionType, accountID uuid.UUID, destID *uuid.UUID) error {
+	if txnType != model.TransactionTypeTransfer || destID == nil {
+		return nil
+	}
+	source, err := accounts.GetByID(ctx, accountID, householdID)
+	if err != nil {
+		return notFoundOr(err, ErrAccountNotFound, "get source account")
+	}
+	dest, err := accounts.GetByID(ctx, *destID, householdID)
+	if err != nil {
+		return notFoundOr(err, ErrAccountNotFound, "get destination account")
+	}
+	if source.Currency != dest.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, source.Currency, dest.Currency)
+	}
+	return nil
+}
+
+// applyBalanceChange moves balances by a transaction's effect in asset terms;
+// UpdateBalance inverts it for liabilities, so an expense on a credit card
+// raises what is owed and a transfer into it pays it down.
 func applyBalanceChange(ctx context.Context, accounts repository.AccountRepository, txnType model.TransactionType, amount decimal.Decimal, accountID uuid.UUID, destID *uuid.UUID) error {
 	switch txnType {
 	case model.TransactionTypeIncome:
@@ -230,3 +830,175 @@ func reverseBalanceChange(ctx context.Context, accounts repository.AccountReposi
 	}
 	return nil
 }
+
+// applyOverrides copies the fields set in o onto req.
+func applyOverrides(req *model.CreateTransactionRequest, o model.TransactionOverrides) {
+	if o.Description != nil {
+		req.Description = *o.Description
+	}
+	if o.Amount != nil {
+		req.Amount = *o.Amount
+	}
+	if o.AccountID != nil {
+		req.AccountID = *o.AccountID
+	}
+	if o.DestinationAccountID != nil {
+		req.DestinationAccountID = o.DestinationAccountID
+	}
+	if o.Tags != nil {
+		req.Tags = o.Tags
+	}
+	if o.Note != nil {
+		req.Note = o.Note
+	}
+	if o.TransactedAt != nil {
+		req.TransactedAt = *o.TransactedAt
+	}
+}
+
+// commitError marks a refused commit as ErrTransactionNotSaved and one with an
+// unknown outcome as ErrTransactionOutcomeUnknown, so the client learns whether
+// anything changed rather than getting a generic server error.
+func commitError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCommitFailed):
+		return fmt.Errorf("%w: %w", ErrTransactionNotSaved, err)
+	case errors.Is(err, repository.ErrCommitUnknown):
+		return fmt.Errorf("%w: %w", ErrTransactionOutcomeUnknown, err)
+	}
+	return err
+}
+
+// normalizeTags trims and deduplicates tags, keeping their order, and rejects
+// empty or overlong tags and more than txnCfg.MaxTags of them.
+func normalizeTags(tags []string, txnCfg config.TransactionConfig) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidTags)
+		}
+		if utf8.RuneCountInString(tag) > txnCfg.MaxTagLength {
+			return nil, fmt.Errorf("%w: tags must be at most %d characters", ErrInvalidTags, txnCfg.MaxTagLength)
+		}
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	if len(out) > txnCfg.MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags allowed", ErrInvalidTags, txnCfg.MaxTags)
+	}
+	return out, nil
+}
+
+// foldTags lower-cases normalized tags and drops the duplicates that leaves,
+// unless the household keeps tags case-sensitive.
+func foldTags(ctx context.Context, households repository.HouseholdRepository, householdID uuid.UUID, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+	hh, err := households.GetByID(ctx, householdID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrHouseholdNotFound, "get household")
+	}
+	if hh.CaseSensitiveTags {
+		return tags, nil
+	}
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.ToLower(tag); !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out, nil
+}
+
+// checkTransactedAt rejects dates more than maxFuture ahead of now (0 means no limit).
+func checkTransactedAt(t time.Time, maxFuture time.Duration) error {
+	if maxFuture > 0 && t.After(time.Now().Add(maxFuture)) {
+		return fmt.Errorf("%w: more than %s in the future", ErrInvalidTransactedAt, maxFuture)
+	}
+	return nil
+}
+
+// --- split helpers ---
+
+// parseSplits validates split requests against the transaction amount. No splits is valid.
+func parseSplits(amount decimal.Decimal, reqs []model.SplitRequest, maxAmount decimal.Decimal) ([]repository.CreateSplitParams, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	out := make([]repository.CreateSplitParams, 0, len(reqs))
+	sum := decimal.Zero
+	for _, r := range reqs {
+		a, err := parseAmount(r.Amount, maxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: split: %v", ErrInvalidAmount, err)
+		}
+		if !a.IsPositive() {
+			return nil, ErrInvalidSplits
+		}
+		sum = sum.Add(a)
+		out = append(out, repository.CreateSplitParams{CategoryID: r.CategoryID, Amount: a})
+	}
+	if !sum.Equal(amount) {
+		return nil, ErrInvalidSplits
+	}
+	return out, nil
+}
+
+// storeSplits writes splits for a transaction after checking their categories belong
+// to the household. repos must be transactional.
+func storeSplits(ctx context.Context, repos *repository.Repos, householdID, transactionID uuid.UUID, splits []repository.CreateSplitParams) ([]model.TransactionSplit, error) {
+	if len(splits) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(splits))
+	ids := make([]uuid.UUID, 0, len(splits))
+	for _, sp := range splits {
+		if _, ok := seen[sp.CategoryID]; !ok {
+			seen[sp.CategoryID] = struct{}{}
+			ids = append(ids, sp.CategoryID)
+		}
+	}
+	n, err := repos.Categories.CountInHousehold(ctx, householdID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("check categories: %w", err)
+	}
+	if n != int64(len(ids)) {
+		return nil, ErrCategoryNotFound
+	}
+
+	out := make([]model.TransactionSplit, 0, len(splits))
+	for _, sp := range splits {
+		created, err := repos.Transactions.CreateSplit(ctx, transactionID, sp)
+		if err != nil {
+			// The category was deleted after the check above.
+			if mapped := constraintError(err, nil, ErrCategoryNotFound); mapped != nil {
+				return nil, mapped
+			}
+			return nil, fmt.Errorf("create split: %w", err)
+		}
+		out = append(out, created)
+	}
+	return out, nil
+}
+
+// replaceSplits swaps a transaction's splits for new ones.
+func replaceSplits(ctx context.Context, repos *repository.Repos, householdID, transactionID uuid.UUID, splits []repository.CreateSplitParams) ([]model.TransactionSplit, error) {
+	if err := repos.Transactions.DeleteSplits(ctx, transactionID); err != nil {
+		return nil, fmt.Errorf("delete splits: %w", err)
+	}
+	return storeSplits(ctx, repos, householdID, transactionID, splits)
+}
+
+// splitParams converts stored splits back to create params, e.g. to restore a snapshot.
+func splitParams(splits []model.TransactionSplit) []repository.CreateSplitParams {
+	out := make([]repository.CreateSplitParams, 0, len(splits))
+	for _, sp := range splits {
+		out = append(out, repository.CreateSplitParams{CategoryID: sp.CategoryID, Amount: sp.Amount})
+	}
+	return out
+}
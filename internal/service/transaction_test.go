@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+func testTxnConfig() config.TransactionConfig {
+	return config.TransactionConfig{
+		MaxFuture:    24 * time.Hour,
+		MaxTags:      3,
+		MaxTagLength: 10,
+		MaxAmount:    decimal.New(1, 12),
+	}
+}
+
+func newTestTransactionService(f *fakes) *TransactionService {
+	return NewTransactionService(f.repos, nil, nil, nil, testTxnConfig(), config.PaginationConfig{DefaultLimit: 50, MaxLimit: 100})
+}
+
+func TestDeleteCommitFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		commitErr error
+		want      error
+	}{
+		{"no commit", nil, nil},
+		{"rolled back", fmt.Errorf("%w: serialization failure", repository.ErrCommitFailed), ErrTransactionNotSaved},
+		{"connection lost", fmt.Errorf("%w: unexpected EOF", repository.ErrCommitUnknown), ErrTransactionOutcomeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			f.uow.commitErr = tt.commitErr
+			hh := uuid.New()
+			acc := f.addAccount(hh, "USD", 100)
+			txn := &model.Transaction{
+				ID: uuid.New(), HouseholdID: hh, AccountID: acc.ID,
+				Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(30), Posted: true,
+			}
+			f.transactions.byID[txn.ID] = txn
+
+			balances, err := newTestTransactionService(f).Delete(context.Background(), txn.ID, hh, uuid.New())
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Delete: %v", err)
+				}
+				if len(balances) != 1 || !balances[0].Balance.Equal(decimal.NewFromInt(130)) {
+					t.Errorf("balances = %v, want account at 130", balances)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Delete error = %v, want %v", err, tt.want)
+			}
+			if balances != nil {
+				t.Errorf("balances = %v, want none after a failed commit", balances)
+			}
+			if !acc.Balance.Equal(decimal.NewFromInt(100)) {
+				t.Errorf("account balance = %s after a failed commit, want 100", acc.Balance)
+			}
+			if _, ok := f.transactions.byID[txn.ID]; !ok {
+				t.Error("transaction gone after a failed commit")
+			}
+			if len(f.audit.entries) != 0 {
+				t.Errorf("audit entries = %d after a failed commit, want 0", len(f.audit.entries))
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string // nil means ErrInvalidTags
+	}{
+		{"none", nil, []string{}},
+		{"trims", []string{"  food ", "\ttravel\n"}, []string{"food", "travel"}},
+		{"dedupes keeping first", []string{"b", "a", " b", "a "}, []string{"b", "a"}},
+		{"keeps case", []string{"Food", "food"}, []string{"Food", "food"}},
+		{"empty", []string{"food", "  "}, nil},
+		{"too long", []string{"abcdefghijk"}, nil},
+		{"length counts runes", []string{"їжаківїжак"}, []string{"їжаківїжак"}},
+		{"too many", []string{"a", "b", "c", "d"}, nil},
+		{"duplicates don't count toward the limit", []string{"a", "b", "c", "a", " c"}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.in, testTxnConfig())
+			if tt.want == nil {
+				if !errors.Is(err, ErrInvalidTags) {
+					t.Fatalf("normalizeTags(%q) = %q, %v; want ErrInvalidTags", tt.in, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeTags(%q): %v", tt.in, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("normalizeTags(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		in            []string
+		want          []string
+	}{
+		{"lower-cases", false, []string{"Food", "TRAVEL"}, []string{"food", "travel"}},
+		{"dedupes after folding, keeping first", false, []string{"Travel", "food", "travel", "FOOD"}, []string{"travel", "food"}},
+		{"case-sensitive keeps tags as given", true, []string{"Food", "food"}, []string{"Food", "food"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.households.byID[hh] = model.Household{ID: hh, CaseSensitiveTags: tt.caseSensitive}
+
+			got, err := foldTags(context.Background(), f.households, hh, tt.in)
+			if err != nil {
+				t.Fatalf("foldTags: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("foldTags(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameTagCaseOnly(t *testing.T) {
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		from, to      string
+		want          [2]string // zero means ErrInvalidTagRename
+	}{
+		{"case-insensitive household", false, "Food", "FOOD", [2]string{}},
+		{"case-sensitive household", true, "Food", "FOOD", [2]string{"Food", "FOOD"}},
+		{"folds both sides", false, " Food ", "Groceries", [2]string{"food", "groceries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.households.byID[hh] = model.Household{ID: hh, CaseSensitiveTags: tt.caseSensitive}
+
+			_, err := newTestTransactionService(f).RenameTag(context.Background(), hh, tt.from, tt.to)
+			if tt.want == ([2]string{}) {
+				if !errors.Is(err, ErrInvalidTagRename) {
+					t.Fatalf("RenameTag error = %v, want ErrInvalidTagRename", err)
+				}
+				if len(f.transactions.renamed) != 0 {
+					t.Fatalf("renamed %v, want nothing", f.transactions.renamed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenameTag: %v", err)
+			}
+			if len(f.transactions.renamed) != 1 || f.transactions.renamed[0] != tt.want {
+				t.Fatalf("renamed %v, want %v", f.transactions.renamed, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferGuards(t *testing.T) {
+	tests := []struct {
+		name         string
+		destCurrency string
+		sameAccount  bool
+		want         error
+	}{
+		{"same currency", "USD", false, nil},
+		{"to itself", "USD", true, ErrSameAccountTransfer},
+		{"different currency", "EUR", false, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			src := f.addAccount(hh, "USD", 100)
+			dest := f.addAccount(hh, tt.destCurrency, 0)
+			if tt.sameAccount {
+				dest = src
+			}
+
+			_, err := newTestTransactionService(f).Create(context.Background(), hh, uuid.New(), model.CreateTransactionRequest{
+				Type:                 model.TransactionTypeTransfer,
+				Amount:               "40",
+				AccountID:            src.ID,
+				DestinationAccountID: &dest.ID,
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Create error = %v, want %v", err, tt.want)
+			}
+
+			wantSrc, wantDest := decimal.NewFromInt(100), decimal.Zero
+			if tt.want == nil {
+				wantSrc, wantDest = decimal.NewFromInt(60), decimal.NewFromInt(40)
+			} else if len(f.transactions.byID) != 0 {
+				t.Errorf("a rejected transfer was stored")
+			}
+			if !src.Balance.Equal(wantSrc) {
+				t.Errorf("source balance = %s, want %s", src.Balance, wantSrc)
+			}
+			if !tt.sameAccount && !dest.Balance.Equal(wantDest) {
+				t.Errorf("destination balance = %s, want %s", dest.Balance, wantDest)
+			}
+		})
+	}
+}
+
+func TestUpdateTransferGuards(t *testing.T) {
+	tests := []struct {
+		name         string
+		destCurrency string
+		sameAccount  bool
+		want         error
+	}{
+		{"to itself", "USD", true, ErrSameAccountTransfer},
+		{"different currency", "EUR", false, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			src := f.addAccount(hh, "USD", 100)
+			dest := f.addAccount(hh, tt.destCurrency, 0)
+			if tt.sameAccount {
+				dest = src
+			}
+			old := model.Transaction{
+				ID: uuid.New(), HouseholdID: hh, Type: model.TransactionTypeExpense,
+				Amount: decimal.NewFromInt(40), AccountID: src.ID, Posted: true,
+				TransactedAt: time.Now().Add(-time.Hour),
+			}
+
+			_, err := updateTransaction(context.Background(), f.repos, old, uuid.New(), model.UpdateTransactionRequest{
+				Type:                 model.TransactionTypeTransfer,
+				Amount:               "40",
+				AccountID:            src.ID,
+				DestinationAccountID: &dest.ID,
+				TransactedAt:         old.TransactedAt,
+			}, testTxnConfig())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("updateTransaction error = %v, want %v", err, tt.want)
+			}
+			if !src.Balance.Equal(decimal.NewFromInt(100)) {
+				t.Errorf("source balance = %s, want it untouched", src.Balance)
+			}
+		})
+	}
+}
+
+func TestCreateTransactedAt(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		at         time.Time
+		wantErr    bool
+		wantPosted bool
+	}{
+		{"missing defaults to now", time.Time{}, false, true},
+		{"past", now.Add(-72 * time.Hour), false, true},
+		{"just now", now.Add(-time.Second), false, true},
+		{"future within limit is scheduled", now.Add(23 * time.Hour), false, false},
+		{"future beyond limit", now.Add(25 * time.Hour), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := model.CreateTransactionRequest{
+				Type: model.TransactionTypeExpense, Amount: "5", AccountID: uuid.New(), TransactedAt: tt.at,
+			}
+			params, err := newCreateTransactionParams(uuid.New(), uuid.New(), req, testTxnConfig())
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTransactedAt) {
+					t.Fatalf("error = %v, want ErrInvalidTransactedAt", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newCreateTransactionParams: %v", err)
+			}
+			if params.Posted != tt.wantPosted {
+				t.Errorf("posted = %v, want %v", params.Posted, tt.wantPosted)
+			}
+			if tt.at.IsZero() {
+				if d := time.Since(params.TransactedAt); d < 0 || d > time.Minute {
+					t.Errorf("transacted_at = %s, want about now", params.TransactedAt)
+				}
+			} else if !params.TransactedAt.Equal(tt.at) {
+				t.Errorf("transacted_at = %s, want %s", params.TransactedAt, tt.at)
+			}
+		})
+	}
+}
+
+func TestUpdateTransactedAt(t *testing.T) {
+	f := newFakes()
+	hh := uuid.New()
+	acc := f.addAccount(hh, "USD", 100)
+	old := model.Transaction{
+		ID: uuid.New(), HouseholdID: hh, Type: model.TransactionTypeExpense,
+		Amount: decimal.NewFromInt(5), AccountID: acc.ID, Posted: true, TransactedAt: time.Now().Add(-time.Hour),
+	}
+	req := model.UpdateTransactionRequest{Type: model.TransactionTypeExpense, Amount: "5", AccountID: acc.ID}
+
+	// An update replaces the whole transaction, so a missing date is an error
+	// rather than a move to today.
+	_, err := newTestTransactionService(f).Update(context.Background(), old.ID, hh, uuid.New(), req)
+	if !errors.Is(err, ErrInvalidTransactedAt) {
+		t.Fatalf("Update without transacted_at error = %v, want ErrInvalidTransactedAt", err)
+	}
+
+	req.TransactedAt = time.Now().Add(48 * time.Hour)
+	_, err = updateTransaction(context.Background(), f.repos, old, uuid.New(), req, testTxnConfig())
+	if !errors.Is(err, ErrInvalidTransactedAt) {
+		t.Fatalf("update beyond the future limit error = %v, want ErrInvalidTransactedAt", err)
+	}
+	if !acc.Balance.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("balance = %s, want it untouched", acc.Balance)
+	}
+}
+
+func TestPatchKeepsStoredTags(t *testing.T) {
+	// Stored under looser limits than testTxnConfig's three tags of ten characters.
+	stored := []string{"groceries-weekly", "a", "b", "c"}
+	bread := "Bread"
+	tests := []struct {
+		name     string
+		patch    model.PatchTransactionRequest
+		wantErr  error
+		wantTags []string
+	}{
+		{"tags left out", model.PatchTransactionRequest{Description: &bread}, nil, stored},
+		{"tags replaced", model.PatchTransactionRequest{Tags: []string{" Food ", "food"}}, nil, []string{"food"}},
+		{"new tags over the limit", model.PatchTransactionRequest{Tags: []string{"a", "b", "c", "d"}}, ErrInvalidTags, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			hh := uuid.New()
+			f.households.byID[hh] = model.Household{ID: hh}
+			acc := f.addAccount(hh, "USD", 100)
+			old := &model.Transaction{
+				ID: uuid.New(), HouseholdID: hh, Type: model.TransactionTypeExpense, Description: "Groceries",
+				Amount: decimal.NewFromInt(5), AccountID: acc.ID, Tags: stored,
+				TransactedAt: time.Now().Add(-time.Hour), Posted: true,
+			}
+			f.transactions.byID[old.ID] = old
+
+			txn, err := newTestTransactionService(f).Patch(context.Background(), old.ID, hh, uuid.New(), tt.patch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Patch error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !slices.Equal(txn.Tags, tt.wantTags) {
+				t.Errorf("tags = %q, want %q", txn.Tags, tt.wantTags)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/howallet/howallet/internal/config"
+	"github.com/howallet/howallet/internal/model"
+	"github.com/howallet/howallet/internal/repository"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+)
+
+// WebhookService manages a household's webhooks.
+type WebhookService struct {
+	webhooks   repository.WebhookRepository
+	pagination config.PaginationConfig
+}
+
+func NewWebhookService(webhooks repository.WebhookRepository, pagination config.PaginationConfig) *WebhookService {
+	return &WebhookService{webhooks: webhooks, pagination: pagination}
+}
+
+// Create registers a webhook and generates its signing secret. The secret is only
+// returned here.
+func (s *WebhookService) Create(ctx context.Context, householdID uuid.UUID, req model.CreateWebhookRequest) (*model.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	wh, err := s.webhooks.Create(ctx, householdID, req.URL, generateRandomToken(32), events)
+	if err != nil {
+		if mapped := constraintError(err, nil, ErrHouseholdNotFound); mapped != nil {
+			return nil, mapped
+		}
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	return &wh, nil
+}
+
+func (s *WebhookService) List(ctx context.Context, householdID uuid.UUID) ([]model.Webhook, error) {
+	webhooks, err := s.webhooks.ListByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, id, householdID uuid.UUID, req model.UpdateWebhookRequest) (*model.Webhook, error) {
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+	var events []model.WebhookEvent
+	if req.Events != nil {
+		var err error
+		if events, err = validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	wh, err := s.webhooks.Update(ctx, id, householdID, req.URL, events)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("update webhook: %w", err)
+	}
+	wh.Secret = ""
+	return &wh, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id, householdID uuid.UUID) error {
+	deleted, err := s.webhooks.Delete(ctx, id, householdID)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Deliveries returns the most recent delivery attempts of a webhook, newest first.
+func (s *WebhookService) Deliveries(ctx context.Context, id, householdID uuid.UUID, limit int32) ([]model.WebhookDelivery, error) {
+	if _, err := s.webhooks.GetByID(ctx, id, householdID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("get webhook: %w", err)
+	}
+	deliveries, err := s.webhooks.ListDeliveries(ctx, id, s.pagination.Limit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must not point at a local address", ErrInvalidWebhook)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddr(addr) {
+		return fmt.Errorf("%w: url must not point at a local address", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is routable on the public internet, ruling out
+// loopback, private (RFC 1918, fc00::/7), link-local (which includes the cloud
+// metadata address 169.254.169.254), multicast and unspecified addresses.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// errWebhookAddrBlocked is returned when a webhook host resolves to an address
+// publicAddr rejects.
+var errWebhookAddrBlocked = errors.New("webhook host resolves to a non-public address")
+
+// webhookDialControl runs after the host has been resolved and before the
+// connection is made, so a hostname that passed validateWebhookURL but resolves
+// (or later re-resolves, or redirects) to an internal address is still refused.
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errWebhookAddrBlocked, address)
+	}
+	if !publicAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", errWebhookAddrBlocked, ap.Addr())
+	}
+	return nil
+}
+
+// newWebhookClient returns the HTTP client used for deliveries. It ignores proxy
+// settings so that every connection goes through webhookDialControl.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webhookRequestTimeout, Control: webhookDialControl}
+	return &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookRequestTimeout,
+			MaxIdleConns:        16,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// validateWebhookEvents requires at least one known event and drops duplicates.
+func validateWebhookEvents(events []model.WebhookEvent) ([]model.WebhookEvent, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	seen := make(map[model.WebhookEvent]struct{}, len(events))
+	out := make([]model.WebhookEvent, 0, len(events))
+	for _, e := range events {
+		if !e.Valid() {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, e)
+		}
+		if _, ok := seen[e]; !ok {
+			seen[e] = struct{}{}
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// --- delivery ---
+
+const (
+	webhookQueueSize      = 256
+	webhookRequestTimeout = 10 * time.Second
+)
+
+// webhookRetryDelays are the waits before each retry; a delivery gets one attempt
+// more than there are delays.
+var webhookRetryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+type webhookJob struct {
+	householdID uuid.UUID
+	event       model.WebhookEvent
+	body        []byte
+}
+
+// WebhookDispatcher delivers events to subscribed webhooks in the background.
+// Each request carries an X-Signature header: "sha256=" followed by the hex
+// HMAC-SHA256 of the body keyed with the webhook's secret.
+type WebhookDispatcher struct {
+	repos  *repository.Repos
+	client *http.Client
+	queue  chan webhookJob
+	logger *slog.Logger
+}
+
+func NewWebhookDispatcher(repos *repository.Repos, logger *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repos:  repos,
+		client: newWebhookClient(),
+		queue:  make(chan webhookJob, webhookQueueSize),
+		logger: logger,
+	}
+}
+
+// Publish queues an event for the household's webhooks. It never blocks: if the
+// queue is full the event is dropped and logged. A nil dispatcher ignores events.
+func (d *WebhookDispatcher) Publish(householdID uuid.UUID, event model.WebhookEvent, data interface{}) {
+	if d == nil {
+		return
+	}
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:          uuid.New(),
+		Event:       event,
+		HouseholdID: householdID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        data,
+	})
+	if err != nil {
+		d.logger.Error("webhook: encode payload", slog.String("event", string(event)), slog.String("error", err.Error()))
+		return
+	}
+
+	select {
+	case d.queue <- webhookJob{householdID: householdID, event: event, body: body}:
+	default:
+		d.logger.Error("webhook: queue full, event dropped",
+			slog.String("event", string(event)), slog.String("household_id", householdID.String()))
+	}
+}
+
+// Run fans queued events out to webhooks until ctx is cancelled, then waits for
+// the deliveries in flight to stop. Retries of different webhooks proceed
+// independently.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	var deliveries sync.WaitGroup
+	for {
+		select {
+		case <-ctx.Done():
+			deliveries.Wait()
+			return
+		case job := <-d.queue:
+			webhooks, err := d.repos.Webhooks.ListForEvent(ctx, job.householdID, job.event)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.logger.Error("webhook: list subscribers", slog.String("error", err.Error()))
+				}
+				continue
+			}
+			for _, wh := range webhooks {
+				deliveries.Go(func() { d.deliver(ctx, wh, job) })
+			}
+		}
+	}
+}
+
+// deliver posts the job to one webhook, retrying on errors and non-2xx responses.
+// Every attempt is recorded in the delivery log.
+func (d *WebhookDispatcher) deliver(ctx context.Context, wh model.Webhook, job webhookJob) {
+	for attempt := 1; ; attempt++ {
+		status, err := d.post(ctx, wh, job)
+
+		params := repository.CreateWebhookDeliveryParams{
+			WebhookID: wh.ID,
+			Event:     job.event,
+			Payload:   job.body,
+			Attempt:   int32(attempt),
+		}
+		if status != 0 {
+			code := int32(status)
+			params.StatusCode = &code
+		}
+		if err != nil {
+			msg := err.Error()
+			params.Error = &msg
+		}
+		if logErr := d.repos.Webhooks.CreateDelivery(ctx, params); logErr != nil && ctx.Err() == nil {
+			d.logger.Error("webhook: record delivery", slog.String("webhook_id", wh.ID.String()), slog.String("error", logErr.Error()))
+		}
+
+		if err == nil || attempt > len(webhookRetryDelays) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookRetryDelays[attempt-1]):
+		}
+	}
+}
+
+// post sends one attempt and returns the response status (0 if none was received).
+func (d *WebhookDispatcher) post(ctx context.Context, wh model.Webhook, job webhookJob) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hoWallet-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", string(job.event))
+	req.Header.Set("X-Signature", "sha256="+signWebhookPayload(wh.Secret, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://example.com/hook", true},
+		{"http://93.184.216.34:8080/hook", true},
+		{"ftp://example.com/hook", false},
+		{"/relative", false},
+		{"http://localhost/hook", false},
+		{"http://api.localhost./hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://172.16.0.1/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateWebhookURL(tt.url)
+			if tt.ok && err != nil {
+				t.Fatalf("validateWebhookURL(%q) = %v, want nil", tt.url, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidWebhook) {
+				t.Fatalf("validateWebhookURL(%q) = %v, want ErrInvalidWebhook", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		ok      bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"10.0.0.5:443", false},
+		{"169.254.169.254:80", false},
+		{"[::1]:443", false},
+		{"[::ffff:192.168.0.1]:443", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := webhookDialControl("tcp", tt.address, nil)
+			if tt.ok && err != nil {
+				t.Fatalf("webhookDialControl(%q) = %v, want nil", tt.address, err)
+			}
+			if !tt.ok && !errors.Is(err, errWebhookAddrBlocked) {
+				t.Fatalf("webhookDialControl(%q) = %v, want errWebhookAddrBlocked", tt.address, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+-- ============================================================
+-- AUDIT LOG  (before/after snapshots of household mutations)
+-- ============================================================
+
+CREATE TABLE audit_log (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action       VARCHAR(64) NOT NULL,
+    entity_type  VARCHAR(64) NOT NULL,
+    entity_id    UUID NOT NULL,
+    before       JSONB,
+    after        JSONB,
+    undone_at    TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_household_user ON audit_log (household_id, user_id, created_at DESC);
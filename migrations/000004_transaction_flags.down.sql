@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_txn_flagged;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS flag_reason,
+    DROP COLUMN IF EXISTS flagged;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    ADD COLUMN flagged     BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN flag_reason TEXT;
+
+CREATE INDEX idx_txn_flagged ON transactions (household_id) WHERE flagged;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_rt_last_used;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS last_used_at;
@@ -0,0 +1,4 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX idx_rt_last_used ON refresh_tokens (last_used_at);
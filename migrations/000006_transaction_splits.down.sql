@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transaction_splits;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,26 @@
+-- ============================================================
+-- CATEGORIES  (household-scoped spending categories)
+-- ============================================================
+
+CREATE TABLE categories (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (household_id, name)
+);
+
+-- ============================================================
+-- TRANSACTION SPLITS  (categorize parts of one transaction)
+-- ============================================================
+
+CREATE TABLE transaction_splits (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    category_id    UUID NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    amount         DECIMAL(19, 4) NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_splits_transaction ON transaction_splits (transaction_id);
+CREATE INDEX idx_splits_category    ON transaction_splits (category_id);
@@ -0,0 +1,3 @@
+-- Accounts that shouldn't count toward household totals (e.g. money held for someone else).
+ALTER TABLE accounts
+    ADD COLUMN include_in_totals BOOLEAN NOT NULL DEFAULT true;
@@ -0,0 +1,4 @@
+-- Client details captured at sign-in so users can recognize their sessions.
+ALTER TABLE refresh_tokens
+    ADD COLUMN user_agent TEXT,
+    ADD COLUMN ip_address TEXT;
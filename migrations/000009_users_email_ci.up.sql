@@ -0,0 +1,17 @@
+-- Emails are compared case-insensitively. Addresses that only differ by case are
+-- collapsed onto the oldest account; newer duplicates are renamed so they stay
+-- intact (with their households) until an operator merges or removes them.
+UPDATE users u
+SET email = left(lower(u.email), 200) || '.duplicate-' || u.id
+WHERE EXISTS (
+    SELECT 1 FROM users o
+    WHERE lower(o.email) = lower(u.email)
+      AND (o.created_at, o.id) < (u.created_at, u.id)
+);
+
+UPDATE users SET email = lower(email) WHERE email <> lower(email);
+UPDATE invitations SET email = lower(email) WHERE email <> lower(email);
+
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email_lower ON users (lower(email));
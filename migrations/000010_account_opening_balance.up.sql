@@ -0,0 +1,15 @@
+-- The balance an account was opened with; unlike balance it never changes.
+ALTER TABLE accounts
+    ADD COLUMN opening_balance DECIMAL(19, 4) NOT NULL DEFAULT 0;
+
+-- Existing accounts: back out every transaction's effect from the current balance.
+UPDATE accounts a
+SET opening_balance = a.balance - COALESCE((
+    SELECT SUM(CASE
+                   WHEN t.type = 'income' THEN t.amount
+                   WHEN t.account_id = a.id THEN -t.amount
+                   ELSE t.amount -- incoming transfer
+               END)
+    FROM transactions t
+    WHERE t.account_id = a.id OR t.destination_account_id = a.id
+), 0);
@@ -0,0 +1,28 @@
+-- ============================================================
+-- WEBHOOKS  (household-scoped outgoing event notifications)
+-- ============================================================
+
+CREATE TABLE webhooks (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    url          TEXT NOT NULL,
+    secret       TEXT NOT NULL,
+    events       TEXT[] NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhooks_household ON webhooks (household_id);
+
+-- One row per delivery attempt.
+CREATE TABLE webhook_deliveries (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id  UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event       VARCHAR(64) NOT NULL,
+    payload     JSONB NOT NULL,
+    attempt     INT NOT NULL,
+    status_code INT,
+    error       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- IANA zone used to turn timestamps into calendar dates (exports, reports).
+ALTER TABLE households
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
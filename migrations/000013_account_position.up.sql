@@ -0,0 +1,12 @@
+-- User-defined display order of a household's accounts (lowest first).
+ALTER TABLE accounts
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+-- Existing accounts keep their creation order.
+UPDATE accounts a
+SET position = o.pos
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY household_id ORDER BY created_at) - 1 AS pos
+    FROM accounts
+) o
+WHERE a.id = o.id;
@@ -0,0 +1,24 @@
+-- ============================================================
+-- TRANSACTION TEMPLATES  (saved partial transactions for quick entry)
+-- ============================================================
+
+CREATE TABLE transaction_templates (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    household_id           UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name                   VARCHAR(255) NOT NULL,
+    type                   transaction_type NOT NULL,
+    description            VARCHAR(512) NOT NULL DEFAULT '',
+    amount                 DECIMAL(19, 4),
+    account_id             UUID REFERENCES accounts (id) ON DELETE SET NULL,
+    destination_account_id UUID REFERENCES accounts (id) ON DELETE SET NULL,
+    tags                   TEXT[] NOT NULL DEFAULT '{}',
+    note                   TEXT,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_templates_household ON transaction_templates (household_id);
+
+CREATE TRIGGER trg_transaction_templates_updated_at
+    BEFORE UPDATE ON transaction_templates
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
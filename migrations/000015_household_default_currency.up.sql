@@ -0,0 +1,3 @@
+-- Currency given to new accounts that don't name one.
+ALTER TABLE households
+    ADD COLUMN default_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
@@ -0,0 +1,3 @@
+-- Who last changed an account's settings (NULL until first edited).
+ALTER TABLE accounts
+    ADD COLUMN updated_by UUID REFERENCES users (id) ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+DELETE FROM refresh_tokens WHERE used_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_rt_family;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS replaced_by,
+    DROP COLUMN IF EXISTS used_at,
+    DROP COLUMN IF EXISTS family_id;
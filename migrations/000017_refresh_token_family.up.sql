@@ -0,0 +1,13 @@
+-- Rotated refresh tokens are kept (marked used) so that replaying one can be
+-- detected; every token descended from the same login shares a family_id.
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id   UUID,
+    ADD COLUMN used_at     TIMESTAMPTZ,
+    ADD COLUMN replaced_by UUID;
+
+UPDATE refresh_tokens SET family_id = id;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_rt_family ON refresh_tokens (family_id);
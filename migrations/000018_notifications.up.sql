@@ -0,0 +1,25 @@
+-- ============================================================
+-- NOTIFICATIONS  (in-app, per user and household)
+-- ============================================================
+
+CREATE TABLE notifications (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    kind         VARCHAR(64) NOT NULL,
+    data         JSONB NOT NULL,
+    read_at      TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+-- Which events notify a user. Users without a row get the column defaults;
+-- large-transaction notifications are off until a threshold is set.
+CREATE TABLE notification_preferences (
+    user_id                     UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    large_transaction_threshold DECIMAL(19, 4),
+    member_joined               BOOLEAN NOT NULL DEFAULT true,
+    invitation_accepted         BOOLEAN NOT NULL DEFAULT true,
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,29 @@
+-- ============================================================
+-- BUDGETS  (monthly spending limits per category)
+-- ============================================================
+
+-- thresholds are percentages of amount; members are alerted as the month's
+-- spend in the category reaches each of them.
+CREATE TABLE budgets (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    category_id  UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    amount       DECIMAL(19, 4) NOT NULL CHECK (amount > 0),
+    thresholds   INT[] NOT NULL DEFAULT '{80,100}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (household_id, category_id)
+);
+
+CREATE TRIGGER trg_budgets_updated_at
+    BEFORE UPDATE ON budgets
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- One row per threshold reached per month, so each alert fires only once.
+CREATE TABLE budget_alerts (
+    budget_id    UUID NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    threshold    INT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (budget_id, period_start, threshold)
+);
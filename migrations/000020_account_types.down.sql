@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS account_types;
+
+CREATE TYPE account_type AS ENUM ('card', 'deposit', 'cash');
+
+-- Custom types have no enum value; fall back to the column default.
+UPDATE accounts SET type = 'card' WHERE type NOT IN ('card', 'deposit', 'cash');
+
+ALTER TABLE accounts
+    ALTER COLUMN type DROP DEFAULT,
+    ALTER COLUMN type TYPE account_type USING type::account_type,
+    ALTER COLUMN type SET DEFAULT 'card';
@@ -0,0 +1,16 @@
+-- Account types become free text so households can add their own (loans,
+-- investments, e-wallets) next to the built-in card, deposit and cash, which
+-- stay valid everywhere and are not stored here.
+ALTER TABLE accounts
+    ALTER COLUMN type DROP DEFAULT,
+    ALTER COLUMN type TYPE VARCHAR(64) USING type::text,
+    ALTER COLUMN type SET DEFAULT 'card';
+
+DROP TYPE IF EXISTS account_type;
+
+CREATE TABLE account_types (
+    household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name         VARCHAR(64) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (household_id, name)
+);
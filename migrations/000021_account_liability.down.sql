@@ -0,0 +1,6 @@
+-- Back to asset semantics: what is owed becomes a negative balance.
+UPDATE accounts
+SET balance = -balance, opening_balance = -opening_balance
+WHERE is_liability;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS is_liability;
@@ -0,0 +1,3 @@
+-- Liability accounts (credit cards, loans) hold what is owed: expenses raise
+-- their balance and totals subtract it.
+ALTER TABLE accounts ADD COLUMN is_liability BOOLEAN NOT NULL DEFAULT false;
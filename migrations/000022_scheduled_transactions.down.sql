@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transactions_scheduled;
+
+-- Scheduled transactions never touched balances; dropping the flag would make
+-- them look applied, so they go.
+DELETE FROM transactions WHERE NOT posted;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS posted;
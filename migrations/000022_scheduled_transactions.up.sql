@@ -0,0 +1,5 @@
+-- Transactions dated in the future are scheduled: they leave balances alone
+-- until the poster marks them posted once their date arrives.
+ALTER TABLE transactions ADD COLUMN posted BOOLEAN NOT NULL DEFAULT true;
+
+CREATE INDEX idx_transactions_scheduled ON transactions (transacted_at) WHERE NOT posted;
@@ -0,0 +1,2 @@
+-- Caps a day's expenses and outgoing transfers from the account; NULL: no limit.
+ALTER TABLE accounts ADD COLUMN daily_limit DECIMAL(19, 4) CHECK (daily_limit > 0);
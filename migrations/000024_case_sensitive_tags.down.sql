@@ -0,0 +1 @@
+ALTER TABLE households DROP COLUMN IF EXISTS case_sensitive_tags;
@@ -0,0 +1,14 @@
+-- When false (the default), tags are lower-cased on write so "Food" and "food" are one tag.
+ALTER TABLE households ADD COLUMN case_sensitive_tags BOOLEAN NOT NULL DEFAULT false;
+
+-- Bring existing tags in line: trimmed, lower-cased, without empties or duplicates,
+-- keeping their first position.
+UPDATE transactions t
+SET tags = ARRAY(
+    SELECT lower(btrim(tag))
+    FROM unnest(t.tags) WITH ORDINALITY AS u(tag, ord)
+    WHERE btrim(tag) <> ''
+    GROUP BY lower(btrim(tag))
+    ORDER BY min(ord)
+)
+WHERE cardinality(t.tags) > 0;
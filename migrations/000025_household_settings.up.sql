@@ -0,0 +1,3 @@
+-- Assorted household settings that don't warrant a column of their own, keyed
+-- by their JSON names (see model.HouseholdSettings). Missing keys take defaults.
+ALTER TABLE households ADD COLUMN settings JSONB NOT NULL DEFAULT '{}'::jsonb;
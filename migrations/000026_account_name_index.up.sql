@@ -0,0 +1,3 @@
+-- Backs the case-insensitive duplicate name check on account creation. Not
+-- unique: duplicates can be created on purpose and may already exist.
+CREATE INDEX idx_accounts_household_lower_name ON accounts (household_id, lower(name));
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the API can check, and
+// optionally apply, them at startup. Files follow golang-migrate's naming:
+// NNNNNN_name.up.sql and NNNNNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,14 @@
+-- name: CreateAccountType :exec
+INSERT INTO account_types (household_id, name) VALUES ($1, $2);
+
+-- name: ListAccountTypes :many
+SELECT name FROM account_types WHERE household_id = $1 ORDER BY name;
+
+-- name: AccountTypeExists :one
+SELECT EXISTS (SELECT 1 FROM account_types WHERE household_id = $1 AND name = $2 FOR KEY SHARE);
+
+-- name: DeleteAccountType :execrows
+DELETE FROM account_types WHERE household_id = $1 AND name = $2;
+
+-- name: CountAccountsOfType :one
+SELECT COUNT(*) FROM accounts WHERE household_id = $1 AND type = $2;
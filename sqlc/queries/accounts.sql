@@ -1,27 +1,54 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (household_id, name, type, balance, currency, created_by)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO accounts (household_id, name, type, balance, opening_balance, currency, created_by, include_in_totals, is_liability, daily_limit, position)
+VALUES ($1, $2, $3, $4, $4, $5, $6, $7, $8, $9,
+        (SELECT COALESCE(MAX(position) + 1, 0) FROM accounts WHERE household_id = $1))
 RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts WHERE id = $1 AND household_id = $2;
 
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts WHERE id = $1 AND household_id = $2
+FOR UPDATE;
+
 -- name: ListAccountsByHousehold :many
 SELECT * FROM accounts
 WHERE household_id = $1
-ORDER BY created_at;
+ORDER BY position, created_at;
+
+-- name: AccountNameExists :one
+SELECT EXISTS (SELECT 1 FROM accounts WHERE household_id = $1 AND lower(name) = lower($2));
+
+-- name: SearchAccounts :many
+SELECT * FROM accounts
+WHERE household_id = $1 AND strpos(lower(name), lower($2)) > 0
+ORDER BY position, created_at
+LIMIT $3;
 
 -- name: UpdateAccount :one
 UPDATE accounts
 SET name     = COALESCE(sqlc.narg('name'), name),
     type     = COALESCE(sqlc.narg('type'), type),
-    currency = COALESCE(sqlc.narg('currency'), currency)
+    currency = COALESCE(sqlc.narg('currency'), currency),
+    include_in_totals = COALESCE(sqlc.narg('include_in_totals'), include_in_totals),
+    balance = CASE WHEN sqlc.narg('is_liability')::boolean <> is_liability THEN -balance ELSE balance END,
+    opening_balance = CASE WHEN sqlc.narg('is_liability')::boolean <> is_liability THEN -opening_balance ELSE opening_balance END,
+    is_liability = COALESCE(sqlc.narg('is_liability'), is_liability),
+    daily_limit = CASE WHEN sqlc.arg('set_daily_limit')::boolean THEN sqlc.narg('daily_limit') ELSE daily_limit END,
+    updated_by = @updated_by
 WHERE id = $1 AND household_id = $2
+  AND (sqlc.narg('expected_updated_at')::timestamptz IS NULL OR updated_at = sqlc.narg('expected_updated_at'))
 RETURNING *;
 
+-- name: ReorderAccounts :execrows
+UPDATE accounts a
+SET position = o.pos - 1
+FROM unnest(@account_ids::uuid[]) WITH ORDINALITY AS o(id, pos)
+WHERE a.id = o.id AND a.household_id = @household_id;
+
 -- name: UpdateAccountBalance :exec
 UPDATE accounts
-SET balance = balance + $2
+SET balance = balance + CASE WHEN is_liability THEN -@delta::numeric ELSE @delta::numeric END
 WHERE id = $1;
 
 -- name: SetAccountBalance :exec
@@ -34,3 +61,27 @@ DELETE FROM accounts WHERE id = $1 AND household_id = $2;
 
 -- name: CountTransactionsByAccount :one
 SELECT COUNT(*) FROM transactions WHERE account_id = $1;
+
+-- name: SumBalancesByType :many
+SELECT type, currency, SUM(CASE WHEN is_liability THEN -balance ELSE balance END)::decimal AS total, COUNT(*) AS count
+FROM accounts
+WHERE household_id = $1 AND include_in_totals
+GROUP BY type, currency
+ORDER BY type, currency;
+
+-- name: GetAccountBalanceAsOf :one
+SELECT (a.opening_balance + CASE WHEN a.is_liability THEN -n.net ELSE n.net END)::decimal AS balance
+FROM accounts a,
+     LATERAL (
+         SELECT COALESCE(SUM(CASE
+                    WHEN t.type = 'income' THEN t.amount
+                    WHEN t.type = 'expense' THEN -t.amount
+                    WHEN t.account_id = a.id THEN -t.amount
+                    ELSE t.amount
+                END), 0) AS net
+         FROM transactions t
+         WHERE (t.account_id = a.id OR t.destination_account_id = a.id)
+           AND t.posted
+           AND t.transacted_at < sqlc.arg('before')
+     ) n
+WHERE a.id = $1 AND a.household_id = $2;
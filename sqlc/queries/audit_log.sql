@@ -0,0 +1,19 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log (household_id, user_id, action, entity_type, entity_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetLastUndoableAuditEntry :one
+SELECT * FROM audit_log
+WHERE household_id = $1
+  AND user_id = $2
+  AND undone_at IS NULL
+  AND created_at >= $3
+ORDER BY created_at DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: MarkAuditEntryUndone :exec
+UPDATE audit_log
+SET undone_at = now()
+WHERE id = $1;
@@ -0,0 +1,35 @@
+-- name: CreateBudget :one
+INSERT INTO budgets (household_id, category_id, amount, thresholds)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListBudgets :many
+SELECT * FROM budgets WHERE household_id = $1
+ORDER BY created_at;
+
+-- name: ListBudgetsForCategories :many
+SELECT * FROM budgets WHERE household_id = $1 AND category_id = ANY($2);
+
+-- name: UpdateBudget :one
+UPDATE budgets SET category_id = $3, amount = $4, thresholds = $5
+WHERE id = $1 AND household_id = $2
+RETURNING *;
+
+-- name: DeleteBudget :execrows
+DELETE FROM budgets WHERE id = $1 AND household_id = $2;
+
+-- name: SumCategorySpend :one
+SELECT COALESCE(SUM(s.amount), 0)
+FROM transaction_splits s
+JOIN transactions t ON t.id = s.transaction_id
+WHERE t.household_id = $1
+  AND t.type = 'expense'
+  AND t.posted
+  AND s.category_id = $2
+  AND t.transacted_at >= $3
+  AND t.transacted_at < $4;
+
+-- name: CreateBudgetAlert :execrows
+INSERT INTO budget_alerts (budget_id, period_start, threshold)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- name: CreateCategory :one
+INSERT INTO categories (household_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+WHERE household_id = $1
+ORDER BY name;
+
+-- name: CountHouseholdCategories :one
+SELECT COUNT(*) FROM categories
+WHERE household_id = $1 AND id = ANY($2::uuid[]);
@@ -1,6 +1,6 @@
 -- name: CreateHousehold :one
-INSERT INTO households (name, owner_id)
-VALUES ($1, $2)
+INSERT INTO households (name, owner_id, timezone, default_currency)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetHousehold :one
@@ -15,7 +15,12 @@ ORDER BY h.created_at;
 
 -- name: UpdateHousehold :one
 UPDATE households
-SET name = $2
+SET name                = COALESCE(sqlc.narg('name'), name),
+    timezone            = COALESCE(sqlc.narg('timezone'), timezone),
+    case_sensitive_tags = COALESCE(sqlc.narg('case_sensitive_tags'), case_sensitive_tags),
+    default_currency    = COALESCE(sqlc.narg('default_currency'), default_currency),
+    settings            = CASE WHEN sqlc.narg('fiscal_month_start_day')::int IS NULL THEN settings
+                               ELSE jsonb_set(settings, '{fiscal_month_start_day}', to_jsonb(sqlc.narg('fiscal_month_start_day')::int)) END
 WHERE id = $1
 RETURNING *;
 
@@ -40,7 +45,20 @@ SELECT hm.*, u.email, u.name AS user_name
 FROM household_members hm
 JOIN users u ON u.id = hm.user_id
 WHERE hm.household_id = $1
-ORDER BY hm.joined_at;
+  AND ($2::household_role IS NULL OR hm.role = $2)
+  AND ($3::text IS NULL OR strpos(lower(u.name), lower($3)) > 0 OR strpos(lower(u.email), lower($3)) > 0)
+  AND ($6::uuid[] IS NULL OR hm.user_id = ANY($6))
+ORDER BY hm.joined_at, hm.user_id
+LIMIT $4 OFFSET $5;
+
+-- name: CountHouseholdMembers :one
+SELECT COUNT(*)
+FROM household_members hm
+JOIN users u ON u.id = hm.user_id
+WHERE hm.household_id = $1
+  AND ($2::household_role IS NULL OR hm.role = $2)
+  AND ($3::text IS NULL OR strpos(lower(u.name), lower($3)) > 0 OR strpos(lower(u.email), lower($3)) > 0)
+  AND ($4::uuid[] IS NULL OR hm.user_id = ANY($4));
 
 -- name: IsHouseholdMember :one
 SELECT EXISTS (
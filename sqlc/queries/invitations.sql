@@ -4,19 +4,31 @@ VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetInvitationByToken :one
-SELECT * FROM invitations WHERE token = $1;
+SELECT id, household_id, email, invited_by, token,
+       CASE WHEN status = 'pending' AND expires_at <= now() THEN 'expired' ELSE status END::invitation_status AS status,
+       expires_at, created_at
+FROM invitations WHERE token = $1;
 
--- name: AcceptInvitation :exec
+-- name: AcceptInvitation :execrows
 UPDATE invitations
 SET status = 'accepted'
-WHERE id = $1;
+WHERE id = $1 AND status = 'pending' AND expires_at > now();
 
--- name: ExpireOldInvitations :exec
+-- name: DeletePendingInvitation :execrows
+DELETE FROM invitations
+WHERE id = $1 AND household_id = $2 AND status = 'pending';
+
+-- name: ExpireStaleInvitations :execrows
 UPDATE invitations
 SET status = 'expired'
-WHERE status = 'pending' AND expires_at < now();
+WHERE status = 'pending' AND expires_at <= now();
 
 -- name: ListPendingInvitations :many
 SELECT * FROM invitations
-WHERE household_id = $1 AND status = 'pending'
-ORDER BY created_at DESC;
+WHERE household_id = $1 AND status = 'pending' AND expires_at > now()
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountPendingInvitations :one
+SELECT COUNT(*) FROM invitations
+WHERE household_id = $1 AND status = 'pending' AND expires_at > now();
@@ -0,0 +1,70 @@
+-- name: CreateLargeTransactionNotifications :execrows
+INSERT INTO notifications (user_id, household_id, kind, data)
+SELECT hm.user_id, hm.household_id, 'large_transaction', $4
+FROM household_members hm
+JOIN notification_preferences np ON np.user_id = hm.user_id
+WHERE hm.household_id = $1
+  AND hm.user_id <> $2
+  AND np.large_transaction_threshold IS NOT NULL
+  AND $3 >= np.large_transaction_threshold;
+
+-- name: CreateMemberJoinedNotifications :execrows
+INSERT INTO notifications (user_id, household_id, kind, data)
+SELECT hm.user_id, hm.household_id, 'member_joined', $4
+FROM household_members hm
+LEFT JOIN notification_preferences np ON np.user_id = hm.user_id
+WHERE hm.household_id = $1
+  AND hm.user_id <> $2
+  AND hm.user_id <> $3
+  AND COALESCE(np.member_joined, true);
+
+-- name: CreateInvitationAcceptedNotification :execrows
+INSERT INTO notifications (user_id, household_id, kind, data)
+SELECT hm.user_id, hm.household_id, 'invitation_accepted', $3
+FROM household_members hm
+LEFT JOIN notification_preferences np ON np.user_id = hm.user_id
+WHERE hm.household_id = $1
+  AND hm.user_id = $2
+  AND COALESCE(np.invitation_accepted, true);
+
+-- name: CreateBudgetNotifications :execrows
+INSERT INTO notifications (user_id, household_id, kind, data)
+SELECT hm.user_id, hm.household_id, 'budget_threshold', $2
+FROM household_members hm
+WHERE hm.household_id = $1;
+
+-- name: ListNotifications :many
+SELECT n.*
+FROM notifications n
+JOIN household_members hm ON hm.household_id = n.household_id AND hm.user_id = n.user_id
+WHERE n.user_id = $1
+  AND (NOT $2 OR n.read_at IS NULL)
+ORDER BY n.created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountNotifications :one
+SELECT COUNT(*)
+FROM notifications n
+JOIN household_members hm ON hm.household_id = n.household_id AND hm.user_id = n.user_id
+WHERE n.user_id = $1
+  AND (NOT $2 OR n.read_at IS NULL);
+
+-- name: MarkNotificationRead :one
+UPDATE notifications n SET read_at = COALESCE(n.read_at, now())
+FROM household_members hm
+WHERE n.id = $1 AND n.user_id = $2
+  AND hm.household_id = n.household_id AND hm.user_id = n.user_id
+RETURNING n.*;
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences WHERE user_id = $1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (user_id, large_transaction_threshold, member_joined, invitation_accepted)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET large_transaction_threshold = EXCLUDED.large_transaction_threshold,
+    member_joined = EXCLUDED.member_joined,
+    invitation_accepted = EXCLUDED.invitation_accepted,
+    updated_at = now()
+RETURNING *;
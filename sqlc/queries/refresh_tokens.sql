@@ -1,15 +1,40 @@
--- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-VALUES ($1, $2, $3);
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, token_hash, expires_at, user_agent, ip_address, family_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: MarkRefreshTokenUsed :execrows
+UPDATE refresh_tokens SET used_at = now(), replaced_by = $2
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: DeleteRefreshTokenFamily :exec
+DELETE FROM refresh_tokens WHERE family_id = $1;
 
 -- name: GetRefreshToken :one
 SELECT * FROM refresh_tokens WHERE token_hash = $1;
 
+-- name: ListRefreshTokensByUser :many
+SELECT * FROM refresh_tokens
+WHERE user_id = $1 AND expires_at > now() AND used_at IS NULL
+ORDER BY last_used_at DESC;
+
+-- name: DeleteUserRefreshToken :execrows
+DELETE FROM refresh_tokens
+WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE id = $1 AND user_id = $2);
+
+-- name: DeleteUserRefreshTokenByHash :execrows
+DELETE FROM refresh_tokens
+WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND user_id = $2);
+
 -- name: DeleteRefreshToken :exec
-DELETE FROM refresh_tokens WHERE token_hash = $1;
+DELETE FROM refresh_tokens
+WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1);
 
 -- name: DeleteUserRefreshTokens :exec
 DELETE FROM refresh_tokens WHERE user_id = $1;
 
--- name: DeleteExpiredRefreshTokens :exec
+-- name: DeleteExpiredRefreshTokens :execrows
 DELETE FROM refresh_tokens WHERE expires_at < now();
+
+-- name: DeleteIdleRefreshTokens :execrows
+DELETE FROM refresh_tokens WHERE last_used_at < $1 AND used_at IS NULL;
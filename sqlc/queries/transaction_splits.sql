@@ -0,0 +1,13 @@
+-- name: CreateTransactionSplit :one
+INSERT INTO transaction_splits (transaction_id, category_id, amount)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListTransactionSplits :many
+SELECT * FROM transaction_splits
+WHERE transaction_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteTransactionSplits :exec
+DELETE FROM transaction_splits
+WHERE transaction_id = $1;
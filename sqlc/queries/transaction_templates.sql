@@ -0,0 +1,25 @@
+-- name: CreateTransactionTemplate :one
+INSERT INTO transaction_templates (
+    household_id, name, type, description, amount,
+    account_id, destination_account_id, tags, note
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetTransactionTemplate :one
+SELECT * FROM transaction_templates WHERE id = $1 AND household_id = $2;
+
+-- name: ListTransactionTemplates :many
+SELECT * FROM transaction_templates
+WHERE household_id = $1
+ORDER BY name;
+
+-- name: UpdateTransactionTemplate :one
+UPDATE transaction_templates
+SET name = $3, type = $4, description = $5, amount = $6,
+    account_id = $7, destination_account_id = $8, tags = $9, note = $10
+WHERE id = $1 AND household_id = $2
+RETURNING *;
+
+-- name: DeleteTransactionTemplate :execrows
+DELETE FROM transaction_templates WHERE id = $1 AND household_id = $2;
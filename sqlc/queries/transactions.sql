@@ -2,9 +2,9 @@
 INSERT INTO transactions (
     household_id, type, description, amount,
     account_id, destination_account_id, tags, note,
-    transacted_at, created_by
+    transacted_at, created_by, posted
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: GetTransaction :one
@@ -17,9 +17,12 @@ WHERE household_id = $1
   AND ($2::timestamptz IS NULL OR transacted_at >= $2)
   AND ($3::timestamptz IS NULL OR transacted_at <= $3)
   AND ($4::transaction_type IS NULL OR type = $4)
-  AND ($5::uuid IS NULL OR account_id = $5 OR destination_account_id = $5)
+  AND ($5::uuid[] IS NULL OR account_id = ANY($5) OR destination_account_id = ANY($5))
+  AND ($6::boolean IS NULL OR flagged = $6)
+  AND ($7::uuid IS NULL OR created_by = $7)
+  AND ($8::boolean IS NULL OR posted = $8)
 ORDER BY transacted_at DESC
-LIMIT $6 OFFSET $7;
+LIMIT $9 OFFSET $10;
 
 -- name: CountTransactions :one
 SELECT COUNT(*) FROM transactions
@@ -27,7 +30,115 @@ WHERE household_id = $1
   AND ($2::timestamptz IS NULL OR transacted_at >= $2)
   AND ($3::timestamptz IS NULL OR transacted_at <= $3)
   AND ($4::transaction_type IS NULL OR type = $4)
-  AND ($5::uuid IS NULL OR account_id = $5 OR destination_account_id = $5);
+  AND ($5::uuid[] IS NULL OR account_id = ANY($5) OR destination_account_id = ANY($5))
+  AND ($6::boolean IS NULL OR flagged = $6)
+  AND ($7::uuid IS NULL OR created_by = $7)
+  AND ($8::boolean IS NULL OR posted = $8);
+
+-- name: SummarizeTransactions :many
+SELECT a.currency,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total,
+       COALESCE(SUM(CASE t.type WHEN 'income' THEN t.amount
+                                WHEN 'expense' THEN -t.amount END), 0) AS net,
+       COUNT(*)
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+WHERE t.household_id = $1
+  AND ($2::timestamptz IS NULL OR t.transacted_at >= $2)
+  AND ($3::timestamptz IS NULL OR t.transacted_at <= $3)
+  AND ($4::transaction_type IS NULL OR t.type = $4)
+  AND ($5::uuid[] IS NULL OR t.account_id = ANY($5) OR t.destination_account_id = ANY($5))
+  AND ($6::boolean IS NULL OR t.flagged = $6)
+  AND ($7::uuid IS NULL OR t.created_by = $7)
+  AND ($8::boolean IS NULL OR t.posted = $8)
+GROUP BY a.currency
+ORDER BY a.currency;
+
+-- name: SumTransactionsByMember :many
+SELECT t.created_by, u.name, u.email, t.account_id, a.name, a.currency,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total,
+       COUNT(*)
+FROM transactions t
+JOIN users u ON u.id = t.created_by
+JOIN accounts a ON a.id = t.account_id
+WHERE t.household_id = $1
+  AND t.type <> 'transfer'
+  AND ($2::timestamptz IS NULL OR t.transacted_at >= $2)
+  AND ($3::timestamptz IS NULL OR t.transacted_at <= $3)
+GROUP BY t.created_by, u.name, u.email, t.account_id, a.name, a.currency
+ORDER BY u.name, u.email, a.name;
+
+-- name: SumTransactionsByDay :many
+SELECT date_trunc('day', t.transacted_at AT TIME ZONE $2)::date AS day,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0)  AS income_total,
+       COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0) AS expense_total
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+WHERE t.household_id = $1
+  AND t.type <> 'transfer'
+  AND a.currency = $3
+  AND t.transacted_at >= $4
+  AND t.transacted_at < $5
+GROUP BY day
+ORDER BY day;
+
+-- name: SearchTransactions :many
+SELECT * FROM transactions
+WHERE household_id = $1
+  AND (strpos(lower(description), lower($2)) > 0
+    OR strpos(lower(COALESCE(note, '')), lower($2)) > 0
+    OR EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE strpos(lower(tag), lower($2)) > 0))
+ORDER BY transacted_at DESC
+LIMIT $3;
+
+-- name: ListDistinctTags :many
+SELECT DISTINCT tag
+FROM transactions, unnest(tags) AS tag
+WHERE household_id = $1
+  AND ($2::text IS NULL OR starts_with(lower(tag), lower($2)))
+ORDER BY 1;
+
+-- name: RenameTag :execrows
+UPDATE transactions
+SET tags = ARRAY(
+    SELECT tag
+    FROM unnest(array_replace(tags, $2, $3)) WITH ORDINALITY AS u(tag, ord)
+    GROUP BY tag
+    ORDER BY min(ord)
+)
+WHERE household_id = $1 AND $2 = ANY(tags);
+
+-- name: AccountNetEffect :one
+SELECT COALESCE(SUM(CASE
+           WHEN account_id = @account_id AND destination_account_id = @account_id THEN 0
+           WHEN account_id = @account_id AND type = 'income' THEN amount
+           WHEN account_id = @account_id THEN -amount
+           ELSE amount -- incoming transfer
+       END), 0)::decimal AS net
+FROM transactions
+WHERE household_id = @household_id AND (account_id = @account_id OR destination_account_id = @account_id)
+  AND posted;
+
+-- name: SumAccountOutflow :one
+SELECT COALESCE(SUM(amount), 0)::decimal AS total
+FROM transactions
+WHERE household_id = @household_id AND account_id = @account_id
+  AND type IN ('expense', 'transfer')
+  AND transacted_at >= @from AND transacted_at < @to;
+
+-- name: CountTransfersBetween :one
+SELECT COUNT(*) FROM transactions
+WHERE household_id = @household_id AND type = 'transfer'
+  AND ((account_id = @from_account AND destination_account_id = @to_account)
+    OR (account_id = @to_account AND destination_account_id = @from_account));
+
+-- name: ReassignTransactions :execrows
+UPDATE transactions
+SET account_id             = CASE WHEN account_id = @from_account THEN @to_account ELSE account_id END,
+    destination_account_id = CASE WHEN destination_account_id = @from_account THEN @to_account ELSE destination_account_id END
+WHERE household_id = @household_id AND (account_id = @from_account OR destination_account_id = @from_account);
 
 -- name: UpdateTransaction :one
 UPDATE transactions
@@ -38,7 +149,17 @@ SET description            = $3,
     tags                   = $7,
     note                   = $8,
     transacted_at          = $9,
-    type                   = $10
+    type                   = $10,
+    posted                 = sqlc.arg('posted')
+WHERE id = $1 AND household_id = $2
+  AND (sqlc.narg('expected_updated_at')::timestamptz IS NULL OR updated_at = sqlc.narg('expected_updated_at'))
+  AND posted = sqlc.arg('was_posted')
+RETURNING *;
+
+-- name: SetTransactionFlag :one
+UPDATE transactions
+SET flagged     = $3,
+    flag_reason = $4
 WHERE id = $1 AND household_id = $2
 RETURNING *;
 
@@ -47,16 +168,40 @@ DELETE FROM transactions
 WHERE id = $1 AND household_id = $2
 RETURNING *;
 
+-- name: RestoreTransaction :one
+INSERT INTO transactions (
+    id, household_id, type, description, amount,
+    account_id, destination_account_id, tags, note,
+    transacted_at, created_by, created_at,
+    flagged, flag_reason, posted
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING *;
+
+-- name: ListDueTransactions :many
+SELECT id FROM transactions
+WHERE NOT posted AND transacted_at <= $1
+ORDER BY transacted_at
+LIMIT $2;
+
+-- name: PostTransaction :one
+UPDATE transactions SET posted = true
+WHERE id = @id AND NOT posted AND transacted_at <= @now
+RETURNING *;
+
 -- name: ListTransactionsForExport :many
 SELECT
+    t.id,
     t.transacted_at,
     t.description,
     t.amount,
     t.type,
     t.tags,
     t.note,
+    t.account_id,
     a.name  AS account_name,
     a.currency AS account_currency,
+    t.destination_account_id,
     da.name AS destination_account_name
 FROM transactions t
 JOIN accounts a ON a.id = t.account_id
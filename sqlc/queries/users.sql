@@ -1,10 +1,10 @@
 -- name: CreateUser :one
 INSERT INTO users (email, password_hash, name)
-VALUES ($1, $2, $3)
+VALUES (lower($1), $2, $3)
 RETURNING *;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = $1;
+SELECT * FROM users WHERE lower(email) = lower($1);
 
 -- name: GetUserByID :one
 SELECT * FROM users WHERE id = $1;
@@ -12,6 +12,9 @@ SELECT * FROM users WHERE id = $1;
 -- name: UpdateUser :one
 UPDATE users
 SET name = COALESCE(sqlc.narg('name'), name),
-    email = COALESCE(sqlc.narg('email'), email)
+    email = COALESCE(lower(sqlc.narg('email')), email)
 WHERE id = $1
 RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2 WHERE id = $1;
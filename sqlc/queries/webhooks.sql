@@ -0,0 +1,36 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (household_id, url, secret, events)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks WHERE id = $1 AND household_id = $2;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE household_id = $1
+ORDER BY created_at;
+
+-- name: ListWebhooksForEvent :many
+SELECT * FROM webhooks
+WHERE household_id = $1 AND sqlc.arg('event')::text = ANY(events);
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url    = COALESCE(sqlc.narg('url'), url),
+    events = COALESCE(sqlc.narg('events'), events)
+WHERE id = $1 AND household_id = $2
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND household_id = $2;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (webhook_id, event, payload, attempt, status_code, error)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2;